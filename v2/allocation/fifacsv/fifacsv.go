@@ -49,6 +49,10 @@ type fifaRecord struct {
 	DefendingStandingTackle  int `csv:"defending_standing_tackle"`
 	DefendingSlidingTackle   int `csv:"defending_sliding_tackle"`
 	MentalityInterceptions   int `csv:"mentality_interceptions"`
+	GoalkeepingDiving        int `csv:"goalkeeping_diving"`
+	GoalkeepingReflexes      int `csv:"goalkeeping_reflexes"`
+	GoalkeepingPositioning   int `csv:"goalkeeping_positioning"`
+	GoalkeepingKicking       int `csv:"goalkeeping_kicking"`
 }
 
 // LoadCandidates reads the FIFA dataset CSV at path and maps each row to an
@@ -95,6 +99,11 @@ func (rec fifaRecord) toAttributes(r *rand.Rand) soccer.PlayerAttributes {
 		Technique: averagePositive(rec.SkillCurve, rec.SkillFKAccuracy, rec.PowerLongShots),
 		Composure: rec.Mentality,
 		Tackling:  averagePositive(rec.DefendingStandingTackle, rec.DefendingSlidingTackle, rec.MentalityInterceptions),
+
+		Reflexes:     averagePositive(rec.GoalkeepingReflexes, rec.GoalkeepingDiving),
+		Handling:     rec.Goalkeeping,
+		Positioning:  rec.GoalkeepingPositioning,
+		Distribution: rec.GoalkeepingKicking,
 	}
 	// OverallRating is the FIFA `overall` column verbatim. v1 derived a
 	// position-weighted overall here; since the 2026-06 OVR alignment the
@@ -8,6 +8,7 @@ import (
 	soccer "github.com/stein-f/oink-soccer-common/v2"
 	"github.com/stein-f/oink-soccer-common/v2/testdata"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Phase 4 balance harness. Heavy — skipped under -short. Runs many trials
@@ -161,6 +162,72 @@ func TestControlSensitivity_BoundedImpact(t *testing.T) {
 		abs(swing)*100, tolerance*100)
 }
 
+// Larger teams create more chances but each is harder to convert; goals
+// per match must stay within 15% of five-a-side so seven- and
+// eleven-a-side scorelines look like the game players already know.
+func TestTeamSize_GoalsPerMatchMatchFiveASide(t *testing.T) {
+	if testing.Short() {
+		t.Skip("team-size scoreline harness is heavy")
+	}
+	const trials = 2000
+	goalsPerMatch := func(f soccer.FormationType) (goals, chances float64) {
+		home, away := testdata.StrongTeam(f), testdata.StrongTeam(f)
+		for i := 0; i < trials; i++ {
+			events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(int64(i))), home, away)
+			require.NoError(t, err)
+			stats := soccer.CreateGameStats(events)
+			goals += float64(stats.HomeTeamStats.Goals + stats.AwayTeamStats.Goals)
+			chances += float64(stats.HomeTeamStats.Shots + stats.AwayTeamStats.Shots)
+		}
+		return goals / trials, chances / trials
+	}
+
+	fiveGoals, fiveChances := goalsPerMatch(soccer.FormationTypeDiamond)
+	for _, f := range []soccer.FormationType{soccer.FormationTypeArrow, soccer.FormationTypeClassic} {
+		goals, chances := goalsPerMatch(f)
+		t.Logf("%s: %.2f goals, %.2f chances per match (five-a-side %.2f, %.2f)", f, goals, chances, fiveGoals, fiveChances)
+		assert.Greater(t, chances, fiveChances, "%s should create more chances than five-a-side", f)
+		assert.InDelta(t, fiveGoals, goals, 0.15*fiveGoals, "%s goals per match out of the five-a-side range", f)
+	}
+}
+
+// Width and PassingStyle follow the no-free-lunch rule: across every
+// pairing of options (each taking a turn at home, on common seeds), no
+// option may beat another by more than the tolerance. An option that did
+// would be the one every manager picks.
+func TestTactics_NoWidthOrPassingStyleDominates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("tactic balance harness is heavy")
+	}
+	const (
+		trials    = 3000
+		tolerance = 0.03
+	)
+	levers := map[string][]soccer.Tactics{
+		"width": {
+			{}, {Width: soccer.WidthNarrow}, {Width: soccer.WidthWide},
+		},
+		"passing style": {
+			{}, {PassingStyle: soccer.PassingStyleDirect}, {PassingStyle: soccer.PassingStylePossession},
+		},
+	}
+	withTactics := func(tac soccer.Tactics) soccer.GameLineup {
+		l := testdata.StrongTeam(soccer.FormationTypeDiamond)
+		l.Team.Tactics = tac
+		return l
+	}
+	for lever, options := range levers {
+		for i, a := range options {
+			for _, b := range options[i+1:] {
+				aHome := homeWinRate(t, trials, withTactics(a), withTactics(b))
+				bHome := homeWinRate(t, trials, withTactics(b), withTactics(a))
+				t.Logf("%s: %+v at home %.3f, %+v at home %.3f", lever, a, aHome, b, bHome)
+				assert.InDelta(t, aHome, bHome, tolerance, "%s: %+v vs %+v is lopsided", lever, a, b)
+			}
+		}
+	}
+}
+
 func bumpControl(l *soccer.GameLineup, delta int) {
 	for i := range l.Players {
 		if l.Players[i].SelectedPosition == soccer.PlayerPositionMidfield {
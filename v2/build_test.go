@@ -3,6 +3,7 @@ package soccer
 import (
 	"testing"
 
+	"github.com/stein-f/oink-soccer-common/v2/internal/tuning"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -224,7 +225,7 @@ func TestRawDefense_TacklingAttributeShapesOutfieldDefense(t *testing.T) {
 		"enforcer (tackling=92) should out-score a higher-DefenseRating, lower-tackling defender")
 }
 
-// Goalkeepers don't use Tackling — saves come from GoalkeeperRating + Speed.
+// Goalkeepers don't use Tackling — saves come from their save rating + Speed.
 // Setting Tackling on a GK must have no effect.
 func TestRawDefense_GoalkeeperIgnoresTackling(t *testing.T) {
 	gk := PlayerAttributes{
@@ -239,3 +240,136 @@ func TestRawDefense_GoalkeeperIgnoresTackling(t *testing.T) {
 	assert.Equal(t, rawDefense(gk, Tactics{}), rawDefense(withTackling, Tactics{}),
 		"goalkeepers must ignore Tackling — saves are driven by GoalkeeperRating + Speed")
 }
+
+// A keeper without specialist attributes keeps a single rating: both save
+// accessors fall back to GoalkeeperRating, so the save rating is the same
+// against every chance type, and Handling and Distribution are neutral, so
+// rebounds and the chance mix don't move.
+func TestGoalkeeperAttributes_FallBackToGoalkeeperRating(t *testing.T) {
+	gk := PlayerAttributes{
+		GoalkeeperRating: 84,
+		SpeedRating:      70,
+		PrimaryPosition:  PlayerPositionGoalkeeper,
+		Positions:        []PlayerPosition{PlayerPositionGoalkeeper},
+	}
+	assert.Equal(t, 84, gk.EffectiveReflexes())
+	assert.Equal(t, 84, gk.EffectivePositioning())
+	assert.Equal(t, tuning.ReboundHandlingNeutral, gk.EffectiveHandling(), "an unset Handling is neutral, not GoalkeeperRating")
+	assert.Equal(t, tuning.ChanceMixLineupNeutral, gk.EffectiveDistribution(), "an unset Distribution is neutral, not GoalkeeperRating")
+
+	for _, ct := range chanceTypeOrder {
+		assert.Equal(t, 84, goalkeeperSaveRating(gk, ct), "chance type %s", ct)
+		assert.Equal(t, rawDefense(gk, Tactics{}), rawDefenseForChance(gk, Tactics{}, ct), "chance type %s", ct)
+	}
+}
+
+// Keeper archetypes: a shot-stopper (Reflexes) wins close in, a sweeper who
+// reads the game (Positioning) wins from distance. Handling doesn't make
+// saves; it decides rebounds (see TestShotOutcome_HandlingDecidesRebounds).
+func TestRawDefenseForChance_KeeperSpecialistsSplitByChanceType(t *testing.T) {
+	base := PlayerAttributes{
+		GoalkeeperRating: 80,
+		SpeedRating:      70,
+		PrimaryPosition:  PlayerPositionGoalkeeper,
+		Positions:        []PlayerPosition{PlayerPositionGoalkeeper},
+	}
+	shotStopper := base
+	shotStopper.Reflexes, shotStopper.Positioning = 95, 65
+	reader := base
+	reader.Reflexes, reader.Positioning = 65, 95
+
+	for _, ct := range []ChanceType{ChanceTypeOpenPlay, ChanceTypeCross, ChanceTypeCorner, ChanceTypePenalty, ChanceTypeGoalKeeperShot} {
+		assert.Greater(t, rawDefenseForChance(shotStopper, Tactics{}, ct), rawDefenseForChance(reader, Tactics{}, ct),
+			"Reflexes should win on %s", ct)
+	}
+	for _, ct := range []ChanceType{ChanceTypeLongRange, ChanceTypeFreeKick} {
+		assert.Greater(t, rawDefenseForChance(reader, Tactics{}, ct), rawDefenseForChance(shotStopper, Tactics{}, ct),
+			"Positioning should win on %s", ct)
+	}
+
+	safeHands := base
+	safeHands.Handling = 95
+	for _, ct := range chanceTypeOrder {
+		assert.Equal(t, rawDefenseForChance(base, Tactics{}, ct), rawDefenseForChance(safeHands, Tactics{}, ct),
+			"Handling shouldn't change the save on %s", ct)
+	}
+}
+
+// shotGrid runs shotOutcome over an even grid of draws and returns the goal
+// and rebound counts. The grid is offset so no draw lands on p itself.
+func shotGrid(p float64, handling int) (goals, rebounds int, xg float64) {
+	const n = 10000
+	for i := 0; i < n; i++ {
+		goal, rebound, x := shotOutcome((float64(i)+0.37)/n, p, handling)
+		if goal {
+			goals++
+		}
+		if rebound {
+			rebounds++
+		}
+		xg = x
+	}
+	return goals, rebounds, xg
+}
+
+// A keeper without a Handling rating concedes exactly as the engine always
+// has: a goal when the draw is under p, and no rebounds.
+func TestShotOutcome_UnratedKeeperIsLegacy(t *testing.T) {
+	for _, p := range []float64{0.05, 0.3, 0.8} {
+		for i := 0; i < 1000; i++ {
+			u := (float64(i) + 0.5) / 1000
+			goal, rebound, xg := shotOutcome(u, p, 0)
+			assert.Equal(t, u < p, goal)
+			assert.False(t, rebound)
+			assert.Equal(t, p, xg)
+		}
+	}
+}
+
+// At neutral Handling the keeper spills saves, but the goals fall on the
+// same draws as an unrated keeper's: the rating changes how the goals come,
+// not when they do.
+func TestShotOutcome_NeutralHandlingKeepsLegacyGoals(t *testing.T) {
+	for _, p := range []float64{0.05, 0.3, 0.8} {
+		for i := 0; i < 1000; i++ {
+			u := (float64(i) + 0.37) / 1000
+			goal, _, xg := shotOutcome(u, p, tuning.ReboundHandlingNeutral)
+			assert.Equal(t, u < p, goal, "p=%v u=%v", p, u)
+			assert.InDelta(t, p, xg, 1e-12)
+		}
+		_, rebounds, _ := shotGrid(p, tuning.ReboundHandlingNeutral)
+		assert.Positive(t, rebounds, "a neutral keeper still spills some saves")
+	}
+}
+
+// Poor hands spill more saves and concede more; safe hands fewer.
+func TestShotOutcome_HandlingDecidesRebounds(t *testing.T) {
+	const p = 0.3
+	poorGoals, poorRebounds, poorXG := shotGrid(p, 40)
+	neutralGoals, neutralRebounds, neutralXG := shotGrid(p, tuning.ReboundHandlingNeutral)
+	safeGoals, safeRebounds, safeXG := shotGrid(p, 95)
+
+	assert.Greater(t, poorRebounds, neutralRebounds)
+	assert.Greater(t, neutralRebounds, safeRebounds)
+	assert.Greater(t, poorGoals, neutralGoals)
+	assert.Greater(t, neutralGoals, safeGoals)
+	assert.Greater(t, poorXG, neutralXG)
+	assert.Greater(t, neutralXG, safeXG)
+	assert.InDelta(t, poorXG, float64(poorGoals)/10000, 0.001, "xg is the goal probability with the keeper's hands")
+}
+
+// Outfield defense doesn't depend on the chance type, and outfielders
+// ignore the goalkeeper attributes.
+func TestRawDefenseForChance_OutfieldIgnoresChanceType(t *testing.T) {
+	def := PlayerAttributes{
+		DefenseRating:   80,
+		SpeedRating:     75,
+		Reflexes:        99,
+		Positioning:     10,
+		PrimaryPosition: PlayerPositionDefense,
+		Positions:       []PlayerPosition{PlayerPositionDefense},
+	}
+	for _, ct := range chanceTypeOrder {
+		assert.Equal(t, rawDefense(def, Tactics{}), rawDefenseForChance(def, Tactics{}, ct), "chance type %s", ct)
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"math"
 	"math/rand"
 	"sort"
+
+	"github.com/stein-f/oink-soccer-common/v2/internal/tuning"
 )
 
 // chanceTypeProfile shapes how a particular kind of chance plays out:
-//   - BaseWeight is its frequency in chance-type rolls
+//   - BaseWeight is its baseline frequency in chance-type rolls, before the
+//     attacking team's formation, tempo and lineup shift it (see
+//     chanceTypeWeights)
 //   - PositionWeights override the default attacker-pick weights for this type
 //   - AttackBoost multiplies the attacker's effective attack score
 //   - DefenseScale multiplies the defender's effective defense score
@@ -180,29 +184,127 @@ var chanceTypeOrder = []ChanceType{
 	ChanceTypeGoalKeeperShot,
 }
 
-// pickChanceType samples a chance type via weighted random, banning the
-// previous chance type to avoid back-to-back duplicates that look weird in
-// commentary ("CORNER. CORNER. CORNER.").
-func pickChanceType(rand *rand.Rand, previous ChanceType) ChanceType {
-	var totalW uint
-	weights := make([]uint, len(chanceTypeOrder))
+// chanceTypeWeights returns the chance-type weights (aligned with
+// chanceTypeOrder) for a team in possession. Each chance type's BaseWeight
+// is scaled by:
+//
+//   - the formation's ChanceMix (shape — see tuning.FormationChanceMixes),
+//   - the team's tempo (fast ⇒ more breakaways; see tuning.ChanceMixForTempo),
+//   - the team's width and passing style (wide ⇒ more crosses, direct ⇒
+//     more long range and breakaways),
+//   - the opponent's shape: a narrow opponent concedes the flanks, a
+//     possession opponent is caught on the counter,
+//   - the lineup itself: an aerial front line (attackers' EffectiveHeading)
+//     draws more crosses and corners, a technical midfield (midfielders'
+//     EffectiveTechnique) draws more open play and long range, a keeper
+//     with good distribution (EffectiveDistribution) springs more
+//     breakaways, and so does a Ball Winner who wins the ball cleanly
+//     (their EffectiveTackling; a team without one is neutral — see
+//     tuning.BallWinnerBreakawayFactor).
+//
+// The weights depend only on the lineup and its tactics, so the engine
+// computes them once per team rather than per chance.
+func chanceTypeWeights(lineup GameLineup, mix ChanceMix, tactics, opponent Tactics) []float64 {
+	tacticMixes := []ChanceMix{
+		tuning.ChanceMixForTempo(string(tactics.Tempo)),
+		tuning.ChanceMixForWidth(string(tactics.Width)),
+		tuning.ChanceMixForPassingStyle(string(tactics.PassingStyle)),
+		tuning.OpponentChanceMixForWidth(string(opponent.Width)),
+		tuning.OpponentChanceMixForPassingStyle(string(opponent.PassingStyle)),
+	}
+	aerial := tuning.ChanceMixLineupFactor(positionAverage(lineup.Players, PlayerPositionAttack, PlayerAttributes.EffectiveHeading))
+	technical := tuning.ChanceMixLineupFactor(positionAverage(lineup.Players, PlayerPositionMidfield, PlayerAttributes.EffectiveTechnique))
+	distribution := tuning.ChanceMixLineupFactor(positionAverage(lineup.Players, PlayerPositionGoalkeeper, PlayerAttributes.EffectiveDistribution))
+	ballWinner := 1.0
+	if tackling, ok := roleAverage(lineup.Players, PlayerRoleBallWinner, PlayerAttributes.EffectiveTackling); ok {
+		ballWinner = tuning.BallWinnerBreakawayFactor(tackling)
+	}
+
+	weights := make([]float64, len(chanceTypeOrder))
 	for i, ct := range chanceTypeOrder {
-		w := chanceTypeProfiles[ct].BaseWeight
-		if ct == previous {
-			w = 0
+		w := float64(chanceTypeProfiles[ct].BaseWeight)
+		w *= mix.Factor(string(ct))
+		for _, m := range tacticMixes {
+			w *= m.Factor(string(ct))
+		}
+		switch ct {
+		case ChanceTypeCross, ChanceTypeCorner:
+			w *= aerial
+		case ChanceTypeOpenPlay, ChanceTypeLongRange:
+			w *= technical
+		case ChanceTypeGoalKeeperShot:
+			w *= distribution * ballWinner
 		}
 		weights[i] = w
-		totalW += w
 	}
-	if totalW == 0 {
+	return weights
+}
+
+// positionAverage returns the mean of attr over the players selected at pos,
+// falling back to every outfield player when nobody is selected there (The
+// Box fields no midfielders). Returns the neutral rating for an empty lineup
+// so the lineup factor stays 1.0.
+func positionAverage(players []SelectedPlayer, pos PlayerPosition, attr func(PlayerAttributes) int) float64 {
+	var sum, n int
+	for _, p := range players {
+		if p.SelectedPosition == pos {
+			sum += attr(p.Attributes)
+			n++
+		}
+	}
+	if n == 0 {
+		for _, p := range players {
+			if p.SelectedPosition != PlayerPositionGoalkeeper {
+				sum += attr(p.Attributes)
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return tuning.ChanceMixLineupNeutral
+	}
+	return float64(sum) / float64(n)
+}
+
+// roleAverage returns the mean of attr over the players tagged role, and
+// whether anyone is.
+func roleAverage(players []SelectedPlayer, role PlayerRole, attr func(PlayerAttributes) int) (float64, bool) {
+	var sum, n int
+	for _, p := range players {
+		if p.Role == role {
+			sum += attr(p.Attributes)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return float64(sum) / float64(n), true
+}
+
+// pickChanceType samples a chance type from the attacking team's weights
+// (see chanceTypeWeights), banning the previous chance type to avoid
+// back-to-back duplicates that look weird in commentary ("CORNER. CORNER.
+// CORNER.").
+func pickChanceType(rand *rand.Rand, weights []float64, previous ChanceType) ChanceType {
+	var totalW float64
+	for i, ct := range chanceTypeOrder {
+		if ct != previous {
+			totalW += weights[i]
+		}
+	}
+	if totalW <= 0 {
 		return ChanceTypeOpenPlay
 	}
-	pick := uint(rand.Intn(int(totalW)))
-	var cum uint
-	for i, w := range weights {
-		cum += w
+	pick := rand.Float64() * totalW
+	var cum float64
+	for i, ct := range chanceTypeOrder {
+		if ct == previous {
+			continue
+		}
+		cum += weights[i]
 		if pick < cum {
-			return chanceTypeOrder[i]
+			return ct
 		}
 	}
 	return chanceTypeOrder[len(chanceTypeOrder)-1]
@@ -220,13 +322,41 @@ func pickChanceType(rand *rand.Rand, previous ChanceType) ChanceType {
 // excludeID, if non-empty, removes the player with that ID from the pool.
 // Used for corners, where the named SetPieceTaker is delivering the ball and
 // can't also be the one heading it home.
-func pickAttacker(rand *rand.Rand, lineup GameLineup, ct ChanceType, excludeID string) SelectedPlayer {
+//
+// marked is the defending team's man-marking assignment; the marked player
+// is picked less often.
+func pickAttacker(rand *rand.Rand, lineup GameLineup, ct ChanceType, excludeID string, marked marking) SelectedPlayer {
+	players, weights, total := attackerPool(lineup, ct, excludeID, marked)
+	if total == 0 {
+		// Fallback: pick any player not on the exclude list.
+		for _, p := range players {
+			if excludeID == "" || p.ID != excludeID {
+				return p
+			}
+		}
+		return players[rand.Intn(len(players))]
+	}
+	pick := rand.Float64() * total
+	var cum float64
+	for i, w := range weights {
+		cum += w
+		if pick < cum {
+			return players[i]
+		}
+	}
+	return players[len(players)-1]
+}
+
+// attackerPool returns the lineup sorted by ID (for deterministic
+// iteration) with each player's weight in pickAttacker's draw, and the
+// weights' total. AnalyzeLineup reads the same weights as each player's
+// share of a chance type.
+func attackerPool(lineup GameLineup, ct ChanceType, excludeID string, marked marking) ([]SelectedPlayer, []float64, float64) {
 	posWeights := defaultPositionPickWeights
 	if profile, ok := chanceTypeProfiles[ct]; ok && profile.PositionWeights != nil {
 		posWeights = profile.PositionWeights
 	}
 
-	// Sort players by ID for deterministic iteration.
 	players := make([]SelectedPlayer, len(lineup.Players))
 	copy(players, lineup.Players)
 	sort.Slice(players, func(i, j int) bool { return players[i].ID < players[j].ID })
@@ -250,27 +380,11 @@ func pickAttacker(rand *rand.Rand, lineup GameLineup, ct ChanceType, excludeID s
 		if p.Role == PlayerRoleTargetMan && (ct == ChanceTypeCorner || ct == ChanceTypeCross) {
 			w *= 2.0
 		}
+		w *= marked.selectionFactor(p)
 		weights[i] = w
 		total += w
 	}
-	if total == 0 {
-		// Fallback: pick any player not on the exclude list.
-		for _, p := range players {
-			if excludeID == "" || p.ID != excludeID {
-				return p
-			}
-		}
-		return players[rand.Intn(len(players))]
-	}
-	pick := rand.Float64() * total
-	var cum float64
-	for i, w := range weights {
-		cum += w
-		if pick < cum {
-			return players[i]
-		}
-	}
-	return players[len(players)-1]
+	return players, weights, total
 }
 
 // chanceTypeAttackBoost returns the AttackBoost for a chance type, defaulting
@@ -0,0 +1,137 @@
+package soccer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// chance_test.go pins the chance-type mix: the kind of chances a team gets
+// must depend on its shape, tempo and players, not just on fixed weights.
+
+func chanceLineup(formation FormationType, slots []PlayerPosition, attrs PlayerAttributes) GameLineup {
+	players := make([]SelectedPlayer, len(slots))
+	for i, pos := range slots {
+		a := attrs
+		a.PrimaryPosition = pos
+		a.Positions = []PlayerPosition{pos}
+		players[i] = SelectedPlayer{ID: string(rune('1' + i)), SelectedPosition: pos, Attributes: a}
+	}
+	return GameLineup{Team: Team{Formation: formation}, Players: players}
+}
+
+func chanceShare(weights []float64, cts ...ChanceType) float64 {
+	var total, share float64
+	for i, ct := range chanceTypeOrder {
+		total += weights[i]
+		for _, want := range cts {
+			if ct == want {
+				share += weights[i]
+			}
+		}
+	}
+	return share / total
+}
+
+var (
+	boxSlots     = []PlayerPosition{PlayerPositionGoalkeeper, PlayerPositionDefense, PlayerPositionDefense, PlayerPositionAttack, PlayerPositionAttack}
+	diamondSlots = []PlayerPosition{PlayerPositionGoalkeeper, PlayerPositionDefense, PlayerPositionMidfield, PlayerPositionMidfield, PlayerPositionAttack}
+)
+
+// The Box with aerial strikers must see a larger share of crosses + corners
+// than a Diamond of technicians — the original complaint behind the mix.
+func TestChanceTypeWeights_AerialBoxGetsMoreDeliveriesThanTechnicalDiamond(t *testing.T) {
+	aerial := PlayerAttributes{AttackRating: 80, ControlRating: 70, Heading: 92, Technique: 55}
+	technical := PlayerAttributes{AttackRating: 80, ControlRating: 70, Heading: 55, Technique: 92}
+
+	box := chanceLineup(FormationTypeBox, boxSlots, aerial)
+	diamond := chanceLineup(FormationTypeDiamond, diamondSlots, technical)
+
+	boxW := chanceTypeWeights(box, formationChanceMixFor(FormationTypeBox), Tactics{}, Tactics{})
+	diamondW := chanceTypeWeights(diamond, formationChanceMixFor(FormationTypeDiamond), Tactics{}, Tactics{})
+
+	assert.Greater(t,
+		chanceShare(boxW, ChanceTypeCross, ChanceTypeCorner),
+		chanceShare(diamondW, ChanceTypeCross, ChanceTypeCorner),
+		"an aerial Box should see more crosses and corners than a technical Diamond")
+	assert.Greater(t,
+		chanceShare(diamondW, ChanceTypeOpenPlay, ChanceTypeLongRange),
+		chanceShare(boxW, ChanceTypeOpenPlay, ChanceTypeLongRange),
+		"a technical Diamond should see more open play and long range than an aerial Box")
+}
+
+// Same lineup, same shape: only the players' specialist attributes change.
+// Heading must pull crosses/corners up, Technique must pull long range up.
+func TestChanceTypeWeights_LineupShiftsMix(t *testing.T) {
+	base := PlayerAttributes{AttackRating: 75, ControlRating: 70, Heading: 70, Technique: 70}
+	aerial := base
+	aerial.Heading = 95
+	technical := base
+	technical.Technique = 95
+
+	mix := formationChanceMixFor(FormationTypeDiamond)
+	baseW := chanceTypeWeights(chanceLineup(FormationTypeDiamond, diamondSlots, base), mix, Tactics{}, Tactics{})
+	aerialW := chanceTypeWeights(chanceLineup(FormationTypeDiamond, diamondSlots, aerial), mix, Tactics{}, Tactics{})
+	technicalW := chanceTypeWeights(chanceLineup(FormationTypeDiamond, diamondSlots, technical), mix, Tactics{}, Tactics{})
+
+	assert.Greater(t, chanceShare(aerialW, ChanceTypeCross, ChanceTypeCorner), chanceShare(baseW, ChanceTypeCross, ChanceTypeCorner))
+	assert.Greater(t, chanceShare(technicalW, ChanceTypeLongRange), chanceShare(baseW, ChanceTypeLongRange))
+}
+
+// Fast tempo is a transition game — more 1-on-1 breakaways than slow.
+func TestChanceTypeWeights_FastTempoProducesMoreBreakaways(t *testing.T) {
+	lineup := chanceLineup(FormationTypeDiamond, diamondSlots, PlayerAttributes{AttackRating: 75, ControlRating: 70})
+	mix := formationChanceMixFor(FormationTypeDiamond)
+
+	fast := chanceTypeWeights(lineup, mix, Tactics{Tempo: TempoLevelFast}, Tactics{})
+	normal := chanceTypeWeights(lineup, mix, Tactics{}, Tactics{})
+	slow := chanceTypeWeights(lineup, mix, Tactics{Tempo: TempoLevelSlow}, Tactics{})
+
+	assert.Greater(t, chanceShare(fast, ChanceTypeGoalKeeperShot), chanceShare(normal, ChanceTypeGoalKeeperShot))
+	assert.Greater(t, chanceShare(normal, ChanceTypeGoalKeeperShot), chanceShare(slow, ChanceTypeGoalKeeperShot))
+}
+
+// A keeper with good distribution springs breakaways; a poor one doesn't.
+func TestChanceTypeWeights_KeeperDistributionDrivesBreakaways(t *testing.T) {
+	attrs := PlayerAttributes{GoalkeeperRating: 70, AttackRating: 75, ControlRating: 70}
+	mix := formationChanceMixFor(FormationTypeDiamond)
+	withDistribution := func(d int) []float64 {
+		lineup := chanceLineup(FormationTypeDiamond, diamondSlots, attrs)
+		lineup.Players[0].Attributes.Distribution = d
+		return chanceTypeWeights(lineup, mix, Tactics{}, Tactics{})
+	}
+	good, neutral, poor := withDistribution(95), withDistribution(0), withDistribution(40)
+
+	assert.Greater(t, chanceShare(good, ChanceTypeGoalKeeperShot), chanceShare(neutral, ChanceTypeGoalKeeperShot))
+	assert.Greater(t, chanceShare(neutral, ChanceTypeGoalKeeperShot), chanceShare(poor, ChanceTypeGoalKeeperShot))
+}
+
+// A Ball Winner's tackling moves the team's own breakaways; a team without
+// one is neutral.
+func TestChanceTypeWeights_BallWinnerTacklingDrivesBreakaways(t *testing.T) {
+	attrs := PlayerAttributes{AttackRating: 75, ControlRating: 70, DefenseRating: 70, Tackling: 70}
+	mix := formationChanceMixFor(FormationTypeDiamond)
+	with := func(tackling int, role PlayerRole) []float64 {
+		lineup := chanceLineup(FormationTypeDiamond, diamondSlots, attrs)
+		lineup.Players[2].Attributes.Tackling = tackling
+		lineup.Players[2].Role = role
+		return chanceTypeWeights(lineup, mix, Tactics{}, Tactics{})
+	}
+	destroyer, none, poor := with(95, PlayerRoleBallWinner), with(30, PlayerRoleNone), with(30, PlayerRoleBallWinner)
+
+	assert.Greater(t, chanceShare(destroyer, ChanceTypeGoalKeeperShot), chanceShare(none, ChanceTypeGoalKeeperShot))
+	assert.Greater(t, chanceShare(none, ChanceTypeGoalKeeperShot), chanceShare(poor, ChanceTypeGoalKeeperShot))
+}
+
+// pickChanceType must never repeat the previous chance type, whatever the
+// weights say.
+func TestPickChanceType_BansPreviousType(t *testing.T) {
+	weights := make([]float64, len(chanceTypeOrder))
+	weights[0] = 100 // Open Play dominates
+	weights[1] = 1
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		assert.NotEqual(t, ChanceTypeOpenPlay, pickChanceType(r, weights, ChanceTypeOpenPlay))
+	}
+}
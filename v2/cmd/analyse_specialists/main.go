@@ -328,6 +328,9 @@ func playMatchup(rng *rand.Rand, rosters map[allocation.AssetTier]rosterIndex, t
 		}
 		var homeGoals, awayGoals int
 		for _, e := range events {
+			if !e.IsChance() {
+				continue
+			}
 			isHomeEvent := teamTypeOf(e) == soccer.TeamTypeHome
 			eventTier := tierA
 			if isHomeEvent != aIsHome {
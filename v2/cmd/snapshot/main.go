@@ -54,6 +54,7 @@ type InjurySummary struct {
 	PlayerID     string                `json:"player_id"`
 	Severity     soccer.InjurySeverity `json:"severity"`
 	Name         string                `json:"name"`
+	Minute       int                   `json:"minute"`
 	DurationDays int                   `json:"duration_days"`
 }
 
@@ -122,6 +123,9 @@ func generate(seed int64, homeF, awayF soccer.FormationType, homeTag, awayTag st
 		case soccer.GameEventTypeMiss:
 			m := e.GetMissEvent()
 			ev.PlayerID, ev.TeamType = m.PlayerID, m.TeamType
+		case soccer.GameEventTypeInjury:
+			i := e.GetInjuryEvent()
+			ev.PlayerID, ev.TeamType = i.PlayerID, i.TeamType
 		}
 		out.Events = append(out.Events, ev)
 	}
@@ -142,6 +146,7 @@ func summarise(events []soccer.InjuryEvent) []InjurySummary {
 			PlayerID:     e.PlayerID,
 			Severity:     e.Injury.Severity,
 			Name:         e.Injury.Name,
+			Minute:       e.Minute,
 			DurationDays: e.DurationDays,
 		})
 	}
@@ -0,0 +1,362 @@
+// Package commentary turns a simulated match into narrative lines. Every
+// client used to build its own strings from GameEvent.Type and ChanceType,
+// and they drifted; this package is the one place the words live.
+//
+// Lines come from templates keyed by what happened (a LineKind), the chance
+// type, the score state and the stage of the match (a MinuteBucket). The
+// most specific template that exists wins, falling back key by key to a
+// catch-all that every locale must define. When a key has several variants
+// one is picked from a hash of the match seed and the line's position, so
+// the same match reads the same way for everyone, in every client.
+//
+// Generate is a pure function of (match, locale): no clocks, no randomness
+// beyond the seed, no I/O.
+package commentary
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+)
+
+// Locale identifies a commentary language.
+type Locale string
+
+const (
+	LocaleEnglish Locale = "en"
+	LocaleSpanish Locale = "es"
+)
+
+// Locales returns every supported locale.
+func Locales() []Locale {
+	return []Locale{LocaleEnglish, LocaleSpanish}
+}
+
+// ErrUnknownLocale is returned when a locale has no templates.
+var ErrUnknownLocale = errors.New("commentary: unknown locale")
+
+// LineKind is what a line describes.
+type LineKind string
+
+const (
+	LineKindLineup         LineKind = "Lineup"
+	LineKindKickOff        LineKind = "Kick Off"
+	LineKindGoal           LineKind = "Goal"
+	LineKindMiss           LineKind = "Miss"
+	LineKindInjury         LineKind = "Injury"
+	LineKindFullTime       LineKind = "Full Time"
+	LineKindShootoutGoal   LineKind = "Shootout Goal"
+	LineKindShootoutSave   LineKind = "Shootout Save"
+	LineKindShootoutMiss   LineKind = "Shootout Miss" // off target
+	LineKindShootoutResult LineKind = "Shootout Result"
+)
+
+// ScoreState is the score from the acting team's point of view. Goals are
+// classified by what they did to the score; everything else by the score as
+// it stands.
+type ScoreState string
+
+const (
+	ScoreStateOpener      ScoreState = "Opener"      // first goal of the match
+	ScoreStateEqualiser   ScoreState = "Equaliser"   // levels the score
+	ScoreStateGoAhead     ScoreState = "Go Ahead"    // from level to leading
+	ScoreStateExtend      ScoreState = "Extend"      // already leading
+	ScoreStateConsolation ScoreState = "Consolation" // still trailing after it
+	ScoreStateLevel       ScoreState = "Level"
+	ScoreStateLeading     ScoreState = "Leading"
+	ScoreStateTrailing    ScoreState = "Trailing"
+	ScoreStateDecisive    ScoreState = "Decisive" // the kick that settles a shootout
+)
+
+// MinuteBucket is the stage of the match a line falls in.
+type MinuteBucket string
+
+const (
+	MinuteBucketEarly      MinuteBucket = "Early"       // 1-15
+	MinuteBucketFirstHalf  MinuteBucket = "First Half"  // 16-45
+	MinuteBucketSecondHalf MinuteBucket = "Second Half" // 46-75
+	MinuteBucketLate       MinuteBucket = "Late"        // 76+
+)
+
+// BucketFor returns the minute bucket for a match minute.
+func BucketFor(minute int) MinuteBucket {
+	switch {
+	case minute <= 15:
+		return MinuteBucketEarly
+	case minute <= 45:
+		return MinuteBucketFirstHalf
+	case minute <= 75:
+		return MinuteBucketSecondHalf
+	default:
+		return MinuteBucketLate
+	}
+}
+
+// Line is one piece of commentary.
+type Line struct {
+	Kind     LineKind        `json:"kind"`
+	Minute   int             `json:"minute,omitempty"` // zero before kick-off and for shootout lines
+	Kick     int             `json:"kick,omitempty"`   // 1-based shootout kick, zero otherwise
+	TeamType soccer.TeamType `json:"team_type,omitempty"`
+	PlayerID string          `json:"player_id,omitempty"`
+	Text     string          `json:"text"`
+}
+
+// Match is everything a commentary is generated from. Events come from
+// RunGameWithSeed (in order); Shootout is optional.
+type Match struct {
+	Seed     int64
+	Home     soccer.GameLineup
+	Away     soccer.GameLineup
+	Events   []soccer.GameEvent
+	Shootout *soccer.ShootoutResult
+}
+
+// Generate narrates a match in the given locale: each side's lineup and the
+// kick-off, a line per goal, miss and injury, a full-time line, then a line
+// per shootout kick and the shootout result if there was one.
+func Generate(m Match, locale Locale) ([]Line, error) {
+	cat, ok := catalogs[locale]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLocale, locale)
+	}
+	g := &generator{match: m, locale: locale, catalog: cat, names: playerNames(m)}
+
+	for _, team := range []soccer.TeamType{soccer.TeamTypeHome, soccer.TeamTypeAway} {
+		lineup := m.Home
+		if team == soccer.TeamTypeAway {
+			lineup = m.Away
+		}
+		names := make([]string, len(lineup.Players))
+		for i, p := range lineup.Players {
+			names[i] = g.name(team, p.ID)
+		}
+		vars := g.vars(team, "", 0, 0, 0)
+		vars["{formation}"] = string(lineup.Team.Formation)
+		vars["{players}"] = strings.Join(names, ", ")
+		g.add(Line{Kind: LineKindLineup, TeamType: team}, "", "", vars)
+	}
+	g.add(Line{Kind: LineKindKickOff}, "", "", g.vars(soccer.TeamTypeHome, "", 0, 0, 0))
+
+	var home, away int
+	for _, e := range m.Events {
+		switch e.Type {
+		case soccer.GameEventTypeGoal:
+			ev := e.GetGoalEvent()
+			state := goalState(ev.TeamType, home, away)
+			if ev.TeamType == soccer.TeamTypeHome {
+				home++
+			} else {
+				away++
+			}
+			g.add(Line{Kind: LineKindGoal, Minute: e.Minute, TeamType: ev.TeamType, PlayerID: ev.PlayerID},
+				e.ChanceType, state, g.vars(ev.TeamType, ev.PlayerID, e.Minute, home, away))
+		case soccer.GameEventTypeMiss:
+			ev := e.GetMissEvent()
+			g.add(Line{Kind: LineKindMiss, Minute: e.Minute, TeamType: ev.TeamType, PlayerID: ev.PlayerID},
+				e.ChanceType, standingState(ev.TeamType, home, away), g.vars(ev.TeamType, ev.PlayerID, e.Minute, home, away))
+		case soccer.GameEventTypeInjury:
+			ev := e.GetInjuryEvent()
+			vars := g.vars(ev.TeamType, ev.PlayerID, e.Minute, home, away)
+			vars["{injury}"] = InjuryName(locale, ev.Injury)
+			g.add(Line{Kind: LineKindInjury, Minute: e.Minute, TeamType: ev.TeamType, PlayerID: ev.PlayerID},
+				"", standingState(ev.TeamType, home, away), vars)
+		}
+	}
+
+	fullTime := 90
+	if n := len(m.Events); n > 0 && m.Events[n-1].Minute > fullTime {
+		fullTime = m.Events[n-1].Minute
+	}
+	g.add(Line{Kind: LineKindFullTime, Minute: fullTime}, "", standingState(soccer.TeamTypeHome, home, away),
+		g.vars(soccer.TeamTypeHome, "", fullTime, home, away))
+
+	if m.Shootout != nil {
+		g.shootout(*m.Shootout)
+	}
+	return g.lines, nil
+}
+
+type generator struct {
+	match   Match
+	locale  Locale
+	catalog catalog
+	names   map[player]string
+	lines   []Line
+}
+
+// add renders the line's template and appends it.
+func (g *generator) add(line Line, ct soccer.ChanceType, state ScoreState, vars map[string]string) {
+	var bucket MinuteBucket
+	if line.Minute > 0 {
+		bucket = BucketFor(line.Minute)
+	}
+	variants := g.catalog.lookup(line.Kind, ct, state, bucket)
+	text := variants[variant(g.match.Seed, len(g.lines), len(variants))]
+	pairs := make([]string, 0, 2*len(vars))
+	for k, v := range vars {
+		pairs = append(pairs, k, v)
+	}
+	line.Text = strings.NewReplacer(pairs...).Replace(text)
+	g.lines = append(g.lines, line)
+}
+
+func (g *generator) shootout(s soccer.ShootoutResult) {
+	var home, away int
+	for i, k := range s.Kicks {
+		if k.IsGoal() {
+			if k.TeamType == soccer.TeamTypeHome {
+				home++
+			} else {
+				away++
+			}
+		}
+		kind := LineKindShootoutGoal
+		switch {
+		case k.IsGoal():
+		case k.MissType == soccer.PenaltyMissTypeOffTarget:
+			kind = LineKindShootoutMiss
+		default:
+			kind = LineKindShootoutSave
+		}
+		var state ScoreState
+		if i == s.DecisiveKick {
+			state = ScoreStateDecisive
+		}
+		vars := g.vars(k.TeamType, k.TakerID, 0, home, away)
+		vars["{keeper}"] = g.name(opposite(k.TeamType), k.KeeperID)
+		g.add(Line{Kind: kind, Kick: i + 1, TeamType: k.TeamType, PlayerID: k.TakerID}, soccer.ChanceTypePenalty, state, vars)
+	}
+	if s.Winner != "" {
+		g.add(Line{Kind: LineKindShootoutResult, TeamType: s.Winner}, soccer.ChanceTypePenalty, "", g.vars(s.Winner, "", 0, s.HomeScore, s.AwayScore))
+	}
+}
+
+// vars returns the template variables for a line about team (and player).
+func (g *generator) vars(team soccer.TeamType, playerID string, minute, home, away int) map[string]string {
+	own, opp := g.match.Home, g.match.Away
+	if team == soccer.TeamTypeAway {
+		own, opp = opp, own
+	}
+	return map[string]string{
+		"{player}":   g.name(team, playerID),
+		"{team}":     teamName(own),
+		"{opponent}": teamName(opp),
+		"{keeper}":   g.name(opposite(team), goalkeeperID(opp)),
+		"{home}":     teamName(g.match.Home),
+		"{away}":     teamName(g.match.Away),
+		"{minute}":   strconv.Itoa(minute),
+		"{score}":    strconv.Itoa(home) + "-" + strconv.Itoa(away),
+	}
+}
+
+// player identifies a player in a match. IDs are only unique within a
+// team, so both sides can field a "5".
+type player struct {
+	team soccer.TeamType
+	id   string
+}
+
+func (g *generator) name(team soccer.TeamType, id string) string {
+	if n, ok := g.names[player{team, id}]; ok {
+		return n
+	}
+	return id
+}
+
+// playerNames maps each side's players to display names, including
+// shootout substitute keepers. Players without a Name show their ID.
+func playerNames(m Match) map[player]string {
+	out := make(map[player]string)
+	for team, l := range map[soccer.TeamType]soccer.GameLineup{soccer.TeamTypeHome: m.Home, soccer.TeamTypeAway: m.Away} {
+		players := l.Players
+		if l.Shootout.Keeper != nil {
+			players = append(players[:len(players):len(players)], *l.Shootout.Keeper)
+		}
+		for _, p := range players {
+			if p.Name != "" {
+				out[player{team, p.ID}] = p.Name
+			}
+		}
+	}
+	return out
+}
+
+func opposite(team soccer.TeamType) soccer.TeamType {
+	if team == soccer.TeamTypeAway {
+		return soccer.TeamTypeHome
+	}
+	return soccer.TeamTypeAway
+}
+
+func teamName(l soccer.GameLineup) string {
+	if l.Team.CustomName != "" {
+		return l.Team.CustomName
+	}
+	return l.Team.ID
+}
+
+// goalkeeperID returns the lineup's selected goalkeeper, or "" if none.
+func goalkeeperID(l soccer.GameLineup) string {
+	for _, p := range l.Players {
+		if p.SelectedPosition == soccer.PlayerPositionGoalkeeper {
+			return p.ID
+		}
+	}
+	return ""
+}
+
+// goalState classifies a goal by team against the score before it.
+func goalState(team soccer.TeamType, home, away int) ScoreState {
+	own, opp := home, away
+	if team == soccer.TeamTypeAway {
+		own, opp = away, home
+	}
+	switch {
+	case own == 0 && opp == 0:
+		return ScoreStateOpener
+	case own == opp-1:
+		return ScoreStateEqualiser
+	case own == opp:
+		return ScoreStateGoAhead
+	case own > opp:
+		return ScoreStateExtend
+	default:
+		return ScoreStateConsolation
+	}
+}
+
+// standingState is the current score from team's point of view.
+func standingState(team soccer.TeamType, home, away int) ScoreState {
+	own, opp := home, away
+	if team == soccer.TeamTypeAway {
+		own, opp = away, home
+	}
+	switch {
+	case own > opp:
+		return ScoreStateLeading
+	case own < opp:
+		return ScoreStateTrailing
+	default:
+		return ScoreStateLevel
+	}
+}
+
+// variant picks one of n templates from the match seed and the line's
+// position, so inserting a line only re-rolls the lines after it.
+func variant(seed int64, index, n int) int {
+	if n <= 1 {
+		return 0
+	}
+	h := fnv.New64a()
+	var buf [16]byte
+	binary.LittleEndian.PutUint64(buf[:8], uint64(seed))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(index))
+	_, _ = h.Write(buf[:])
+	return int(h.Sum64() % uint64(n))
+}
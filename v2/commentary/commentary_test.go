@@ -0,0 +1,210 @@
+package commentary_test
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/commentary"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var placeholder = regexp.MustCompile(`\{[a-z]+\}`)
+
+// playedMatch runs a real match (and a shootout) to narrate.
+func playedMatch(t *testing.T, seed int64) commentary.Match {
+	t.Helper()
+	home := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	away := testdata.WeakTeam(soccer.FormationTypeBox)
+	home.Team.CustomName = "Oinkers"
+	events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(seed)), home, away)
+	require.NoError(t, err)
+	shootout, err := soccer.RunShootoutWithSeed(rand.New(rand.NewSource(seed)), home, away)
+	require.NoError(t, err)
+	return commentary.Match{Seed: seed, Home: home, Away: away, Events: events, Shootout: &shootout}
+}
+
+func TestGenerate_IsDeterministic(t *testing.T) {
+	m := playedMatch(t, 7)
+
+	a, err := commentary.Generate(m, commentary.LocaleEnglish)
+	require.NoError(t, err)
+	b, err := commentary.Generate(m, commentary.LocaleEnglish)
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+}
+
+func TestGenerate_SeedPicksTheVariants(t *testing.T) {
+	m := playedMatch(t, 7)
+	a, err := commentary.Generate(m, commentary.LocaleEnglish)
+	require.NoError(t, err)
+
+	var differs bool
+	for seed := int64(100); seed < 110 && !differs; seed++ {
+		m.Seed = seed
+		b, err := commentary.Generate(m, commentary.LocaleEnglish)
+		require.NoError(t, err)
+		require.Len(t, b, len(a))
+		for i := range a {
+			differs = differs || a[i].Text != b[i].Text
+		}
+	}
+	assert.True(t, differs, "the same events under another seed should read differently somewhere")
+}
+
+func TestGenerate_CoversEveryEvent(t *testing.T) {
+	m := playedMatch(t, 3)
+
+	lines, err := commentary.Generate(m, commentary.LocaleEnglish)
+	require.NoError(t, err)
+
+	want := 3 // the lineups and the kick-off
+	for _, e := range m.Events {
+		if e.IsChance() || e.IsInjury() {
+			want++
+		}
+	}
+	want++ // full time
+	want += len(m.Shootout.Kicks) + 1
+	require.Len(t, lines, want)
+	assert.Equal(t, commentary.LineKindLineup, lines[0].Kind)
+	assert.Equal(t, soccer.TeamTypeHome, lines[0].TeamType)
+	assert.Equal(t, commentary.LineKindLineup, lines[1].Kind)
+	assert.Equal(t, soccer.TeamTypeAway, lines[1].TeamType)
+	assert.Equal(t, commentary.LineKindKickOff, lines[2].Kind)
+	assert.Equal(t, commentary.LineKindFullTime, lines[len(lines)-len(m.Shootout.Kicks)-2].Kind)
+	assert.Equal(t, commentary.LineKindShootoutResult, lines[len(lines)-1].Kind)
+	assert.Contains(t, lines[len(lines)-len(m.Shootout.Kicks)-2].Text, "Oinkers")
+}
+
+func TestGenerate_EveryPlaceholderIsFilled(t *testing.T) {
+	for _, locale := range commentary.Locales() {
+		for seed := int64(1); seed <= 20; seed++ {
+			lines, err := commentary.Generate(playedMatch(t, seed), locale)
+			require.NoError(t, err)
+			for _, l := range lines {
+				assert.NotEmpty(t, l.Text)
+				assert.False(t, placeholder.MatchString(l.Text), "%s seed %d: %q", locale, seed, l.Text)
+			}
+		}
+	}
+}
+
+func TestGenerate_ScoreStates(t *testing.T) {
+	goal := func(team soccer.TeamType, id string, minute int) soccer.GameEvent {
+		return soccer.GameEvent{Type: soccer.GameEventTypeGoal, ChanceType: soccer.ChanceTypeOpenPlay, Minute: minute,
+			Event: soccer.GoalEvent{TeamType: team, PlayerID: id}}
+	}
+	m := commentary.Match{
+		Home: testdata.StrongTeam(soccer.FormationTypeDiamond),
+		Away: testdata.WeakTeam(soccer.FormationTypeDiamond),
+		Events: []soccer.GameEvent{
+			goal(soccer.TeamTypeHome, "5", 10),
+			goal(soccer.TeamTypeAway, "5", 88),
+		},
+	}
+
+	lines, err := commentary.Generate(m, commentary.LocaleEnglish)
+	require.NoError(t, err)
+
+	require.Len(t, lines, 6)
+	lines = lines[3:]
+	assert.Contains(t, []string{
+		"10' 5 breaks the deadlock for strong! 1-0",
+		"10' The first goal goes to strong, and it's 5. 1-0",
+	}, lines[0].Text)
+	assert.Equal(t, "88' Late drama! 5 snatches an equaliser for "+m.Away.Team.ID+"! 1-1", lines[1].Text)
+	assert.Equal(t, 90, lines[2].Minute)
+	assert.Contains(t, lines[2].Text, "Honours even")
+}
+
+func TestGenerate_TranslatesInjuries(t *testing.T) {
+	injury := soccer.GetAllInjuries()[0]
+	m := commentary.Match{
+		Home: testdata.StrongTeam(soccer.FormationTypeDiamond),
+		Away: testdata.WeakTeam(soccer.FormationTypeDiamond),
+		Events: []soccer.GameEvent{{Type: soccer.GameEventTypeInjury, Minute: 30,
+			Event: soccer.InjuryEvent{TeamType: soccer.TeamTypeHome, PlayerID: "3", Injury: injury, Minute: 30}}},
+	}
+
+	lines, err := commentary.Generate(m, commentary.LocaleSpanish)
+	require.NoError(t, err)
+
+	assert.Equal(t, commentary.LineKindInjury, lines[3].Kind)
+	assert.Contains(t, lines[3].Text, commentary.InjuryName(commentary.LocaleSpanish, injury))
+	assert.NotContains(t, lines[3].Text, injury.Name)
+}
+
+// Player IDs are only unique within a team: when both sides field the same
+// IDs, each line must name the player from the side it is about.
+func TestGenerate_NamesPlayersBySide(t *testing.T) {
+	home := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	away := testdata.WeakTeam(soccer.FormationTypeDiamond)
+	for i := range home.Players {
+		away.Players[i].ID = home.Players[i].ID
+		home.Players[i].Name = "Home " + home.Players[i].ID
+		away.Players[i].Name = "Away " + away.Players[i].ID
+	}
+	var keeper string
+	for _, p := range home.Players {
+		if p.SelectedPosition == soccer.PlayerPositionGoalkeeper {
+			keeper = p.ID
+		}
+	}
+	require.NotEmpty(t, keeper)
+	m := commentary.Match{
+		Home: home,
+		Away: away,
+		Events: []soccer.GameEvent{{Type: soccer.GameEventTypeGoal, ChanceType: soccer.ChanceTypePenalty, Minute: 10,
+			Event: soccer.GoalEvent{TeamType: soccer.TeamTypeAway, PlayerID: keeper}}},
+		Shootout: &soccer.ShootoutResult{Kicks: []soccer.PenaltyOutcome{
+			{TeamType: soccer.TeamTypeAway, TakerID: keeper, KeeperID: keeper, Result: soccer.PenaltyResultMissed, MissType: soccer.PenaltyMissTypeSaved},
+		}, DecisiveKick: -1},
+	}
+
+	lines, err := commentary.Generate(m, commentary.LocaleEnglish)
+	require.NoError(t, err)
+
+	assert.Contains(t, lines[0].Text, "Home 1, Home 2")
+	assert.NotContains(t, lines[0].Text, "Away")
+	assert.Contains(t, lines[1].Text, "Away 1, Away 2")
+	assert.NotContains(t, lines[1].Text, "Home")
+	assert.Contains(t, lines[3].Text, "Away "+keeper)
+	assert.Equal(t, away.Team.ID+": Home "+keeper+" saves from Away "+keeper+"! 0-0", lines[5].Text)
+}
+
+func TestGenerate_UnknownLocale(t *testing.T) {
+	_, err := commentary.Generate(commentary.Match{}, "xx")
+
+	assert.ErrorIs(t, err, commentary.ErrUnknownLocale)
+}
+
+func TestInjuryName_EveryCatalogueEntryIsTranslated(t *testing.T) {
+	for _, locale := range commentary.Locales() {
+		for _, inj := range soccer.GetAllInjuries() {
+			name := commentary.InjuryName(locale, inj)
+			desc := commentary.InjuryDescription(locale, inj)
+			assert.NotEmpty(t, name)
+			assert.NotEmpty(t, desc)
+			if locale != commentary.LocaleEnglish {
+				assert.NotEqual(t, inj.Name, name, "%s: %q is untranslated", locale, inj.Name)
+				assert.NotEqual(t, inj.Description, desc, "%s: %q is untranslated", locale, inj.Name)
+			}
+		}
+	}
+
+	custom := soccer.Injury{Name: "Custom", Description: "Made up."}
+	assert.Equal(t, "Custom", commentary.InjuryName(commentary.LocaleSpanish, custom))
+	assert.Equal(t, "Made up.", commentary.InjuryDescription(commentary.LocaleSpanish, custom))
+}
+
+func TestBucketFor(t *testing.T) {
+	assert.Equal(t, commentary.MinuteBucketEarly, commentary.BucketFor(15))
+	assert.Equal(t, commentary.MinuteBucketFirstHalf, commentary.BucketFor(16))
+	assert.Equal(t, commentary.MinuteBucketSecondHalf, commentary.BucketFor(75))
+	assert.Equal(t, commentary.MinuteBucketLate, commentary.BucketFor(76))
+}
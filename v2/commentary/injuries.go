@@ -0,0 +1,62 @@
+package commentary
+
+import soccer "github.com/stein-f/oink-soccer-common/v2"
+
+// injuryText is a translated catalogue entry.
+type injuryText struct {
+	name        string
+	description string
+}
+
+// injuryTranslations are keyed by the English catalogue name. English is the
+// catalogue itself and has no entry here.
+var injuryTranslations = map[Locale]map[string]injuryText{
+	LocaleSpanish: {
+		"Minor sprain":                  {"Esguince leve", "Se distendió un ligamento en una entrada fallida."},
+		"Squirrel Scare":                {"Susto de ardilla", "Una ardilla saltó al campo y provocó una caída cómica pero desafortunada."},
+		"Pie Burn":                      {"Quemadura de empanada", "Fuera un partido tras comerse una empanada demasiado rápido en el descanso y quemarse el paladar."},
+		"Laugh Attack":                  {"Ataque de risa", "No pudo parar de reír con el chiste de un compañero y acabó con flato."},
+		"Dance-Off Defeat":              {"Derrota en el duelo de baile", "Orgullo herido y tobillo torcido en un duelo de baile improvisado antes del partido."},
+		"Turf Toe":                      {"Dedo del césped", "Se golpeó un dedo del pie contra el césped celebrando un gol."},
+		"Pepper Spray Incident":         {"Incidente picante", "Se frotó los ojos tras tocar comida picante después del partido."},
+		"Selfie Slip":                   {"Resbalón del selfi", "Perdió el equilibrio haciéndose un selfi en el campo tras el partido: una caída inofensiva pero vergonzosa."},
+		"Paparazzi Panic":               {"Pánico paparazzi", "Cegado un momento por el flash de un aficionado demasiado entusiasta tras el partido."},
+		"Locker Room Slippery Floor":    {"Suelo resbaladizo del vestuario", "Resbaló en una zona mojada del vestuario y sufrió un esguince leve."},
+		"Overzealous Autograph Signing": {"Firma de autógrafos excesiva", "Muñeca sobrecargada tras firmar demasiados autógrafos después del partido."},
+		"Powerful sneeze":               {"Estornudo potente", "Lesión de espalda 'fea' provocada por un estornudo muy fuerte."},
+		"Hamstring strain":              {"Rotura de isquiotibiales", "Pequeña rotura en el isquiotibial al esprintar para alcanzar una contra."},
+		"Concussion":                    {"Conmoción cerebral", "Golpe en la cabeza al chocar con un compañero en un remate."},
+		"Mismatched Boots":              {"Botas desparejadas", "Jugó con dos botas izquierdas: ampollas y carreras confusas."},
+		"Charley Horse":                 {"Calambre muscular", "Calambre fuerte por el sobreesfuerzo durante el partido."},
+		"Overenthusiastic Headbutt":     {"Cabezazo entusiasta", "Conmoción leve tras un intento demasiado entusiasta de cabecear el balón."},
+		"Mascot Mishap":                 {"Percance con la mascota", "Chocó con la mascota del equipo en una actuación del descanso y se magulló una costilla."},
+		"Helmet Hair Disaster":          {"Desastre capilar", "Pasó demasiado tiempo arreglándose el pelo bajo el casco y acabó con tortícolis."},
+		"Post-Match Pizza Overload":     {"Atracón de pizza", "Comió demasiada pizza tras el partido y sufrió fuertes dolores de estómago."},
+		"Achilles Tendon Rupture":       {"Rotura del tendón de Aquiles", "Rotura del tendón de Aquiles en una aceleración repentina para perseguir un balón."},
+		"High-five fail":                {"Choque de manos fallido", "Falló un choque de manos y se metió el dedo en el ojo."},
+		"ACL Tear":                      {"Rotura del ligamento cruzado", "Lesión grave de rodilla tras una mala caída."},
+		"Ballistic Banana Slip":         {"Resbalón balístico", "Resbaló con una piel de plátano en el campo y se lesionó la espalda."},
+		"Celebration Injury":            {"Lesión celebrando", "Se dio un tirón en una celebración de gol demasiado efusiva."},
+		"Caught on the Corner Flag":     {"Enredado en el banderín", "Se torció el tobillo al enredarse con el banderín de córner en un giro rápido."},
+		"Post-Match Cramp":              {"Calambre tras el partido", "Calambre fuerte por deshidratación tras el partido que exige una larga recuperación."},
+		"Hydration Hazard":              {"Peligro de hidratación", "Resbaló con agua derramada en el vestuario tras el partido y se dislocó el hombro."},
+	},
+}
+
+// InjuryName returns the injury's name in the given locale. Injuries without
+// a translation (custom injuries, or English) keep their own name.
+func InjuryName(locale Locale, inj soccer.Injury) string {
+	if t, ok := injuryTranslations[locale][inj.Name]; ok {
+		return t.name
+	}
+	return inj.Name
+}
+
+// InjuryDescription returns the injury's description in the given locale,
+// falling back to the injury's own description like InjuryName.
+func InjuryDescription(locale Locale, inj soccer.Injury) string {
+	if t, ok := injuryTranslations[locale][inj.Name]; ok {
+		return t.description
+	}
+	return inj.Description
+}
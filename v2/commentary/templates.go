@@ -0,0 +1,317 @@
+package commentary
+
+import soccer "github.com/stein-f/oink-soccer-common/v2"
+
+// Templates use these variables:
+//
+//	{player}    the acting player (taker, scorer, injured player)
+//	{team}      the acting team; the shootout winner on a result line
+//	{opponent}  the other team
+//	{keeper}    the opponent's goalkeeper; the keeper facing a shootout kick
+//	{home}      the home team
+//	{away}      the away team
+//	{minute}    the match minute
+//	{score}     the score after the event, home first ("2-1")
+//	{injury}    the injury's name, translated (injury lines only)
+//	{formation} the team's formation (lineup lines only)
+//	{players}   the team's players in lineup order (lineup lines only)
+
+// key identifies a set of template variants. Empty fields are wildcards.
+type key struct {
+	kind   LineKind
+	chance soccer.ChanceType
+	state  ScoreState
+	bucket MinuteBucket
+}
+
+type catalog map[key][]string
+
+// lookup returns the variants for the most specific key that exists. The
+// score state outranks the chance type (an equaliser is an equaliser,
+// however it went in), and both outrank the minute bucket.
+func (c catalog) lookup(kind LineKind, ct soccer.ChanceType, st ScoreState, mb MinuteBucket) []string {
+	for _, k := range []key{
+		{kind, ct, st, mb},
+		{kind, ct, st, ""},
+		{kind, "", st, mb},
+		{kind, ct, "", mb},
+		{kind, "", st, ""},
+		{kind, ct, "", ""},
+		{kind, "", "", mb},
+		{kind, "", "", ""},
+	} {
+		if v := c[k]; len(v) > 0 {
+			return v
+		}
+	}
+	return []string{""}
+}
+
+var catalogs = map[Locale]catalog{
+	LocaleEnglish: english,
+	LocaleSpanish: spanish,
+}
+
+var english = catalog{
+	// Before the match.
+	{LineKindLineup, "", "", ""}: {
+		"{team} line up in {formation}: {players}.",
+		"{team} go with {formation}: {players}.",
+	},
+	{LineKindKickOff, "", "", ""}: {
+		"{home} v {away}. We're under way!",
+		"Kick-off: {home} against {away}.",
+	},
+
+	// Goals.
+	{LineKindGoal, "", "", ""}: {
+		"{minute}' GOAL! {player} scores for {team}. {score}",
+		"{minute}' {player} finds the net for {team}. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeOpenPlay, "", ""}: {
+		"{minute}' GOAL! {player} finishes a flowing move for {team}. {score}",
+		"{minute}' {player} picks their spot and scores. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeCross, "", ""}: {
+		"{minute}' GOAL! {player} meets the cross and {team} score. {score}",
+		"{minute}' A whipped ball in and {player} gets there first. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeCorner, "", ""}: {
+		"{minute}' GOAL! {player} rises highest from the corner. {score}",
+		"{minute}' Chaos from the corner and {player} bundles it in. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeLongRange, "", ""}: {
+		"{minute}' What a strike! {player} scores from distance. {score}",
+		"{minute}' {player} lets fly from 30 yards and {keeper} can't reach it. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeFreeKick, "", ""}: {
+		"{minute}' GOAL! {player} curls the free kick over the wall. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypePenalty, "", ""}: {
+		"{minute}' {player} sends {keeper} the wrong way from the spot. {score}",
+		"{minute}' {player} converts the penalty. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeGoalKeeperShot, "", ""}: {
+		"{minute}' GOAL! {player} races clear and slots it past {keeper}. {score}",
+	},
+	{LineKindGoal, "", ScoreStateOpener, ""}: {
+		"{minute}' {player} breaks the deadlock for {team}! {score}",
+		"{minute}' The first goal goes to {team}, and it's {player}. {score}",
+	},
+	{LineKindGoal, "", ScoreStateEqualiser, ""}: {
+		"{minute}' {player} levels it for {team}! {score}",
+	},
+	{LineKindGoal, "", ScoreStateEqualiser, MinuteBucketLate}: {
+		"{minute}' Late drama! {player} snatches an equaliser for {team}! {score}",
+	},
+	{LineKindGoal, "", ScoreStateGoAhead, MinuteBucketLate}: {
+		"{minute}' Late, late goal! {player} puts {team} in front! {score}",
+	},
+	{LineKindGoal, "", ScoreStateConsolation, MinuteBucketLate}: {
+		"{minute}' {player} pulls one back for {team}, but is it too late? {score}",
+	},
+
+	// Misses.
+	{LineKindMiss, "", "", ""}: {
+		"{minute}' {player} goes close for {team}.",
+		"{minute}' Chance for {team}, but {player} can't take it.",
+	},
+	{LineKindMiss, soccer.ChanceTypeOpenPlay, "", ""}: {
+		"{minute}' {player} drags the shot wide.",
+		"{minute}' {player} works the space but {keeper} saves.",
+	},
+	{LineKindMiss, soccer.ChanceTypeCross, "", ""}: {
+		"{minute}' {player} heads the cross over the bar.",
+	},
+	{LineKindMiss, soccer.ChanceTypeCorner, "", ""}: {
+		"{minute}' {player} gets on the end of the corner but can't keep it down.",
+	},
+	{LineKindMiss, soccer.ChanceTypeLongRange, "", ""}: {
+		"{minute}' {player} tries their luck from range. Comfortable for {keeper}.",
+	},
+	{LineKindMiss, soccer.ChanceTypeFreeKick, "", ""}: {
+		"{minute}' {player}'s free kick thuds into the wall.",
+	},
+	{LineKindMiss, soccer.ChanceTypePenalty, "", ""}: {
+		"{minute}' {player} misses the penalty! {keeper} is the hero.",
+	},
+	{LineKindMiss, soccer.ChanceTypeGoalKeeperShot, "", ""}: {
+		"{minute}' {player} is clean through, but {keeper} stands tall.",
+	},
+	{LineKindMiss, "", ScoreStateTrailing, MinuteBucketLate}: {
+		"{minute}' {player} spurns a late chance to rescue {team}.",
+	},
+
+	// Injuries.
+	{LineKindInjury, "", "", ""}: {
+		"{minute}' {player} goes down for {team}: {injury}.",
+		"{minute}' Concern for {team}. {player} is hurt ({injury}).",
+	},
+	{LineKindInjury, "", "", MinuteBucketLate}: {
+		"{minute}' {player} is hobbling late on: {injury}.",
+	},
+
+	// Full time.
+	{LineKindFullTime, "", "", ""}: {
+		"Full time: {home} {score} {away}.",
+	},
+	{LineKindFullTime, "", ScoreStateLevel, ""}: {
+		"Full time: {home} {score} {away}. Honours even.",
+	},
+
+	// Shootouts.
+	{LineKindShootoutGoal, "", "", ""}: {
+		"{team}: {player} scores. {score}",
+		"{team}: {player} buries it. {score}",
+	},
+	{LineKindShootoutSave, "", "", ""}: {
+		"{team}: {keeper} saves from {player}! {score}",
+	},
+	{LineKindShootoutMiss, "", "", ""}: {
+		"{team}: {player} blazes it over! {score}",
+	},
+	{LineKindShootoutGoal, "", ScoreStateDecisive, ""}: {
+		"{player} scores the winning penalty for {team}! {score}",
+	},
+	{LineKindShootoutSave, "", ScoreStateDecisive, ""}: {
+		"{keeper} saves from {player} and {opponent} win the shootout! {score}",
+	},
+	{LineKindShootoutMiss, "", ScoreStateDecisive, ""}: {
+		"{player} puts it wide and {opponent} win the shootout! {score}",
+	},
+	{LineKindShootoutResult, "", "", ""}: {
+		"{team} win {score} on penalties.",
+	},
+}
+
+var spanish = catalog{
+	// Antes del partido.
+	{LineKindLineup, "", "", ""}: {
+		"{team} forma en {formation}: {players}.",
+		"{team} sale con {formation}: {players}.",
+	},
+	{LineKindKickOff, "", "", ""}: {
+		"{home} - {away}. ¡Echa a rodar el balón!",
+		"Comienza el partido: {home} contra {away}.",
+	},
+
+	// Goles.
+	{LineKindGoal, "", "", ""}: {
+		"{minute}' ¡GOL! {player} marca para {team}. {score}",
+		"{minute}' {player} encuentra la red para {team}. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeOpenPlay, "", ""}: {
+		"{minute}' ¡GOL! {player} culmina una gran jugada de {team}. {score}",
+		"{minute}' {player} elige su sitio y marca. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeCross, "", ""}: {
+		"{minute}' ¡GOL! {player} remata el centro y marca {team}. {score}",
+		"{minute}' Centro tenso y {player} llega antes que nadie. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeCorner, "", ""}: {
+		"{minute}' ¡GOL! {player} se eleva más que nadie en el córner. {score}",
+		"{minute}' Lío en el área tras el córner y {player} la empuja. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeLongRange, "", ""}: {
+		"{minute}' ¡Qué golazo! {player} marca desde lejos. {score}",
+		"{minute}' {player} dispara desde 30 metros y {keeper} no llega. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeFreeKick, "", ""}: {
+		"{minute}' ¡GOL! {player} pasa la falta por encima de la barrera. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypePenalty, "", ""}: {
+		"{minute}' {player} engaña a {keeper} desde los once metros. {score}",
+		"{minute}' {player} transforma el penalti. {score}",
+	},
+	{LineKindGoal, soccer.ChanceTypeGoalKeeperShot, "", ""}: {
+		"{minute}' ¡GOL! {player} se escapa y bate a {keeper}. {score}",
+	},
+	{LineKindGoal, "", ScoreStateOpener, ""}: {
+		"{minute}' ¡{player} abre el marcador para {team}! {score}",
+		"{minute}' El primer gol es de {team}, obra de {player}. {score}",
+	},
+	{LineKindGoal, "", ScoreStateEqualiser, ""}: {
+		"{minute}' ¡{player} empata para {team}! {score}",
+	},
+	{LineKindGoal, "", ScoreStateEqualiser, MinuteBucketLate}: {
+		"{minute}' ¡Drama final! {player} rescata el empate para {team}! {score}",
+	},
+	{LineKindGoal, "", ScoreStateGoAhead, MinuteBucketLate}: {
+		"{minute}' ¡Gol en el último suspiro! {player} adelanta a {team}! {score}",
+	},
+	{LineKindGoal, "", ScoreStateConsolation, MinuteBucketLate}: {
+		"{minute}' {player} recorta distancias para {team}, ¿demasiado tarde? {score}",
+	},
+
+	// Ocasiones falladas.
+	{LineKindMiss, "", "", ""}: {
+		"{minute}' {player} roza el gol para {team}.",
+		"{minute}' Ocasión para {team}, pero {player} no acierta.",
+	},
+	{LineKindMiss, soccer.ChanceTypeOpenPlay, "", ""}: {
+		"{minute}' {player} manda el disparo desviado.",
+		"{minute}' {player} encuentra el hueco, pero {keeper} para.",
+	},
+	{LineKindMiss, soccer.ChanceTypeCross, "", ""}: {
+		"{minute}' {player} cabecea el centro por encima del larguero.",
+	},
+	{LineKindMiss, soccer.ChanceTypeCorner, "", ""}: {
+		"{minute}' {player} llega al córner, pero no consigue bajarla.",
+	},
+	{LineKindMiss, soccer.ChanceTypeLongRange, "", ""}: {
+		"{minute}' {player} lo intenta desde lejos. Sin problemas para {keeper}.",
+	},
+	{LineKindMiss, soccer.ChanceTypeFreeKick, "", ""}: {
+		"{minute}' La falta de {player} se estrella en la barrera.",
+	},
+	{LineKindMiss, soccer.ChanceTypePenalty, "", ""}: {
+		"{minute}' ¡{player} falla el penalti! {keeper} es el héroe.",
+	},
+	{LineKindMiss, soccer.ChanceTypeGoalKeeperShot, "", ""}: {
+		"{minute}' {player} se queda solo, pero {keeper} aguanta.",
+	},
+	{LineKindMiss, "", ScoreStateTrailing, MinuteBucketLate}: {
+		"{minute}' {player} desperdicia una ocasión tardía para salvar a {team}.",
+	},
+
+	// Lesiones.
+	{LineKindInjury, "", "", ""}: {
+		"{minute}' {player} cae lesionado en {team}: {injury}.",
+		"{minute}' Preocupación en {team}. {player} está tocado ({injury}).",
+	},
+	{LineKindInjury, "", "", MinuteBucketLate}: {
+		"{minute}' {player} cojea en el tramo final: {injury}.",
+	},
+
+	// Final.
+	{LineKindFullTime, "", "", ""}: {
+		"Final: {home} {score} {away}.",
+	},
+	{LineKindFullTime, "", ScoreStateLevel, ""}: {
+		"Final: {home} {score} {away}. Reparto de puntos.",
+	},
+
+	// Tandas de penaltis.
+	{LineKindShootoutGoal, "", "", ""}: {
+		"{team}: {player} marca. {score}",
+		"{team}: {player} no perdona. {score}",
+	},
+	{LineKindShootoutSave, "", "", ""}: {
+		"{team}: ¡{keeper} para el lanzamiento de {player}! {score}",
+	},
+	{LineKindShootoutMiss, "", "", ""}: {
+		"{team}: ¡{player} la manda a las nubes! {score}",
+	},
+	{LineKindShootoutGoal, "", ScoreStateDecisive, ""}: {
+		"¡{player} marca el penalti decisivo para {team}! {score}",
+	},
+	{LineKindShootoutSave, "", ScoreStateDecisive, ""}: {
+		"¡{keeper} para el lanzamiento de {player} y {opponent} gana la tanda! {score}",
+	},
+	{LineKindShootoutMiss, "", ScoreStateDecisive, ""}: {
+		"¡{player} la manda fuera y {opponent} gana la tanda! {score}",
+	},
+	{LineKindShootoutResult, "", "", ""}: {
+		"{team} gana {score} en los penaltis.",
+	},
+}
@@ -0,0 +1,268 @@
+// Package cup runs single-elimination cup competitions. A bracket is drawn
+// from a seeded team list and played tie by tie through the engine; every
+// tie's random stream is derived from a master Algorand block hash and the
+// tie's ID, so anyone with the hash and the lineups can replay the whole
+// competition.
+//
+//   - The bracket is the next power of two up from the number of teams.
+//     Seeds are placed in the standard order (1 v 16, 8 v 9, … for sixteen)
+//     so the top two seeds can only meet in the final, the top four in the
+//     semi-finals, and so on.
+//   - Byes fill the empty places and go to the top seeds: a seed whose
+//     opponent would be ranked below the last team goes through unplayed.
+//   - A tie is one match. The team from the upper half of the tie is at
+//     home. A draw goes straight to a shootout, drawn from the same stream
+//     and taken with the injuries picked up in the match.
+package cup
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"slices"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/algorand"
+	"github.com/stein-f/oink-soccer-common/v2/league"
+)
+
+var (
+	ErrTooFewTeams   = errors.New("cup: at least two teams are required")
+	ErrDuplicateTeam = errors.New("cup: duplicate team")
+	// ErrMissingLineup is league's sentinel, shared by every competition.
+	ErrMissingLineup   = league.ErrMissingLineup
+	ErrBracketComplete = errors.New("cup: every round has been played")
+)
+
+// Bracket is a whole cup competition, drawn and (as it is played) filled
+// in with results.
+type Bracket struct {
+	Rounds []Round `json:"rounds"`
+	// Winner is the champion's team ID, "" until the final is played.
+	Winner string `json:"winner,omitempty"`
+}
+
+// Round is one stage of the cup. Number is 1-based; the last round is the
+// final.
+type Round struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	Ties   []Tie  `json:"ties"`
+}
+
+// Tie is one knockout match. Teams are by ID; a seed of zero means the
+// place is still to be decided by an earlier tie.
+type Tie struct {
+	ID       string `json:"id"` // "R<round>-<tie>", both 1-based
+	Home     string `json:"home,omitempty"`
+	Away     string `json:"away,omitempty"`
+	HomeSeed int    `json:"home_seed,omitempty"`
+	AwaySeed int    `json:"away_seed,omitempty"`
+	// Bye is set when one side of a first-round tie is empty; the other
+	// side goes through without playing.
+	Bye bool `json:"bye,omitempty"`
+
+	Played    bool                   `json:"played"`
+	HomeGoals int                    `json:"home_goals"`
+	AwayGoals int                    `json:"away_goals"`
+	Events    []soccer.GameEvent     `json:"events,omitempty"`
+	Injuries  *soccer.Injuries       `json:"injuries,omitempty"`
+	Shootout  *soccer.ShootoutResult `json:"shootout,omitempty"`
+	Winner    string                 `json:"winner,omitempty"`
+}
+
+// Draw builds the bracket for teams (by ID) listed in seed order, the top
+// seed first. Byes are resolved immediately; nothing is played.
+func Draw(seeds []string) (*Bracket, error) {
+	if len(seeds) < 2 {
+		return nil, ErrTooFewTeams
+	}
+	seen := make(map[string]bool, len(seeds))
+	for _, id := range seeds {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateTeam, id)
+		}
+		seen[id] = true
+	}
+
+	size := 1 << bits.Len(uint(len(seeds)-1))
+	rounds := bits.Len(uint(size)) - 1
+	b := &Bracket{Rounds: make([]Round, rounds)}
+	for i := range b.Rounds {
+		n := i + 1
+		b.Rounds[i] = Round{Number: n, Name: roundName(size >> i), Ties: make([]Tie, size>>n)}
+		for j := range b.Rounds[i].Ties {
+			b.Rounds[i].Ties[j].ID = fmt.Sprintf("R%d-%d", n, j+1)
+		}
+	}
+
+	order := seedOrder(size)
+	for j := range b.Rounds[0].Ties {
+		tie := &b.Rounds[0].Ties[j]
+		tie.HomeSeed, tie.AwaySeed = order[2*j], order[2*j+1]
+		if tie.AwaySeed > len(seeds) {
+			tie.Home, tie.Bye, tie.Winner = seeds[tie.HomeSeed-1], true, seeds[tie.HomeSeed-1]
+			tie.AwaySeed = 0
+			continue
+		}
+		tie.Home, tie.Away = seeds[tie.HomeSeed-1], seeds[tie.AwaySeed-1]
+	}
+	for j := range b.Rounds[0].Ties {
+		if tie := &b.Rounds[0].Ties[j]; tie.Bye {
+			b.advance(0, j, tie.Home, tie.HomeSeed)
+		}
+	}
+	return b, nil
+}
+
+// seedOrder returns the seeds 1..size in bracket order: adjacent pairs are
+// first-round ties, and each pair's seeds add up to size+1.
+func seedOrder(size int) []int {
+	order := []int{1}
+	for n := 2; n <= size; n *= 2 {
+		next := make([]int, 0, n)
+		for _, s := range order {
+			next = append(next, s, n+1-s)
+		}
+		order = next
+	}
+	return order
+}
+
+func roundName(teams int) string {
+	switch teams {
+	case 2:
+		return "Final"
+	case 4:
+		return "Semi-finals"
+	case 8:
+		return "Quarter-finals"
+	default:
+		return fmt.Sprintf("Round of %d", teams)
+	}
+}
+
+// TieSource returns the random stream a tie is played with: the block hash
+// and the tie ID, hashed together.
+func TieSource(blockHash, tieID string) *rand.Rand {
+	return algorand.SeedFromBlockHash(blockHash + "/" + tieID)
+}
+
+// Next returns the index of the next round with ties to play, or -1 when
+// the cup is over.
+func (b *Bracket) Next() int {
+	for i, round := range b.Rounds {
+		for _, tie := range round.Ties {
+			if tie.Winner == "" {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// PlayRound plays every tie of the next round. lineups are keyed by team
+// ID; a manager can change their lineup between rounds. The whole round is
+// played before the bracket is touched, so an error (a missing lineup is
+// ErrMissingLineup) leaves the bracket as it was, ready to save or retry.
+func (b *Bracket) PlayRound(lineups map[string]soccer.GameLineup, blockHash string) error {
+	i := b.Next()
+	if i < 0 {
+		return ErrBracketComplete
+	}
+	var todo []int
+	for j, tie := range b.Rounds[i].Ties {
+		if tie.Winner != "" {
+			continue
+		}
+		for _, id := range []string{tie.Home, tie.Away} {
+			if _, ok := lineups[id]; !ok {
+				return fmt.Errorf("%w: %q", ErrMissingLineup, id)
+			}
+		}
+		todo = append(todo, j)
+	}
+
+	ties := slices.Clone(b.Rounds[i].Ties)
+	for _, j := range todo {
+		if err := ties[j].play(lineups[ties[j].Home], lineups[ties[j].Away], blockHash); err != nil {
+			return err
+		}
+	}
+	b.Rounds[i].Ties = ties
+	for _, j := range todo {
+		tie := ties[j]
+		seed := tie.HomeSeed
+		if tie.Winner == tie.Away {
+			seed = tie.AwaySeed
+		}
+		b.advance(i, j, tie.Winner, seed)
+	}
+	return nil
+}
+
+// Play plays every remaining round.
+func (b *Bracket) Play(lineups map[string]soccer.GameLineup, blockHash string) error {
+	for b.Next() >= 0 {
+		if err := b.PlayRound(lineups, blockHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// play plays the tie. A draw goes to a shootout, taken by the sides as the
+// match left them: anyone injured during it is still hurt.
+func (t *Tie) play(home, away soccer.GameLineup, blockHash string) error {
+	r := TieSource(blockHash, t.ID)
+	events, injuries, err := soccer.RunGameWithSeed(r, home, away)
+	if err != nil {
+		return fmt.Errorf("cup: tie %s: %w", t.ID, err)
+	}
+	stats := soccer.CreateGameStats(events)
+	t.Played = true
+	t.Events, t.Injuries = events, &injuries
+	t.HomeGoals, t.AwayGoals = stats.HomeTeamStats.Goals, stats.AwayTeamStats.Goals
+
+	winner := soccer.TeamTypeHome
+	switch stats.Outcome(soccer.TeamTypeHome) {
+	case soccer.GameOutcomeTypeLost:
+		winner = soccer.TeamTypeAway
+	case soccer.GameOutcomeTypeDrawn:
+		home, away = carryInjuries(home, away, injuries)
+		shootout, err := soccer.RunShootoutWithSeed(r, home, away)
+		if err != nil {
+			return fmt.Errorf("cup: tie %s: %w", t.ID, err)
+		}
+		t.Shootout = &shootout
+		winner = shootout.Winner
+	}
+	t.Winner = t.Home
+	if winner == soccer.TeamTypeAway {
+		t.Winner = t.Away
+	}
+	return nil
+}
+
+// carryInjuries returns the two sides of a match with the injuries they
+// picked up in it. Player IDs are only unique within a team, so each side
+// takes its own list.
+func carryInjuries(home, away soccer.GameLineup, injuries soccer.Injuries) (soccer.GameLineup, soccer.GameLineup) {
+	return soccer.ApplyInjuries(home, injuries.HomeTeamInjuries), soccer.ApplyInjuries(away, injuries.AwayTeamInjuries)
+}
+
+// advance puts the winner of tie j in round i into its place in the next
+// round, or crowns them if that was the final.
+func (b *Bracket) advance(i, j int, team string, seed int) {
+	if i == len(b.Rounds)-1 {
+		b.Winner = team
+		return
+	}
+	next := &b.Rounds[i+1].Ties[j/2]
+	if j%2 == 0 {
+		next.Home, next.HomeSeed = team, seed
+	} else {
+		next.Away, next.AwaySeed = team, seed
+	}
+}
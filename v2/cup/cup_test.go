@@ -0,0 +1,190 @@
+package cup_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/cup"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraw_KeepsTopSeedsApart(t *testing.T) {
+	seeds, _ := testdata.Field("seed-", 8, 4)
+
+	b, err := cup.Draw(seeds)
+	require.NoError(t, err)
+
+	require.Len(t, b.Rounds, 3)
+	assert.Equal(t, []string{"Quarter-finals", "Semi-finals", "Final"}, []string{b.Rounds[0].Name, b.Rounds[1].Name, b.Rounds[2].Name})
+	var pairs [][2]int
+	for _, tie := range b.Rounds[0].Ties {
+		pairs = append(pairs, [2]int{tie.HomeSeed, tie.AwaySeed})
+	}
+	assert.Equal(t, [][2]int{{1, 8}, {4, 5}, {2, 7}, {3, 6}}, pairs)
+	assert.Equal(t, "R1-1", b.Rounds[0].Ties[0].ID)
+	assert.Equal(t, "R3-1", b.Rounds[2].Ties[0].ID)
+}
+
+func TestDraw_ByesGoToTopSeeds(t *testing.T) {
+	seeds, _ := testdata.Field("seed-", 6, 3)
+
+	b, err := cup.Draw(seeds)
+	require.NoError(t, err)
+
+	var byes []int
+	for _, tie := range b.Rounds[0].Ties {
+		if tie.Bye {
+			byes = append(byes, tie.HomeSeed)
+			assert.Equal(t, tie.Home, tie.Winner)
+			assert.Empty(t, tie.Away)
+		}
+	}
+	assert.ElementsMatch(t, []int{1, 2}, byes)
+	assert.Equal(t, 0, b.Next(), "the rest of round one is still to play")
+	assert.Equal(t, "seed-01", b.Rounds[1].Ties[0].Home, "seed 1 waits in the semi-final")
+	assert.Equal(t, 1, b.Rounds[1].Ties[0].HomeSeed)
+}
+
+func TestPlay_RunsTheWholeCup(t *testing.T) {
+	for _, n := range []int{2, 5, 8, 11} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			seeds, lineups := testdata.Field("seed-", n, n/2)
+			b, err := cup.Draw(seeds)
+			require.NoError(t, err)
+
+			require.NoError(t, b.Play(lineups, testdata.BlockHash))
+
+			assert.Equal(t, -1, b.Next())
+			require.NotEmpty(t, b.Winner)
+			final := b.Rounds[len(b.Rounds)-1].Ties[0]
+			assert.Equal(t, final.Winner, b.Winner)
+			for _, round := range b.Rounds {
+				for _, tie := range round.Ties {
+					if tie.Bye {
+						assert.False(t, tie.Played)
+						continue
+					}
+					assert.True(t, tie.Played)
+					assert.Contains(t, []string{tie.Home, tie.Away}, tie.Winner)
+					assert.Equal(t, tie.Shootout != nil, tie.HomeGoals == tie.AwayGoals, "%s: a shootout only after a draw", tie.ID)
+				}
+			}
+			assert.ErrorIs(t, b.PlayRound(lineups, testdata.BlockHash), cup.ErrBracketComplete)
+		})
+	}
+}
+
+func TestPlay_IsReplayableFromTheBlockHash(t *testing.T) {
+	seeds, lineups := testdata.Field("seed-", 8, 4)
+	a, err := cup.Draw(seeds)
+	require.NoError(t, err)
+	require.NoError(t, a.Play(lineups, testdata.BlockHash))
+
+	b, err := cup.Draw(seeds)
+	require.NoError(t, err)
+	for b.Next() >= 0 {
+		require.NoError(t, b.PlayRound(lineups, testdata.BlockHash))
+	}
+	assert.Equal(t, a, b, "round by round plays the same as all at once")
+
+	// Any one tie can be checked on its own.
+	tie := a.Rounds[1].Ties[0]
+	events, _, err := soccer.RunGameWithSeed(cup.TieSource(testdata.BlockHash, tie.ID), lineups[tie.Home], lineups[tie.Away])
+	require.NoError(t, err)
+	assert.Equal(t, tie.Events, events)
+
+	c, err := cup.Draw(seeds)
+	require.NoError(t, err)
+	require.NoError(t, c.Play(lineups, "ANOTHERHASH"))
+	assert.NotEqual(t, a.Rounds[0].Ties[0].Events, c.Rounds[0].Ties[0].Events)
+}
+
+// A drawn tie's shootout is taken by the sides as the match left them.
+func TestPlay_ShootoutCarriesMatchInjuries(t *testing.T) {
+	seeds, lineups := testdata.Field("seed-", 2, 2)
+	home, away := lineups[seeds[0]], lineups[seeds[1]]
+	home.Team.Tactics.Press, away.Team.Tactics.Press = soccer.PressLevelHigh, soccer.PressLevelHigh
+	lineups[seeds[0]], lineups[seeds[1]] = home, away
+
+	for i := 0; ; i++ {
+		require.Less(t, i, 2000, "no drawn tie whose injuries change the shootout")
+		hash := fmt.Sprintf("%s-%d", testdata.BlockHash, i)
+		b, err := cup.Draw(seeds)
+		require.NoError(t, err)
+		require.NoError(t, b.Play(lineups, hash))
+		tie := b.Rounds[0].Ties[0]
+		if tie.Shootout == nil || len(tie.Injuries.HomeTeamInjuries)+len(tie.Injuries.AwayTeamInjuries) == 0 {
+			continue
+		}
+
+		replay := func(home, away soccer.GameLineup) soccer.ShootoutResult {
+			r := cup.TieSource(hash, tie.ID)
+			_, _, err := soccer.RunGameWithSeed(r, lineups[tie.Home], lineups[tie.Away])
+			require.NoError(t, err)
+			shootout, err := soccer.RunShootoutWithSeed(r, home, away)
+			require.NoError(t, err)
+			return shootout
+		}
+		fresh := replay(lineups[tie.Home], lineups[tie.Away])
+		hurt := replay(soccer.ApplyInjuries(lineups[tie.Home], tie.Injuries.HomeTeamInjuries), soccer.ApplyInjuries(lineups[tie.Away], tie.Injuries.AwayTeamInjuries))
+		if assert.ObjectsAreEqual(fresh, hurt) {
+			continue
+		}
+		assert.Equal(t, hurt, *tie.Shootout)
+		return
+	}
+}
+
+// A round that can't be played in full isn't played at all, so a saved
+// bracket never holds half a round.
+func TestPlayRound_MissingLineupPlaysNothing(t *testing.T) {
+	seeds, lineups := testdata.Field("seed-", 8, 4)
+	delete(lineups, seeds[5]) // seed 6 plays in the last tie of the round
+	b, err := cup.Draw(seeds)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, b.PlayRound(lineups, testdata.BlockHash), cup.ErrMissingLineup)
+	fresh, err := cup.Draw(seeds)
+	require.NoError(t, err)
+	assert.Equal(t, fresh, b)
+}
+
+func TestBracket_JSONRoundTrip(t *testing.T) {
+	seeds, lineups := testdata.Field("seed-", 5, 2)
+	b, err := cup.Draw(seeds)
+	require.NoError(t, err)
+	require.NoError(t, b.PlayRound(lineups, testdata.BlockHash))
+
+	body, err := json.Marshal(b)
+	require.NoError(t, err)
+	var decoded struct {
+		Rounds []struct {
+			Ties []struct {
+				ID     string `json:"id"`
+				Winner string `json:"winner"`
+			} `json:"ties"`
+		} `json:"rounds"`
+	}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+
+	assert.Equal(t, b.Rounds[0].Ties[0].Winner, decoded.Rounds[0].Ties[0].Winner)
+	assert.Equal(t, "R2-1", decoded.Rounds[1].Ties[0].ID)
+}
+
+func TestDraw_Errors(t *testing.T) {
+	_, err := cup.Draw([]string{"a"})
+	assert.ErrorIs(t, err, cup.ErrTooFewTeams)
+
+	_, err = cup.Draw([]string{"a", "b", "a"})
+	assert.ErrorIs(t, err, cup.ErrDuplicateTeam)
+
+	seeds, lineups := testdata.Field("seed-", 4, 2)
+	delete(lineups, seeds[3])
+	b, err := cup.Draw(seeds)
+	require.NoError(t, err)
+	assert.ErrorIs(t, b.Play(lineups, testdata.BlockHash), cup.ErrMissingLineup)
+}
@@ -0,0 +1,159 @@
+package cup
+
+import (
+	"fmt"
+	"math/rand"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+)
+
+// TwoLegRules are the dials of a two-legged tie. The zero value is the
+// modern format: aggregate, extra time, then penalties.
+type TwoLegRules struct {
+	// AwayGoals breaks a level aggregate in favour of the side that scored
+	// more goals away from home, before extra time and again after it
+	// (goals in extra time count as away goals for the visitors).
+	AwayGoals bool `json:"away_goals"`
+}
+
+// Decider is what settled a two-legged tie.
+type Decider string
+
+const (
+	DeciderAggregate Decider = "Aggregate"
+	DeciderAwayGoals Decider = "Away Goals"
+	DeciderExtraTime Decider = "Extra Time" // ahead on aggregate after extra time
+	DeciderShootout  Decider = "Shootout"
+)
+
+// Leg is one match of a two-legged tie, or its extra time.
+type Leg struct {
+	Home      string             `json:"home"`
+	Away      string             `json:"away"`
+	HomeGoals int                `json:"home_goals"`
+	AwayGoals int                `json:"away_goals"`
+	Events    []soccer.GameEvent `json:"events"`
+	Injuries  soccer.Injuries    `json:"injuries"`
+}
+
+// TwoLeggedTie is a tie played home and away. Team A is at home in the
+// first leg, team B in the second.
+type TwoLeggedTie struct {
+	ID     string `json:"id"`
+	First  Leg    `json:"first"`
+	Second Leg    `json:"second"`
+	// ExtraTime is played at the end of the second leg, at B's ground, when
+	// the aggregate is level.
+	ExtraTime *Leg                   `json:"extra_time,omitempty"`
+	Shootout  *soccer.ShootoutResult `json:"shootout,omitempty"`
+
+	AggregateA int     `json:"aggregate_a"`
+	AggregateB int     `json:"aggregate_b"`
+	Winner     string  `json:"winner"`
+	DecidedBy  Decider `json:"decided_by"`
+}
+
+// LegSource returns the random stream a leg is played with (leg 1 or 2),
+// derived from the block hash and the tie ID like TieSource. Extra time and
+// the shootout carry on from the second leg's stream.
+func LegSource(blockHash, tieID string, leg int) *rand.Rand {
+	return TieSource(blockHash, fmt.Sprintf("%s/leg-%d", tieID, leg))
+}
+
+// PlayTwoLegs plays a two-legged tie between a and b, a at home first.
+// Injuries picked up in the first leg are carried into the second (see
+// soccer.ApplyInjuries), and second-leg injuries into extra time and the
+// shootout, each to the side that picked it up: b is the home side in the
+// second leg. The lineups are otherwise the same in both legs.
+//
+// A level aggregate goes to the away-goals rule if it is on, then extra
+// time, the rule again, and finally a shootout.
+func PlayTwoLegs(a, b soccer.GameLineup, blockHash, tieID string, rules TwoLegRules) (TwoLeggedTie, error) {
+	tie := TwoLeggedTie{ID: tieID}
+
+	first, err := playLeg(LegSource(blockHash, tieID, 1), a, b, soccer.RunGameWithSeed)
+	if err != nil {
+		return TwoLeggedTie{}, fmt.Errorf("cup: tie %s, first leg: %w", tieID, err)
+	}
+	tie.First = first
+	a, b = carryInjuries(a, b, first.Injuries)
+
+	r := LegSource(blockHash, tieID, 2)
+	second, err := playLeg(r, b, a, soccer.RunGameWithSeed)
+	if err != nil {
+		return TwoLeggedTie{}, fmt.Errorf("cup: tie %s, second leg: %w", tieID, err)
+	}
+	tie.Second = second
+	b, a = carryInjuries(b, a, second.Injuries)
+
+	tie.AggregateA = first.HomeGoals + second.AwayGoals
+	tie.AggregateB = first.AwayGoals + second.HomeGoals
+	awayA, awayB := second.AwayGoals, first.AwayGoals
+	if tie.decide(DeciderAggregate, awayA, awayB, rules) {
+		return tie, nil
+	}
+
+	extra, err := playLeg(r, b, a, soccer.RunExtraTimeWithSeed)
+	if err != nil {
+		return TwoLeggedTie{}, fmt.Errorf("cup: tie %s, extra time: %w", tieID, err)
+	}
+	tie.ExtraTime = &extra
+	tie.AggregateA += extra.AwayGoals
+	tie.AggregateB += extra.HomeGoals
+	if tie.decide(DeciderExtraTime, awayA+extra.AwayGoals, awayB, rules) {
+		return tie, nil
+	}
+
+	shootout, err := soccer.RunShootoutWithSeed(r, b, a)
+	if err != nil {
+		return TwoLeggedTie{}, fmt.Errorf("cup: tie %s, shootout: %w", tieID, err)
+	}
+	tie.Shootout = &shootout
+	tie.DecidedBy = DeciderShootout
+	tie.Winner = a.Team.ID
+	if shootout.Winner == soccer.TeamTypeHome {
+		tie.Winner = b.Team.ID
+	}
+	return tie, nil
+}
+
+// decide settles the tie on the aggregate, or on away goals if the rule is
+// on, and reports whether it did.
+func (t *TwoLeggedTie) decide(onAggregate Decider, awayA, awayB int, rules TwoLegRules) bool {
+	a, b := t.First.Home, t.First.Away
+	switch {
+	case t.AggregateA != t.AggregateB:
+		t.DecidedBy = onAggregate
+		t.Winner = a
+		if t.AggregateB > t.AggregateA {
+			t.Winner = b
+		}
+	case rules.AwayGoals && awayA != awayB:
+		t.DecidedBy = DeciderAwayGoals
+		t.Winner = a
+		if awayB > awayA {
+			t.Winner = b
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+type runner func(r *rand.Rand, home, away soccer.GameLineup) ([]soccer.GameEvent, soccer.Injuries, error)
+
+func playLeg(r *rand.Rand, home, away soccer.GameLineup, run runner) (Leg, error) {
+	events, injuries, err := run(r, home, away)
+	if err != nil {
+		return Leg{}, err
+	}
+	stats := soccer.CreateGameStats(events)
+	return Leg{
+		Home:      home.Team.ID,
+		Away:      away.Team.ID,
+		HomeGoals: stats.HomeTeamStats.Goals,
+		AwayGoals: stats.AwayTeamStats.Goals,
+		Events:    events,
+		Injuries:  injuries,
+	}, nil
+}
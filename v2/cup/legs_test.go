@@ -0,0 +1,140 @@
+package cup_test
+
+import (
+	"fmt"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/cup"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// evenTie returns two evenly matched sides, so ties go the distance often
+// enough to see every way of settling one.
+func evenTie() (soccer.GameLineup, soccer.GameLineup) {
+	a, b := testdata.StrongTeam(soccer.FormationTypeDiamond), testdata.StrongTeam(soccer.FormationTypeBox)
+	a.Team.ID, b.Team.ID = "a", "b"
+	return a, b
+}
+
+func TestPlayTwoLegs_SwapsVenuesAndAggregates(t *testing.T) {
+	a, b := evenTie()
+
+	tie, err := cup.PlayTwoLegs(a, b, testdata.BlockHash, "R1-1", cup.TwoLegRules{})
+	require.NoError(t, err)
+
+	assert.Equal(t, [2]string{"a", "b"}, [2]string{tie.First.Home, tie.First.Away})
+	assert.Equal(t, [2]string{"b", "a"}, [2]string{tie.Second.Home, tie.Second.Away})
+	extraA, extraB := 0, 0
+	if tie.ExtraTime != nil {
+		extraA, extraB = tie.ExtraTime.AwayGoals, tie.ExtraTime.HomeGoals
+	}
+	assert.Equal(t, tie.First.HomeGoals+tie.Second.AwayGoals+extraA, tie.AggregateA)
+	assert.Equal(t, tie.First.AwayGoals+tie.Second.HomeGoals+extraB, tie.AggregateB)
+	assert.Contains(t, []string{"a", "b"}, tie.Winner)
+
+	again, err := cup.PlayTwoLegs(a, b, testdata.BlockHash, "R1-1", cup.TwoLegRules{})
+	require.NoError(t, err)
+	assert.Equal(t, tie, again)
+}
+
+func TestPlayTwoLegs_EachLegIsReplayable(t *testing.T) {
+	a, b := evenTie()
+	b.Team.Tactics.Press = soccer.PressLevelHigh
+
+	// Find a tie where the first leg leaves someone hurt.
+	var tie cup.TwoLeggedTie
+	for i := 1; ; i++ {
+		require.Less(t, i, 200, "no first leg with an injury")
+		var err error
+		tie, err = cup.PlayTwoLegs(a, b, testdata.BlockHash, fmt.Sprintf("R1-%d", i), cup.TwoLegRules{})
+		require.NoError(t, err)
+		if len(tie.First.Injuries.HomeTeamInjuries)+len(tie.First.Injuries.AwayTeamInjuries) > 0 {
+			break
+		}
+	}
+
+	events, _, err := soccer.RunGameWithSeed(cup.LegSource(testdata.BlockHash, tie.ID, 1), a, b)
+	require.NoError(t, err)
+	assert.Equal(t, tie.First.Events, events)
+
+	// The second leg is played carrying the first leg's injuries: a was at
+	// home in it, b away.
+	events, _, err = soccer.RunGameWithSeed(cup.LegSource(testdata.BlockHash, tie.ID, 2),
+		soccer.ApplyInjuries(b, tie.First.Injuries.AwayTeamInjuries), soccer.ApplyInjuries(a, tie.First.Injuries.HomeTeamInjuries))
+	require.NoError(t, err)
+	assert.Equal(t, tie.Second.Events, events)
+}
+
+// Player IDs are only unique within a team, and the test sides share them.
+// A first-leg injury must hurt only the team it happened to.
+func TestPlayTwoLegs_InjuriesStayWithTheirTeam(t *testing.T) {
+	a, b := evenTie()
+	b.Team.Tactics.Press = soccer.PressLevelHigh
+	require.Equal(t, a.Players[1].ID, b.Players[1].ID, "the sides must share player IDs")
+
+	for i := 1; ; i++ {
+		require.Less(t, i, 500, "no first leg whose injuries land on a shared ID")
+		tie, err := cup.PlayTwoLegs(a, b, testdata.BlockHash, fmt.Sprintf("R1-%d", i), cup.TwoLegRules{})
+		require.NoError(t, err)
+		home, away := tie.First.Injuries.HomeTeamInjuries, tie.First.Injuries.AwayTeamInjuries
+		if len(home)+len(away) == 0 {
+			continue
+		}
+
+		leg := func(b, a soccer.GameLineup) []soccer.GameEvent {
+			events, _, err := soccer.RunGameWithSeed(cup.LegSource(testdata.BlockHash, tie.ID, 2), b, a)
+			require.NoError(t, err)
+			return events
+		}
+		both := append(append([]soccer.InjuryEvent(nil), home...), away...)
+		merged := leg(soccer.ApplyInjuries(b, both), soccer.ApplyInjuries(a, both))
+		own := leg(soccer.ApplyInjuries(b, away), soccer.ApplyInjuries(a, home))
+		if assert.ObjectsAreEqual(merged, own) {
+			continue
+		}
+		assert.Equal(t, own, tie.Second.Events)
+		return
+	}
+}
+
+func TestPlayTwoLegs_DecidersFollowTheRules(t *testing.T) {
+	a, b := evenTie()
+	for _, rules := range []cup.TwoLegRules{{}, {AwayGoals: true}} {
+		t.Run(fmt.Sprintf("away goals %v", rules.AwayGoals), func(t *testing.T) {
+			seen := map[cup.Decider]int{}
+			for i := 1; i <= 150; i++ {
+				tie, err := cup.PlayTwoLegs(a, b, testdata.BlockHash, fmt.Sprintf("R1-%d", i), rules)
+				require.NoError(t, err)
+				seen[tie.DecidedBy]++
+
+				awayA, awayB := tie.Second.AwayGoals, tie.First.AwayGoals
+				level := tie.First.HomeGoals+tie.Second.AwayGoals == tie.First.AwayGoals+tie.Second.HomeGoals
+				assert.Equal(t, level && !(rules.AwayGoals && awayA != awayB), tie.ExtraTime != nil,
+					"%s: extra time only when the legs leave it level", tie.ID)
+				assert.Equal(t, tie.DecidedBy == cup.DeciderShootout, tie.Shootout != nil)
+
+				switch tie.DecidedBy {
+				case cup.DeciderAggregate, cup.DeciderExtraTime:
+					assert.NotEqual(t, tie.AggregateA, tie.AggregateB)
+					assert.Equal(t, tie.AggregateA > tie.AggregateB, tie.Winner == "a")
+				case cup.DeciderAwayGoals:
+					require.True(t, rules.AwayGoals)
+					assert.Equal(t, tie.AggregateA, tie.AggregateB)
+				case cup.DeciderShootout:
+					assert.Equal(t, tie.AggregateA, tie.AggregateB)
+					assert.Equal(t, tie.Shootout.Winner == soccer.TeamTypeHome, tie.Winner == "b", "the shootout is at the second leg's ground")
+				}
+			}
+			assert.Positive(t, seen[cup.DeciderAggregate])
+			assert.Positive(t, seen[cup.DeciderShootout])
+			if rules.AwayGoals {
+				assert.Positive(t, seen[cup.DeciderAwayGoals])
+			} else {
+				assert.Zero(t, seen[cup.DeciderAwayGoals])
+			}
+		})
+	}
+}
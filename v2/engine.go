@@ -1,6 +1,9 @@
 package soccer
 
-import "math/rand"
+import (
+	"fmt"
+	"math/rand"
+)
 
 // RunGameWithSeed simulates a deterministic match between two lineups using
 // the supplied random source. The same (seed, home, away) inputs always
@@ -11,13 +14,81 @@ import "math/rand"
 // carries the new ChanceType field (introduced in v2) so consumers can
 // render richer commentary.
 //
+// Both lineups must name a registered formation (see RegisterFormation),
+// otherwise ErrUnknownFormation is returned. Each lineup must field one
+// player per formation slot, and both formations must be the same size
+// (five-, seven- or eleven-a-side); otherwise ErrLineupSize is returned.
+//
 // Injuries are returned with their DurationDays populated; the absolute
 // expiry timestamp is left as the zero time so the engine itself stays
 // deterministic. Callers should attach a clock with ResolveInjuryExpiry.
+//
+// The draws from r come in a fixed order, pinned by the golden snapshots:
+// the chance count (plus one draw to round it when the sides' tempo makes
+// it fractional), the chance minutes, the item-boost rolls, then for each
+// chance the injury rolls up to its minute (one per uninjured player, and
+// two or three more for each new injury), possession, chance type,
+// attacker (skipped on a free kick or penalty with a named set-piece taker
+// on the pitch) and finish, and last the injury rolls to full time. The
+// attacker draw reads the lineup in player ID order. A replay of a match
+// with a change under the same seed therefore sees the same dice for as
+// long as the change doesn't add or remove a draw, and the same players
+// for as long as it doesn't reorder them.
 func RunGameWithSeed(r *rand.Rand, home GameLineup, away GameLineup) ([]GameEvent, Injuries, error) {
 	if r == nil {
 		return nil, Injuries{}, ErrNilRandSource
 	}
-	events, injuries := simulateMatch(r, home, away)
+	if err := validateLineups(home, away); err != nil {
+		return nil, Injuries{}, err
+	}
+	events, injuries := simulateMatch(r, home, away, regulation)
+	return events, injuries, nil
+}
+
+// validateLineups checks both lineups against their registered formations.
+func validateLineups(home, away GameLineup) error {
+	sizes := [2]int{}
+	for i, l := range []GameLineup{home, away} {
+		size, err := validateLineup(l)
+		if err != nil {
+			return err
+		}
+		sizes[i] = size
+	}
+	if sizes[0] != sizes[1] {
+		return fmt.Errorf("%w: %d-a-side home against %d-a-side away", ErrLineupSize, sizes[0], sizes[1])
+	}
+	return nil
+}
+
+// validateLineup checks one lineup's formation is registered and fields as
+// many players as it has slots, and returns that team size.
+func validateLineup(l GameLineup) (int, error) {
+	cfg, err := LookupFormation(l.Team.Formation)
+	if err != nil {
+		return 0, err
+	}
+	if len(l.Players) != cfg.TeamSize() {
+		return 0, fmt.Errorf("%w: %q fields %d players, %q has %d slots", ErrLineupSize, l.Team.ID, len(l.Players), cfg.FormationType, cfg.TeamSize())
+	}
+	return cfg.TeamSize(), nil
+}
+
+// RunExtraTimeWithSeed plays thirty minutes of extra time between two
+// lineups, typically after a drawn knockout match and before
+// RunShootoutWithSeed. Events are minutes 91-120; a period this short
+// produces about a third of a match's chances.
+//
+// Pass the lineups as they finished normal time: ApplyInjuries carries the
+// match's injuries over. No new injuries are rolled in extra time, so the
+// returned Injuries is always empty; it is there to match RunGameWithSeed.
+func RunExtraTimeWithSeed(r *rand.Rand, home GameLineup, away GameLineup) ([]GameEvent, Injuries, error) {
+	if r == nil {
+		return nil, Injuries{}, ErrNilRandSource
+	}
+	if err := validateLineups(home, away); err != nil {
+		return nil, Injuries{}, err
+	}
+	events, injuries := simulateMatch(r, home, away, extraTime)
 	return events, injuries, nil
 }
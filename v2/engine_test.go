@@ -1,11 +1,13 @@
 package soccer_test
 
 import (
+	"encoding/json"
 	"errors"
 	"math/rand"
 	"testing"
 
 	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -99,6 +101,9 @@ func TestRunGameWithSeed_AllEventsHaveChanceType(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, events)
 	for i, e := range events {
+		if e.IsInjury() {
+			continue
+		}
 		assert.NotEmpty(t, e.ChanceType, "event %d has empty chance type", i)
 	}
 }
@@ -115,6 +120,72 @@ func TestRunGameWithSeed_EventsAreChronological(t *testing.T) {
 	}
 }
 
+// Every injury returned is also in the event stream, at the minute it
+// happened, and no other events are injuries.
+func TestRunGameWithSeed_InjuriesAreInTheEventStream(t *testing.T) {
+	home := strongLineup(soccer.FormationTypeDiamond)
+	away := strongLineup(soccer.FormationTypeBox)
+	away.Team.ID = "team-away"
+	away.Team.Tactics.Press = soccer.PressLevelHigh
+	var seen int
+	for seed := int64(0); seed < 200; seed++ {
+		events, injuries, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(seed)), home, away)
+		require.NoError(t, err)
+		var streamed []soccer.InjuryEvent
+		for _, e := range events {
+			if e.IsInjury() {
+				inj := e.GetInjuryEvent()
+				assert.Equal(t, e.Minute, inj.Minute)
+				assert.Empty(t, e.ChanceType)
+				streamed = append(streamed, inj)
+			}
+		}
+		all := append(append([]soccer.InjuryEvent(nil), injuries.HomeTeamInjuries...), injuries.AwayTeamInjuries...)
+		assert.ElementsMatch(t, all, streamed, "seed %d", seed)
+		for _, inj := range injuries.HomeTeamInjuries {
+			assert.Equal(t, soccer.TeamTypeHome, inj.TeamType)
+			assert.Equal(t, "team-strong", inj.TeamID)
+			assert.Positive(t, inj.Minute)
+		}
+		for _, inj := range injuries.AwayTeamInjuries {
+			assert.Equal(t, soccer.TeamTypeAway, inj.TeamType)
+			assert.Equal(t, "team-away", inj.TeamID)
+		}
+		seen += len(streamed)
+	}
+	assert.NotZero(t, seen, "200 matches should produce at least one injury")
+}
+
+// Extra time is the last half hour: a share of a match's chances, from
+// minute 91, and no new injuries.
+func TestRunExtraTimeWithSeed_IsAShortPeriod(t *testing.T) {
+	home := strongLineup(soccer.FormationTypeDiamond)
+	away := strongLineup(soccer.FormationTypeBox)
+	away.Team.ID = "team-away"
+
+	_, _, err := soccer.RunExtraTimeWithSeed(nil, home, away)
+	assert.ErrorIs(t, err, soccer.ErrNilRandSource)
+
+	var fullTime, extraTime int
+	for seed := int64(0); seed < 200; seed++ {
+		events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(seed)), home, away)
+		require.NoError(t, err)
+		fullTime += len(events)
+
+		events, injuries, err := soccer.RunExtraTimeWithSeed(rand.New(rand.NewSource(seed)), home, away)
+		require.NoError(t, err)
+		assert.Empty(t, injuries.HomeTeamInjuries)
+		assert.Empty(t, injuries.AwayTeamInjuries)
+		for _, e := range events {
+			assert.False(t, e.IsInjury())
+			assert.True(t, e.Minute >= 91 && e.Minute <= 120, "seed %d: minute %d", seed, e.Minute)
+		}
+		extraTime += len(events)
+	}
+	assert.Positive(t, extraTime)
+	assert.Less(t, extraTime, fullTime/2, "extra time has far fewer chances than a match")
+}
+
 // CreateGameStats has no engine dependency — pure aggregator.
 func TestCreateGameStats_AggregatesShotsAndGoals(t *testing.T) {
 	events := []soccer.GameEvent{
@@ -123,6 +194,7 @@ func TestCreateGameStats_AggregatesShotsAndGoals(t *testing.T) {
 		{Type: soccer.GameEventTypeGoal, Event: soccer.GoalEvent{TeamType: soccer.TeamTypeAway, PlayerID: "2"}, Minute: 30},
 		{Type: soccer.GameEventTypeMiss, Event: soccer.MissEvent{TeamType: soccer.TeamTypeAway, PlayerID: "2"}, Minute: 40},
 		{Type: soccer.GameEventTypeMiss, Event: soccer.MissEvent{TeamType: soccer.TeamTypeAway, PlayerID: "2"}, Minute: 50},
+		{Type: soccer.GameEventTypeInjury, Event: soccer.InjuryEvent{TeamType: soccer.TeamTypeAway, PlayerID: "2"}, Minute: 55},
 	}
 
 	stats := soccer.CreateGameStats(events)
@@ -130,3 +202,101 @@ func TestCreateGameStats_AggregatesShotsAndGoals(t *testing.T) {
 	assert.Equal(t, soccer.TeamStats{TeamType: soccer.TeamTypeHome, Shots: 2, Goals: 1}, stats.HomeTeamStats)
 	assert.Equal(t, soccer.TeamStats{TeamType: soccer.TeamTypeAway, Shots: 3, Goals: 1}, stats.AwayTeamStats)
 }
+
+// Every chance carries its goal probability, and CreateGameStats sums them.
+func TestRunGameWithSeed_ChancesCarryXG(t *testing.T) {
+	events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(7)), testdata.StrongTeam(soccer.FormationTypeDiamond), testdata.WeakTeam(soccer.FormationTypeDiamond))
+	require.NoError(t, err)
+
+	var home float64
+	for _, e := range events {
+		if !e.IsChance() {
+			assert.Zero(t, e.XG)
+			continue
+		}
+		assert.Greater(t, e.XG, 0.0)
+		assert.Less(t, e.XG, 1.0)
+		if e.IsGoal() && e.GetGoalEvent().TeamType == soccer.TeamTypeHome || !e.IsGoal() && e.GetMissEvent().TeamType == soccer.TeamTypeHome {
+			home += e.XG
+		}
+	}
+	assert.InDelta(t, home, soccer.CreateGameStats(events).HomeTeamStats.XG, 1e-9)
+}
+
+// Events read back from JSON aggregate like the originals.
+func TestGameEvent_JSONRoundTrip(t *testing.T) {
+	events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(1)), testdata.StrongTeam(soccer.FormationTypeDiamond), testdata.WeakTeam(soccer.FormationTypeDiamond))
+	require.NoError(t, err)
+	events = append(events, soccer.GameEvent{Type: soccer.GameEventTypeInjury, Minute: 90,
+		Event: soccer.InjuryEvent{PlayerID: "3", TeamType: soccer.TeamTypeHome, Injury: soccer.GetAllInjuries()[0], DurationDays: 2}})
+
+	body, err := json.Marshal(events)
+	require.NoError(t, err)
+	var decoded []soccer.GameEvent
+	require.NoError(t, json.Unmarshal(body, &decoded))
+
+	assert.Equal(t, events, decoded)
+	assert.Equal(t, soccer.CreateGameStats(events), soccer.CreateGameStats(decoded))
+}
+
+func TestGameStats_Outcome(t *testing.T) {
+	stats := soccer.GameStats{
+		HomeTeamStats: soccer.TeamStats{TeamType: soccer.TeamTypeHome, Goals: 2},
+		AwayTeamStats: soccer.TeamStats{TeamType: soccer.TeamTypeAway, Goals: 1},
+	}
+	assert.Equal(t, soccer.GameOutcomeTypeWon, stats.Outcome(soccer.TeamTypeHome))
+	assert.Equal(t, soccer.GameOutcomeTypeLost, stats.Outcome(soccer.TeamTypeAway))
+
+	stats.AwayTeamStats.Goals = 2
+	assert.Equal(t, soccer.GameOutcomeTypeDrawn, stats.Outcome(soccer.TeamTypeHome))
+}
+
+// A lineup must field exactly one player per formation slot, and both
+// teams must play the same team size.
+func TestRunGameWithSeed_RejectsMismatchedLineupSizes(t *testing.T) {
+	r := func() *rand.Rand { return rand.New(rand.NewSource(1)) }
+
+	short := testdata.StrongTeam(soccer.FormationTypeArrow)
+	short.Players = short.Players[:5]
+	_, _, err := soccer.RunGameWithSeed(r(), short, testdata.StrongTeam(soccer.FormationTypeArrow))
+	assert.ErrorIs(t, err, soccer.ErrLineupSize)
+
+	_, _, err = soccer.RunGameWithSeed(r(), testdata.StrongTeam(soccer.FormationTypeArrow), testdata.StrongTeam(soccer.FormationTypeDiamond))
+	assert.ErrorIs(t, err, soccer.ErrLineupSize)
+}
+
+// Seven- and eleven-a-side matches run deterministically and only ever
+// credit events to players who are on the pitch.
+func TestRunGameWithSeed_LargerTeamSizes(t *testing.T) {
+	for _, f := range []soccer.FormationType{soccer.FormationTypeArrow, soccer.FormationTypeClassic} {
+		t.Run(string(f), func(t *testing.T) {
+			home := testdata.StrongTeam(f)
+			away := testdata.WeakTeam(f)
+			onPitch := map[string]bool{}
+			for _, p := range append(append([]soccer.SelectedPlayer{}, home.Players...), away.Players...) {
+				onPitch[p.ID] = true
+			}
+
+			for seed := int64(0); seed < 50; seed++ {
+				a, injA, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(seed)), home, away)
+				require.NoError(t, err)
+				b, injB, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(seed)), home, away)
+				require.NoError(t, err)
+				assert.Equal(t, a, b)
+				assert.Equal(t, injA, injB)
+				for _, e := range a {
+					var id string
+					switch {
+					case e.IsGoal():
+						id = e.GetGoalEvent().PlayerID
+					case e.IsInjury():
+						id = e.GetInjuryEvent().PlayerID
+					default:
+						id = e.GetMissEvent().PlayerID
+					}
+					assert.True(t, onPitch[id], "event credited to unknown player %q", id)
+				}
+			}
+		})
+	}
+}
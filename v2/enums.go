@@ -34,13 +34,34 @@ const (
 	FormationTypeDiamond FormationType = "The Diamond"
 	FormationTypeY       FormationType = "The Y"
 	FormationTypeBox     FormationType = "The Box"
+
+	// Seven-a-side.
+	FormationTypeArrow FormationType = "The Arrow" // 2-3-1
+	FormationTypeWall  FormationType = "The Wall"  // 3-2-1
+	FormationTypeFork  FormationType = "The Fork"  // 2-2-2
+
+	// Eleven-a-side.
+	FormationTypeClassic  FormationType = "The Classic"  // 4-4-2
+	FormationTypeFortress FormationType = "The Fortress" // 5-3-2
+	FormationTypeTrident  FormationType = "The Trident"  // 4-3-3
+)
+
+// FormationStyle buckets a formation for the chance-volume table
+// (tuning.FormationChanceRanges). Values match the table's key codes.
+type FormationStyle string
+
+const (
+	FormationStyleAttacking FormationStyle = "ATT"
+	FormationStyleBalanced  FormationStyle = "BAL"
+	FormationStyleDefensive FormationStyle = "DEF"
 )
 
 type GameEventType string
 
 const (
-	GameEventTypeGoal GameEventType = "Goal"
-	GameEventTypeMiss GameEventType = "Miss"
+	GameEventTypeGoal   GameEventType = "Goal"
+	GameEventTypeMiss   GameEventType = "Miss"
+	GameEventTypeInjury GameEventType = "Injury"
 )
 
 type BoostType string
@@ -81,6 +102,28 @@ const (
 	InjurySeverityHigh InjurySeverity = "High Severity"
 )
 
+// Attribute names a PlayerAttributes rating. Values match the attribute's
+// JSON field name.
+type Attribute string
+
+const (
+	AttributeGoalkeeper   Attribute = "goalkeeper_rating"
+	AttributeDefense      Attribute = "defense_rating"
+	AttributeSpeed        Attribute = "speed_rating"
+	AttributeControl      Attribute = "control_rating"
+	AttributeAttack       Attribute = "attack_rating"
+	AttributeWorkRate     Attribute = "work_rate"
+	AttributeFinishing    Attribute = "finishing"
+	AttributeHeading      Attribute = "heading"
+	AttributeTechnique    Attribute = "technique"
+	AttributeComposure    Attribute = "composure"
+	AttributeTackling     Attribute = "tackling"
+	AttributeReflexes     Attribute = "reflexes"
+	AttributeHandling     Attribute = "handling"
+	AttributePositioning  Attribute = "positioning"
+	AttributeDistribution Attribute = "distribution"
+)
+
 type PlayerTag string
 
 const (
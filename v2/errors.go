@@ -7,3 +7,21 @@ import "errors"
 // determinism contract; callers must build their own source explicitly so
 // the seed is visible in their code.
 var ErrNilRandSource = errors.New("soccer: rand source is required")
+
+// ErrUnknownFormation is returned when a lineup names a formation that has
+// not been registered. Before the registry existed the engine silently
+// played unknown formations with the neutral profile, hiding typos.
+var ErrUnknownFormation = errors.New("soccer: unknown formation")
+
+// ErrInvalidFormation is returned by RegisterFormation when a FormationConfig
+// fails validation. The wrapped message names the offending field.
+var ErrInvalidFormation = errors.New("soccer: invalid formation")
+
+// ErrFormationExists is returned by RegisterFormation when a formation with
+// the same FormationType is already registered.
+var ErrFormationExists = errors.New("soccer: formation already registered")
+
+// ErrLineupSize is returned by RunGameWithSeed when a lineup doesn't field
+// exactly one player per formation slot, or when the two teams' formations
+// are different sizes.
+var ErrLineupSize = errors.New("soccer: lineup size does not match formation")
@@ -1,17 +1,75 @@
 package soccer
 
+import "encoding/json"
+
 type GameEvent struct {
 	Type   GameEventType `json:"type"`
-	Event  any           `json:"event"` // GoalEvent | MissEvent
+	Event  any           `json:"event"` // GoalEvent | MissEvent | InjuryEvent
 	Minute int           `json:"minute"`
-	// ChanceType is new in v2. Empty string for events that pre-date the field.
+	// ChanceType is new in v2. Empty string for events that pre-date the
+	// field and for injury events.
 	ChanceType ChanceType `json:"chance_type,omitempty"`
+	// XG is the chance's goal probability when it was taken (expected
+	// goals). Zero for injury events and events that pre-date the field.
+	XG float64 `json:"xg,omitempty"`
+	// Rebound is set when the keeper saved the first shot but spilled it,
+	// and the event's outcome is the shooter's follow-up.
+	Rebound bool `json:"rebound,omitempty"`
+}
+
+// UnmarshalJSON decodes Event into the GoalEvent, MissEvent or InjuryEvent
+// its Type calls for, so a stored match can be read back and aggregated
+// like a fresh one.
+func (g *GameEvent) UnmarshalJSON(data []byte) error {
+	type plain GameEvent
+	var raw struct {
+		plain
+		Event json.RawMessage `json:"event"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*g = GameEvent(raw.plain)
+	if len(raw.Event) == 0 || string(raw.Event) == "null" {
+		g.Event = nil
+		return nil
+	}
+	var err error
+	switch g.Type {
+	case GameEventTypeGoal:
+		var ev GoalEvent
+		err = json.Unmarshal(raw.Event, &ev)
+		g.Event = ev
+	case GameEventTypeMiss:
+		var ev MissEvent
+		err = json.Unmarshal(raw.Event, &ev)
+		g.Event = ev
+	case GameEventTypeInjury:
+		var ev InjuryEvent
+		err = json.Unmarshal(raw.Event, &ev)
+		g.Event = ev
+	default:
+		var ev any
+		err = json.Unmarshal(raw.Event, &ev)
+		g.Event = ev
+	}
+	return err
 }
 
 func (g GameEvent) IsGoal() bool {
 	return g.Type == GameEventTypeGoal
 }
 
+// IsChance reports whether the event is a shot (a goal or a miss) rather
+// than an injury.
+func (g GameEvent) IsChance() bool {
+	return g.Type == GameEventTypeGoal || g.Type == GameEventTypeMiss
+}
+
+func (g GameEvent) IsInjury() bool {
+	return g.Type == GameEventTypeInjury
+}
+
 func (g GameEvent) GetGoalEvent() GoalEvent {
 	return g.Event.(GoalEvent)
 }
@@ -20,6 +78,10 @@ func (g GameEvent) GetMissEvent() MissEvent {
 	return g.Event.(MissEvent)
 }
 
+func (g GameEvent) GetInjuryEvent() InjuryEvent {
+	return g.Event.(InjuryEvent)
+}
+
 type GoalEvent struct {
 	PlayerID string   `json:"player_id"`
 	TeamType TeamType `json:"team_type"`
@@ -39,9 +101,11 @@ type TeamStats struct {
 	TeamType TeamType `json:"team_type"`
 	Shots    int      `json:"shots"`
 	Goals    int      `json:"goals"`
+	XG       float64  `json:"xg"`
 }
 
-// CreateGameStats aggregates a slice of GameEvent into per-team shot/goal totals.
+// CreateGameStats aggregates a slice of GameEvent into per-team shot, goal
+// and xG totals. Injury events are ignored.
 func CreateGameStats(events []GameEvent) GameStats {
 	home := TeamStats{TeamType: TeamTypeHome}
 	away := TeamStats{TeamType: TeamTypeAway}
@@ -58,11 +122,13 @@ func CreateGameStats(events []GameEvent) GameStats {
 		switch team {
 		case TeamTypeHome:
 			home.Shots++
+			home.XG += e.XG
 			if e.IsGoal() {
 				home.Goals++
 			}
 		case TeamTypeAway:
 			away.Shots++
+			away.XG += e.XG
 			if e.IsGoal() {
 				away.Goals++
 			}
@@ -70,3 +136,19 @@ func CreateGameStats(events []GameEvent) GameStats {
 	}
 	return GameStats{HomeTeamStats: home, AwayTeamStats: away}
 }
+
+// Outcome returns the result from team's point of view.
+func (s GameStats) Outcome(team TeamType) GameOutcomeType {
+	own, opp := s.HomeTeamStats.Goals, s.AwayTeamStats.Goals
+	if team == TeamTypeAway {
+		own, opp = opp, own
+	}
+	switch {
+	case own > opp:
+		return GameOutcomeTypeWon
+	case own < opp:
+		return GameOutcomeTypeLost
+	default:
+		return GameOutcomeTypeDrawn
+	}
+}
@@ -0,0 +1,217 @@
+// Package fitness tracks a player's injury history across a season and turns
+// it into the state they take into their next match. The engine only knows
+// about the injury a player is carrying today (SelectedPlayer.Injury); once
+// DurationDays have passed the player would be back to 100% and the static
+// InjuryProne tag would be the only memory of what happened. This package
+// adds the season-long view:
+//
+//   - Match fitness: a player returning from injury comes back below full
+//     speed (how far below depends on the injury's severity) and regains it
+//     over their next few appearances.
+//   - Re-injury risk: for a window after an injury clears, the player is
+//     more likely to pick up another one. The risk decays linearly back to
+//     normal across the window.
+//   - Injury prone: a player with repeated injuries inside a rolling window
+//     is flagged InjuryProne automatically, on top of any static tag.
+//
+// Everything is a pure function of (history, now, rules): no clocks, no
+// randomness. Prepare applies the result to a lineup before kick-off.
+package fitness
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+)
+
+// Spell is one injury in a player's history.
+type Spell struct {
+	// Injured is when the injury happened (the match's kick-off).
+	Injured time.Time
+	// Event is the injury as the engine reported it. If Event.Expires is
+	// zero it is resolved from Injured with soccer.ResolveInjuryExpiry.
+	Event soccer.InjuryEvent
+}
+
+// History is everything this package knows about one player. Injuries and
+// Appearances may be in any order.
+type History struct {
+	PlayerID string
+	Injuries []Spell
+	// Appearances are the kick-off times of matches the player played in.
+	Appearances []time.Time
+}
+
+// Rules are the dials of the fitness model.
+type Rules struct {
+	// ReturnFitness is the match fitness a player comes back at, by the
+	// severity of the injury they are returning from.
+	ReturnFitness map[soccer.InjurySeverity]float64
+	// RampGames is how many appearances it takes to get back to full match
+	// fitness. Fitness rises linearly per appearance.
+	RampGames int
+	// ReinjuryRisk is the injury-odds multiplier on the day an injury
+	// clears. It decays linearly to 1.0 over ReinjuryWindow.
+	ReinjuryRisk   float64
+	ReinjuryWindow time.Duration
+	// A player with at least ProneInjuries injuries inside ProneWindow is
+	// flagged InjuryProne.
+	ProneInjuries int
+	ProneWindow   time.Duration
+}
+
+// DefaultRules returns the rules used by the live game.
+func DefaultRules() Rules {
+	return Rules{
+		ReturnFitness: map[soccer.InjurySeverity]float64{
+			soccer.InjurySeverityLow:  0.95,
+			soccer.InjurySeverityMid:  0.90,
+			soccer.InjurySeverityHigh: 0.80,
+		},
+		RampGames:      3,
+		ReinjuryRisk:   1.8,
+		ReinjuryWindow: 14 * 24 * time.Hour,
+		ProneInjuries:  3,
+		ProneWindow:    60 * 24 * time.Hour,
+	}
+}
+
+// Status is a player's condition at a point in time.
+type Status struct {
+	PlayerID string
+	// Injury is the injury the player is still carrying, nil if none. The
+	// engine applies its reductions when the player is selected anyway.
+	Injury *soccer.InjuryEvent
+	// MatchFitness is 1.0 for a fully match-fit player and lower for one
+	// still working back from an injury.
+	MatchFitness float64
+	// ReinjuryRisk multiplies the player's injury odds; 1.0 is normal.
+	ReinjuryRisk float64
+	// InjuryProne reports whether the history alone makes the player
+	// injury prone. A static InjuryProne tag is not reflected here.
+	InjuryProne bool
+}
+
+// Assess computes a player's status at now from their history. Injuries
+// that happen after now are ignored, so the same history can be replayed at
+// any point in the season.
+func Assess(h History, now time.Time, rules Rules) Status {
+	st := Status{PlayerID: h.PlayerID, MatchFitness: 1.0, ReinjuryRisk: 1.0}
+
+	spells := make([]Spell, 0, len(h.Injuries))
+	for _, s := range h.Injuries {
+		if s.Injured.After(now) {
+			continue
+		}
+		if s.Event.Expires.IsZero() {
+			s.Event.Expires = soccer.ResolveInjuryExpiry(s.Injured, s.Event)
+		}
+		spells = append(spells, s)
+	}
+	// Latest expiry first; ties broken by the later injury.
+	sort.SliceStable(spells, func(i, j int) bool {
+		if !spells[i].Event.Expires.Equal(spells[j].Event.Expires) {
+			return spells[i].Event.Expires.After(spells[j].Event.Expires)
+		}
+		return spells[i].Injured.After(spells[j].Injured)
+	})
+
+	var recent int
+	for _, s := range spells {
+		if now.Sub(s.Injured) < rules.ProneWindow {
+			recent++
+		}
+	}
+	st.InjuryProne = rules.ProneInjuries > 0 && recent >= rules.ProneInjuries
+
+	if len(spells) == 0 {
+		return st
+	}
+	latest := spells[0]
+	if now.Before(latest.Event.Expires) {
+		event := latest.Event
+		st.Injury = &event
+		return st
+	}
+
+	st.MatchFitness = matchFitness(latest, h.Appearances, now, rules)
+	st.ReinjuryRisk = reinjuryRisk(now.Sub(latest.Event.Expires), rules)
+	return st
+}
+
+// matchFitness ramps from the severity's ReturnFitness back to 1.0 across
+// RampGames appearances made since the injury cleared.
+func matchFitness(s Spell, appearances []time.Time, now time.Time, rules Rules) float64 {
+	start, ok := rules.ReturnFitness[s.Event.Injury.Severity]
+	if !ok || start >= 1 || rules.RampGames <= 0 {
+		return 1.0
+	}
+	var games int
+	for _, a := range appearances {
+		if !a.Before(s.Event.Expires) && a.Before(now) {
+			games++
+		}
+	}
+	if games >= rules.RampGames {
+		return 1.0
+	}
+	return start + (1-start)*float64(games)/float64(rules.RampGames)
+}
+
+// reinjuryRisk decays linearly from ReinjuryRisk to 1.0 over ReinjuryWindow.
+func reinjuryRisk(sinceCleared time.Duration, rules Rules) float64 {
+	if rules.ReinjuryRisk <= 1 || rules.ReinjuryWindow <= 0 || sinceCleared >= rules.ReinjuryWindow {
+		return 1.0
+	}
+	left := 1 - float64(sinceCleared)/float64(rules.ReinjuryWindow)
+	return 1 + (rules.ReinjuryRisk-1)*left
+}
+
+// Apply feeds a status into a selected player before kick-off:
+//
+//   - an injury still being carried becomes the player's Injury;
+//   - match fitness below 1.0 scales SpeedRating and WorkRate, the physical
+//     attributes a player short of games is missing;
+//   - ReinjuryRisk becomes the player's InjuryRisk;
+//   - InjuryProne adds the InjuryProne tag.
+//
+// The player's own attribute slices are not modified.
+func Apply(sp soccer.SelectedPlayer, st Status) soccer.SelectedPlayer {
+	if st.Injury != nil {
+		sp.Injury = st.Injury
+	}
+	if st.MatchFitness > 0 && st.MatchFitness < 1 {
+		// Pin WorkRate first: when it is unset it follows SpeedRating.
+		sp.Attributes.WorkRate = scale(sp.Attributes.EffectiveWorkRate(), st.MatchFitness)
+		sp.Attributes.SpeedRating = scale(sp.Attributes.SpeedRating, st.MatchFitness)
+	}
+	if st.ReinjuryRisk > 1 {
+		sp.InjuryRisk = max(sp.InjuryRisk, 1) * st.ReinjuryRisk
+	}
+	if st.InjuryProne && !sp.Attributes.IsInjuryProne() {
+		sp.Attributes.Tag = append(slices.Clip(sp.Attributes.Tag), string(soccer.PlayerTagInjuryProne))
+	}
+	return sp
+}
+
+// Prepare assesses every player in the lineup who has a history and applies
+// the result. Players without a history are left as they are. The input
+// lineup is not modified.
+func Prepare(lineup soccer.GameLineup, histories map[string]History, now time.Time, rules Rules) soccer.GameLineup {
+	players := make([]soccer.SelectedPlayer, len(lineup.Players))
+	for i, sp := range lineup.Players {
+		if h, ok := histories[sp.ID]; ok {
+			sp = Apply(sp, Assess(h, now, rules))
+		}
+		players[i] = sp
+	}
+	lineup.Players = players
+	return lineup
+}
+
+func scale(v int, f float64) int {
+	return int(math.Round(float64(v) * f))
+}
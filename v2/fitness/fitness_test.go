@@ -0,0 +1,161 @@
+package fitness_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/fitness"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var seasonStart = time.Date(2026, 8, 1, 18, 0, 0, 0, time.UTC)
+
+func day(n int) time.Time {
+	return seasonStart.AddDate(0, 0, n)
+}
+
+func spell(injured int, severity soccer.InjurySeverity, days int) fitness.Spell {
+	return fitness.Spell{
+		Injured: day(injured),
+		Event: soccer.InjuryEvent{
+			PlayerID:     "p",
+			DurationDays: days,
+			Injury:       soccer.Injury{Severity: severity, StatsReduction: 0.75, Affects: []soccer.Attribute{soccer.AttributeSpeed}},
+		},
+	}
+}
+
+func TestAssess_NoHistoryIsFullyFit(t *testing.T) {
+	st := fitness.Assess(fitness.History{PlayerID: "p"}, day(10), fitness.DefaultRules())
+
+	assert.Equal(t, fitness.Status{PlayerID: "p", MatchFitness: 1, ReinjuryRisk: 1}, st)
+}
+
+func TestAssess_StillInjuredCarriesTheInjury(t *testing.T) {
+	h := fitness.History{PlayerID: "p", Injuries: []fitness.Spell{spell(0, soccer.InjurySeverityHigh, 5)}}
+
+	st := fitness.Assess(h, day(3), fitness.DefaultRules())
+
+	require.NotNil(t, st.Injury)
+	assert.Equal(t, soccer.ResolveInjuryExpiry(day(0), h.Injuries[0].Event), st.Injury.Expires)
+	assert.Equal(t, 1.0, st.MatchFitness)
+}
+
+func TestAssess_MatchFitnessRampsUpOverGames(t *testing.T) {
+	rules := fitness.DefaultRules()
+	h := fitness.History{PlayerID: "p", Injuries: []fitness.Spell{spell(0, soccer.InjurySeverityHigh, 3)}}
+	// Expires at the end of day 3; back in the side from day 4.
+
+	var prev float64
+	for games := 0; games <= rules.RampGames; games++ {
+		h.Appearances = nil
+		for g := 0; g < games; g++ {
+			h.Appearances = append(h.Appearances, day(4+g))
+		}
+		st := fitness.Assess(h, day(4+games), rules)
+		assert.Nil(t, st.Injury)
+		if games == 0 {
+			assert.Equal(t, rules.ReturnFitness[soccer.InjurySeverityHigh], st.MatchFitness)
+		} else {
+			assert.Greater(t, st.MatchFitness, prev, "fitness must rise with each game back (%d)", games)
+		}
+		prev = st.MatchFitness
+	}
+	assert.Equal(t, 1.0, prev, "fully fit after RampGames appearances")
+}
+
+func TestAssess_WorseInjuriesReturnLessFit(t *testing.T) {
+	rules := fitness.DefaultRules()
+	low := fitness.Assess(fitness.History{Injuries: []fitness.Spell{spell(0, soccer.InjurySeverityLow, 1)}}, day(5), rules)
+	high := fitness.Assess(fitness.History{Injuries: []fitness.Spell{spell(0, soccer.InjurySeverityHigh, 1)}}, day(5), rules)
+
+	assert.Less(t, high.MatchFitness, low.MatchFitness)
+}
+
+func TestAssess_ReinjuryRiskDecaysAfterReturn(t *testing.T) {
+	rules := fitness.DefaultRules()
+	h := fitness.History{Injuries: []fitness.Spell{spell(0, soccer.InjurySeverityMid, 2)}}
+
+	justBack := fitness.Assess(h, day(3), rules)
+	later := fitness.Assess(h, day(10), rules)
+	longAfter := fitness.Assess(h, day(40), rules)
+
+	assert.Greater(t, justBack.ReinjuryRisk, later.ReinjuryRisk)
+	assert.Greater(t, later.ReinjuryRisk, 1.0)
+	assert.LessOrEqual(t, justBack.ReinjuryRisk, rules.ReinjuryRisk)
+	assert.Equal(t, 1.0, longAfter.ReinjuryRisk)
+}
+
+func TestAssess_RepeatedInjuriesFlagInjuryProne(t *testing.T) {
+	rules := fitness.DefaultRules()
+	h := fitness.History{Injuries: []fitness.Spell{
+		spell(0, soccer.InjurySeverityLow, 1),
+		spell(20, soccer.InjurySeverityLow, 1),
+	}}
+	assert.False(t, fitness.Assess(h, day(30), rules).InjuryProne)
+
+	h.Injuries = append(h.Injuries, spell(40, soccer.InjurySeverityLow, 1))
+	assert.True(t, fitness.Assess(h, day(45), rules).InjuryProne)
+	assert.False(t, fitness.Assess(h, day(90), rules).InjuryProne, "old injuries drop out of the window")
+}
+
+func TestAssess_IgnoresFutureInjuriesAndInputOrder(t *testing.T) {
+	rules := fitness.DefaultRules()
+	a := fitness.History{PlayerID: "p", Injuries: []fitness.Spell{
+		spell(0, soccer.InjurySeverityHigh, 5),
+		spell(10, soccer.InjurySeverityLow, 1),
+		spell(30, soccer.InjurySeverityHigh, 5),
+	}, Appearances: []time.Time{day(12), day(13), day(14)}}
+	b := a
+	b.Injuries = []fitness.Spell{a.Injuries[2], a.Injuries[1], a.Injuries[0]}
+	b.Appearances = []time.Time{day(14), day(12), day(13)}
+
+	st := fitness.Assess(a, day(15), rules)
+	assert.Equal(t, st, fitness.Assess(b, day(15), rules))
+	assert.Nil(t, st.Injury, "the day-30 injury hasn't happened yet")
+	assert.Equal(t, 1.0, st.MatchFitness, "three games back from the day-10 knock")
+}
+
+func TestApply(t *testing.T) {
+	sp := soccer.SelectedPlayer{ID: "p", Attributes: soccer.PlayerAttributes{SpeedRating: 80, ControlRating: 70, Tag: []string{"Captain Material"}}}
+	injury := &soccer.InjuryEvent{PlayerID: "p"}
+
+	got := fitness.Apply(sp, fitness.Status{Injury: injury, MatchFitness: 0.8, ReinjuryRisk: 1.5, InjuryProne: true})
+
+	assert.Same(t, injury, got.Injury)
+	assert.Equal(t, 64, got.Attributes.SpeedRating)
+	assert.Equal(t, 64, got.Attributes.EffectiveWorkRate())
+	assert.Equal(t, 70, got.Attributes.ControlRating)
+	assert.Equal(t, 1.5, got.InjuryRisk)
+	assert.True(t, got.Attributes.IsInjuryProne())
+	assert.Equal(t, []string{"Captain Material"}, sp.Attributes.Tag, "the input's tags are not modified")
+
+	fit := fitness.Apply(sp, fitness.Status{MatchFitness: 1, ReinjuryRisk: 1})
+	assert.Equal(t, sp, fit, "a fit player is unchanged")
+}
+
+func TestPrepare_FeedsTheEngine(t *testing.T) {
+	home := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	away := testdata.WeakTeam(soccer.FormationTypeDiamond)
+	striker := home.Players[4].ID
+	histories := map[string]fitness.History{
+		striker: {PlayerID: striker, Injuries: []fitness.Spell{spell(0, soccer.InjurySeverityHigh, 5)}},
+	}
+
+	prepared := fitness.Prepare(home, histories, day(2), fitness.DefaultRules())
+
+	require.NotNil(t, prepared.Players[4].Injury)
+	assert.Nil(t, home.Players[4].Injury, "the input lineup is not modified")
+	assert.Equal(t, home.Players[:4], prepared.Players[:4])
+
+	// Deterministic: the prepared lineup plays like any other.
+	a, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(1)), prepared, away)
+	require.NoError(t, err)
+	b, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(1)), fitness.Prepare(home, histories, day(2), fitness.DefaultRules()), away)
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+}
@@ -0,0 +1,83 @@
+// Package forecast estimates how a match is likely to go by playing it
+// many times with the engine.
+//
+// Match k of a forecast is played on its own stream, seeded with seed+k.
+// Two forecasts with the same seed therefore play the same dice, and the
+// difference between them comes from the lineups rather than from luck
+// (common random numbers). Compare variants of a lineup with one seed; use
+// another seed for a fresh sample.
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+)
+
+var ErrNoMatches = errors.New("forecast: at least one match is required")
+
+// Forecast is the outcome of a run of matches from the home side's view.
+type Forecast struct {
+	Matches int `json:"matches"`
+	// Win, Draw and Loss are the shares of the matches, adding up to 1.
+	Win  float64 `json:"win"`
+	Draw float64 `json:"draw"`
+	Loss float64 `json:"loss"`
+	// Goals and xG are per match.
+	GoalsFor     float64 `json:"goals_for"`
+	GoalsAgainst float64 `json:"goals_against"`
+	XGFor        float64 `json:"xg_for"`
+	XGAgainst    float64 `json:"xg_against"`
+}
+
+// Points is the home side's expected score: a win is 1, a draw ½.
+func (f Forecast) Points() float64 {
+	return f.Win + f.Draw/2
+}
+
+// Reversed is the forecast from the away side's view.
+func (f Forecast) Reversed() Forecast {
+	f.Win, f.Loss = f.Loss, f.Win
+	f.GoalsFor, f.GoalsAgainst = f.GoalsAgainst, f.GoalsFor
+	f.XGFor, f.XGAgainst = f.XGAgainst, f.XGFor
+	return f
+}
+
+// Simulate plays home against away n times. It returns the engine's error
+// if the lineups can't play each other.
+func Simulate(seed int64, home, away soccer.GameLineup, n int) (Forecast, error) {
+	if n < 1 {
+		return Forecast{}, fmt.Errorf("%w: %d", ErrNoMatches, n)
+	}
+	f := Forecast{Matches: n}
+	for k := range n {
+		events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(seed+int64(k))), home, away)
+		if err != nil {
+			return Forecast{}, err
+		}
+		stats := soccer.CreateGameStats(events)
+		switch stats.Outcome(soccer.TeamTypeHome) {
+		case soccer.GameOutcomeTypeWon:
+			f.Win++
+		case soccer.GameOutcomeTypeDrawn:
+			f.Draw++
+		default:
+			f.Loss++
+		}
+		f.GoalsFor += float64(stats.HomeTeamStats.Goals)
+		f.GoalsAgainst += float64(stats.AwayTeamStats.Goals)
+		f.XGFor += stats.HomeTeamStats.XG
+		f.XGAgainst += stats.AwayTeamStats.XG
+	}
+	total := float64(n)
+	f.Win /= total
+	f.Draw /= total
+	f.Loss /= total
+	f.GoalsFor /= total
+	f.GoalsAgainst /= total
+	f.XGFor /= total
+	f.XGAgainst /= total
+	return f, nil
+}
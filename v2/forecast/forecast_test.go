@@ -0,0 +1,41 @@
+package forecast_test
+
+import (
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/forecast"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate(t *testing.T) {
+	strong, weak := testdata.StrongTeam(soccer.FormationTypeDiamond), testdata.WeakTeam(soccer.FormationTypeDiamond)
+	f, err := forecast.Simulate(1, strong, weak, 300)
+	require.NoError(t, err)
+
+	assert.Equal(t, 300, f.Matches)
+	assert.InDelta(t, 1, f.Win+f.Draw+f.Loss, 1e-9)
+	assert.Greater(t, f.Win, 0.8)
+	assert.Greater(t, f.GoalsFor, f.GoalsAgainst)
+	assert.Greater(t, f.XGFor, f.XGAgainst)
+	assert.InDelta(t, f.Win+f.Draw/2, f.Points(), 1e-9)
+
+	// The same seed plays the same dice.
+	again, err := forecast.Simulate(1, strong, weak, 300)
+	require.NoError(t, err)
+	assert.Equal(t, f, again)
+	other, err := forecast.Simulate(2, strong, weak, 300)
+	require.NoError(t, err)
+	assert.NotEqual(t, f, other)
+}
+
+func TestSimulate_Errors(t *testing.T) {
+	strong := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	_, err := forecast.Simulate(1, strong, strong, 0)
+	assert.ErrorIs(t, err, forecast.ErrNoMatches)
+
+	_, err = forecast.Simulate(1, strong, testdata.WeakTeam(soccer.FormationTypeClassic), 10)
+	assert.ErrorIs(t, err, soccer.ErrLineupSize)
+}
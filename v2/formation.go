@@ -1,10 +1,18 @@
 package soccer
 
-import "github.com/stein-f/oink-soccer-common/v2/internal/tuning"
+import (
+	"fmt"
+	"sort"
+	"sync"
 
-// FormationConfig describes a tactical shape — its name, slot layout, and
-// the trade-offs it makes against neutral. lost-pigs serializes this as JSON
-// for the formation picker UI.
+	"github.com/stein-f/oink-soccer-common/v2/internal/tuning"
+)
+
+// FormationConfig describes a tactical shape — its name, slot layout, the
+// trade-offs it makes against neutral, its chance-volume style and the kind
+// of chances it creates. lost-pigs serializes this as JSON for the formation
+// picker UI. Formations are registered with RegisterFormation; the four
+// built-ins are registered at init.
 //
 // The legacy DefenseModifier / ControlModifier / AttackModifier fields are
 // kept for backward JSON compatibility but are derived from Profile (see
@@ -13,6 +21,13 @@ type FormationConfig struct {
 	FormationType FormationType
 	Slots         map[uint64]PlayerPosition
 	Profile       FormationProfile
+	// Style buckets the formation for the chance-volume table: two ATT
+	// shapes produce more chances than two DEF shapes.
+	Style FormationStyle
+	// ChanceMix shifts the chance-type distribution when this formation has
+	// the ball (e.g. The Box produces more crosses and corners). Published
+	// so the manager guide and UI can show it; see tuning.FormationChanceMixes.
+	ChanceMix ChanceMix
 
 	// Deprecated: derived from Profile for backward compatibility. The v2
 	// engine ignores these fields. Read Profile.* instead.
@@ -25,11 +40,27 @@ type FormationConfig struct {
 // internal/tuning.FormationProfile for the full doc.
 type FormationProfile = tuning.FormationProfile
 
+// ChanceMix is the per-chance-type weight multiplier table. See
+// internal/tuning.ChanceMix for the full doc.
+type ChanceMix = tuning.ChanceMix
+
 var (
-	ThePyramidFormation = formationConfigFor(FormationTypePyramid, slotsPyramid)
-	TheDiamondFormation = formationConfigFor(FormationTypeDiamond, slotsDiamond)
-	TheYFormation       = formationConfigFor(FormationTypeY, slotsY)
-	TheBoxFormation     = formationConfigFor(FormationTypeBox, slotsBox)
+	ThePyramidFormation = formationConfigFor(FormationTypePyramid, FormationStyleDefensive, slotsPyramid)
+	TheDiamondFormation = formationConfigFor(FormationTypeDiamond, FormationStyleBalanced, slotsDiamond)
+	TheYFormation       = formationConfigFor(FormationTypeY, FormationStyleAttacking, slotsY)
+	// Box is BAL despite its attacking shape: its lever is chance quality,
+	// not volume.
+	TheBoxFormation = formationConfigFor(FormationTypeBox, FormationStyleBalanced, slotsBox)
+
+	// Seven-a-side.
+	TheArrowFormation = formationConfigFor(FormationTypeArrow, FormationStyleBalanced, slotsFor(2, 3, 1))
+	TheWallFormation  = formationConfigFor(FormationTypeWall, FormationStyleDefensive, slotsFor(3, 2, 1))
+	TheForkFormation  = formationConfigFor(FormationTypeFork, FormationStyleAttacking, slotsFor(2, 2, 2))
+
+	// Eleven-a-side.
+	TheClassicFormation  = formationConfigFor(FormationTypeClassic, FormationStyleBalanced, slotsFor(4, 4, 2))
+	TheFortressFormation = formationConfigFor(FormationTypeFortress, FormationStyleDefensive, slotsFor(5, 3, 2))
+	TheTridentFormation  = formationConfigFor(FormationTypeTrident, FormationStyleAttacking, slotsFor(4, 3, 3))
 )
 
 var (
@@ -63,20 +94,204 @@ var (
 	}
 )
 
-func formationConfigFor(t FormationType, slots map[uint64]PlayerPosition) FormationConfig {
-	profile := tuning.LookupFormationProfile(string(t))
-	return FormationConfig{
-		FormationType:   t,
-		Slots:           slots,
-		Profile:         profile,
-		DefenseModifier: profile.DefSolidity,
-		ControlModifier: profile.Possession,
-		AttackModifier:  profile.ChanceCreation * profile.ChanceQuality,
+// slotsFor lays out a goalkeeper in slot 1 followed by the given number of
+// defenders, midfielders and attackers, in that order.
+func slotsFor(def, mid, atk int) map[uint64]PlayerPosition {
+	slots := map[uint64]PlayerPosition{1: PlayerPositionGoalkeeper}
+	next := uint64(2)
+	for _, group := range []struct {
+		pos   PlayerPosition
+		count int
+	}{{PlayerPositionDefense, def}, {PlayerPositionMidfield, mid}, {PlayerPositionAttack, atk}} {
+		for i := 0; i < group.count; i++ {
+			slots[next] = group.pos
+			next++
+		}
+	}
+	return slots
+}
+
+// TeamSize is the number of players the formation fields — one per slot.
+// Lineups must match it exactly.
+func (f FormationConfig) TeamSize() int {
+	return len(f.Slots)
+}
+
+func formationConfigFor(t FormationType, style FormationStyle, slots map[uint64]PlayerPosition) FormationConfig {
+	return withLegacyModifiers(FormationConfig{
+		FormationType: t,
+		Slots:         slots,
+		Profile:       tuning.LookupFormationProfile(string(t)),
+		Style:         style,
+		ChanceMix:     tuning.LookupChanceMix(string(t)),
+	})
+}
+
+// withLegacyModifiers fills the deprecated modifier fields from Profile.
+func withLegacyModifiers(cfg FormationConfig) FormationConfig {
+	cfg.DefenseModifier = cfg.Profile.DefSolidity
+	cfg.ControlModifier = cfg.Profile.Possession
+	cfg.AttackModifier = cfg.Profile.ChanceCreation * cfg.Profile.ChanceQuality
+	return cfg
+}
+
+func init() {
+	for _, cfg := range []FormationConfig{
+		ThePyramidFormation, TheDiamondFormation, TheYFormation, TheBoxFormation,
+		TheArrowFormation, TheWallFormation, TheForkFormation,
+		TheClassicFormation, TheFortressFormation, TheTridentFormation,
+	} {
+		if err := RegisterFormation(cfg); err != nil {
+			panic(err)
+		}
+	}
+}
+
+var (
+	formationsMu sync.RWMutex
+	formations   = map[FormationType]FormationConfig{}
+)
+
+// RegisterFormation adds a formation to the registry so lineups can name it.
+// Intended for start-up (community event shapes such as a 1-2-1-1
+// "Christmas Tree"); registering while matches are running is safe but a
+// formation cannot be replaced once registered.
+//
+// The config is validated and copied. Slots must be numbered 1..n with
+// exactly one goalkeeper, n must be a supported team size (5, 7 or 11; see
+// tuning.TeamSizeScalings), Style must be one of the FormationStyle values,
+// every Profile axis must be positive, and ChanceMix may only name known
+// chance types with positive factors. The deprecated modifier fields are
+// derived from Profile; any values supplied are ignored.
+func RegisterFormation(cfg FormationConfig) error {
+	if err := validateFormation(cfg); err != nil {
+		return err
+	}
+	cfg = withLegacyModifiers(cloneFormationConfig(cfg))
+
+	formationsMu.Lock()
+	defer formationsMu.Unlock()
+	if _, ok := formations[cfg.FormationType]; ok {
+		return fmt.Errorf("%w: %q", ErrFormationExists, cfg.FormationType)
+	}
+	formations[cfg.FormationType] = cfg
+	return nil
+}
+
+// LookupFormation returns the registered config for a formation, or
+// ErrUnknownFormation.
+func LookupFormation(t FormationType) (FormationConfig, error) {
+	formationsMu.RLock()
+	cfg, ok := formations[t]
+	formationsMu.RUnlock()
+	if !ok {
+		return FormationConfig{}, fmt.Errorf("%w: %q", ErrUnknownFormation, t)
+	}
+	return cloneFormationConfig(cfg), nil
+}
+
+// Formations returns every registered formation, sorted by FormationType so
+// the picker UI gets a stable order.
+func Formations() []FormationConfig {
+	formationsMu.RLock()
+	out := make([]FormationConfig, 0, len(formations))
+	for _, cfg := range formations {
+		out = append(out, cloneFormationConfig(cfg))
+	}
+	formationsMu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].FormationType < out[j].FormationType })
+	return out
+}
+
+func validateFormation(cfg FormationConfig) error {
+	if cfg.FormationType == "" {
+		return fmt.Errorf("%w: FormationType is required", ErrInvalidFormation)
+	}
+	if len(cfg.Slots) == 0 {
+		return fmt.Errorf("%w: %q has no slots", ErrInvalidFormation, cfg.FormationType)
+	}
+	if _, ok := tuning.TeamSizeScalings[len(cfg.Slots)]; !ok {
+		return fmt.Errorf("%w: %q has %d slots, team size is not supported", ErrInvalidFormation, cfg.FormationType, len(cfg.Slots))
+	}
+	keepers := 0
+	for i := 1; i <= len(cfg.Slots); i++ {
+		pos, ok := cfg.Slots[uint64(i)]
+		if !ok {
+			return fmt.Errorf("%w: %q slots must be numbered 1..%d, missing %d", ErrInvalidFormation, cfg.FormationType, len(cfg.Slots), i)
+		}
+		switch pos {
+		case PlayerPositionGoalkeeper:
+			keepers++
+		case PlayerPositionDefense, PlayerPositionMidfield, PlayerPositionAttack:
+		default:
+			return fmt.Errorf("%w: %q slot %d has invalid position %q", ErrInvalidFormation, cfg.FormationType, i, pos)
+		}
+	}
+	if keepers != 1 {
+		return fmt.Errorf("%w: %q needs exactly one goalkeeper slot, has %d", ErrInvalidFormation, cfg.FormationType, keepers)
+	}
+	switch cfg.Style {
+	case FormationStyleAttacking, FormationStyleBalanced, FormationStyleDefensive:
+	default:
+		return fmt.Errorf("%w: %q has invalid style %q", ErrInvalidFormation, cfg.FormationType, cfg.Style)
+	}
+	p := cfg.Profile
+	for name, v := range map[string]float64{
+		"Possession": p.Possession, "ChanceCreation": p.ChanceCreation, "ChanceQuality": p.ChanceQuality,
+		"DefSolidity": p.DefSolidity, "InjuryRisk": p.InjuryRisk,
+	} {
+		if v <= 0 {
+			return fmt.Errorf("%w: %q profile %s must be positive", ErrInvalidFormation, cfg.FormationType, name)
+		}
+	}
+	for ct, f := range cfg.ChanceMix {
+		if _, ok := chanceTypeProfiles[ChanceType(ct)]; !ok {
+			return fmt.Errorf("%w: %q chance mix names unknown chance type %q", ErrInvalidFormation, cfg.FormationType, ct)
+		}
+		if f <= 0 {
+			return fmt.Errorf("%w: %q chance mix factor for %q must be positive", ErrInvalidFormation, cfg.FormationType, ct)
+		}
+	}
+	return nil
+}
+
+// cloneFormationConfig copies the maps so neither the caller nor the
+// registry can mutate the other's config.
+func cloneFormationConfig(cfg FormationConfig) FormationConfig {
+	slots := make(map[uint64]PlayerPosition, len(cfg.Slots))
+	for k, v := range cfg.Slots {
+		slots[k] = v
 	}
+	mix := make(ChanceMix, len(cfg.ChanceMix))
+	for k, v := range cfg.ChanceMix {
+		mix[k] = v
+	}
+	cfg.Slots = slots
+	cfg.ChanceMix = mix
+	return cfg
+}
+
+// registeredFormation returns the config the engine plays for a lineup's
+// formation. RunGameWithSeed rejects unknown formations up front; the
+// neutral fallback only serves internal helpers scored without a formation.
+func registeredFormation(t FormationType) FormationConfig {
+	formationsMu.RLock()
+	cfg, ok := formations[t]
+	formationsMu.RUnlock()
+	if !ok {
+		return FormationConfig{FormationType: t, Profile: tuning.NeutralProfile, Style: FormationStyleBalanced}
+	}
+	return cfg
 }
 
 // formationProfileFor returns the trade-off profile for the formation
-// stored on a lineup. Used by the engine. Falls back to NeutralProfile.
+// stored on a lineup. Used by the engine.
 func formationProfileFor(t FormationType) FormationProfile {
-	return tuning.LookupFormationProfile(string(t))
+	return registeredFormation(t).Profile
+}
+
+// formationChanceMixFor returns the chance-type mix for the formation stored
+// on a lineup. A nil mix is neutral.
+func formationChanceMixFor(t FormationType) ChanceMix {
+	return registeredFormation(t).ChanceMix
 }
@@ -1,22 +1,36 @@
 package soccer_test
 
 import (
+	"errors"
+	"math/rand"
 	"testing"
 
 	soccer "github.com/stein-f/oink-soccer-common/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// builtInFormations lists every formation registered at init, grouped by
+// team size.
+var builtInFormations = []soccer.FormationConfig{
+	soccer.ThePyramidFormation,
+	soccer.TheDiamondFormation,
+	soccer.TheYFormation,
+	soccer.TheBoxFormation,
+
+	soccer.TheArrowFormation,
+	soccer.TheWallFormation,
+	soccer.TheForkFormation,
+
+	soccer.TheClassicFormation,
+	soccer.TheFortressFormation,
+	soccer.TheTridentFormation,
+}
+
 // Every formation must publish a non-zero profile. A zero-valued profile
 // would make the engine multiply everything by 0.
 func TestEveryFormationPublishesAProfile(t *testing.T) {
-	formations := []soccer.FormationConfig{
-		soccer.ThePyramidFormation,
-		soccer.TheDiamondFormation,
-		soccer.TheYFormation,
-		soccer.TheBoxFormation,
-	}
-	for _, f := range formations {
+	for _, f := range builtInFormations {
 		t.Run(string(f.FormationType), func(t *testing.T) {
 			assert.NotZero(t, f.Profile.Possession)
 			assert.NotZero(t, f.Profile.ChanceCreation)
@@ -32,13 +46,7 @@ func TestEveryFormationPublishesAProfile(t *testing.T) {
 // publish a control modifier of 1.05 (advertised) but a possession profile
 // of 0.95 (actually used by the engine).
 func TestLegacyModifiersAreDerivedFromProfile(t *testing.T) {
-	formations := []soccer.FormationConfig{
-		soccer.ThePyramidFormation,
-		soccer.TheDiamondFormation,
-		soccer.TheYFormation,
-		soccer.TheBoxFormation,
-	}
-	for _, f := range formations {
+	for _, f := range builtInFormations {
 		t.Run(string(f.FormationType), func(t *testing.T) {
 			assert.Equal(t, f.Profile.DefSolidity, f.DefenseModifier)
 			assert.Equal(t, f.Profile.Possession, f.ControlModifier)
@@ -47,32 +55,167 @@ func TestLegacyModifiersAreDerivedFromProfile(t *testing.T) {
 	}
 }
 
-// No formation should strictly dominate another — i.e. for every pair (A,B)
-// there must be at least one axis where A is worse than B (treating
-// InjuryRisk as "worse when higher", others as "better when higher").
+// No formation should strictly dominate another of the same team size —
+// i.e. for every pair (A,B) there must be at least one axis where A is worse
+// than B (treating InjuryRisk as "worse when higher", others as "better when
+// higher").
 func TestNoFormationStrictlyDominates(t *testing.T) {
-	formations := map[string]soccer.FormationProfile{
-		"Pyramid": soccer.ThePyramidFormation.Profile,
-		"Diamond": soccer.TheDiamondFormation.Profile,
-		"Y":       soccer.TheYFormation.Profile,
-		"Box":     soccer.TheBoxFormation.Profile,
-	}
-	for nameA, a := range formations {
-		for nameB, b := range formations {
-			if nameA == nameB {
+	for _, a := range builtInFormations {
+		for _, b := range builtInFormations {
+			if a.FormationType == b.FormationType || a.TeamSize() != b.TeamSize() {
 				continue
 			}
-			t.Run(nameA+"_vs_"+nameB, func(t *testing.T) {
-				dominates := a.Possession >= b.Possession &&
-					a.ChanceCreation >= b.ChanceCreation &&
-					a.ChanceQuality >= b.ChanceQuality &&
-					a.DefSolidity >= b.DefSolidity &&
-					a.InjuryRisk <= b.InjuryRisk &&
-					(a.Possession > b.Possession || a.ChanceCreation > b.ChanceCreation ||
-						a.ChanceQuality > b.ChanceQuality || a.DefSolidity > b.DefSolidity ||
-						a.InjuryRisk < b.InjuryRisk)
-				assert.False(t, dominates, "%s strictly dominates %s — every axis at least equal and at least one strictly better", nameA, nameB)
+			pa, pb := a.Profile, b.Profile
+			t.Run(string(a.FormationType)+"_vs_"+string(b.FormationType), func(t *testing.T) {
+				dominates := pa.Possession >= pb.Possession &&
+					pa.ChanceCreation >= pb.ChanceCreation &&
+					pa.ChanceQuality >= pb.ChanceQuality &&
+					pa.DefSolidity >= pb.DefSolidity &&
+					pa.InjuryRisk <= pb.InjuryRisk &&
+					(pa.Possession > pb.Possession || pa.ChanceCreation > pb.ChanceCreation ||
+						pa.ChanceQuality > pb.ChanceQuality || pa.DefSolidity > pb.DefSolidity ||
+						pa.InjuryRisk < pb.InjuryRisk)
+				assert.False(t, dominates, "%s strictly dominates %s — every axis at least equal and at least one strictly better", a.FormationType, b.FormationType)
 			})
 		}
 	}
 }
+
+// Every built-in formation publishes a chance mix, and the mix only ever
+// scales the chance-type weights by a positive factor — a zero or negative
+// entry would silently delete a chance type.
+func TestEveryFormationPublishesAChanceMix(t *testing.T) {
+	for _, f := range builtInFormations {
+		t.Run(string(f.FormationType), func(t *testing.T) {
+			assert.NotEmpty(t, f.ChanceMix)
+			for ct, factor := range f.ChanceMix {
+				assert.Greater(t, factor, 0.0, "chance mix entry %q must be positive", ct)
+			}
+		})
+	}
+}
+
+// christmasTree is a 1-2-1-1-style novelty shape, the kind of seasonal
+// formation community events register at start-up.
+func christmasTree(name soccer.FormationType) soccer.FormationConfig {
+	return soccer.FormationConfig{
+		FormationType: name,
+		Slots: map[uint64]soccer.PlayerPosition{
+			1: soccer.PlayerPositionGoalkeeper,
+			2: soccer.PlayerPositionDefense,
+			3: soccer.PlayerPositionMidfield,
+			4: soccer.PlayerPositionMidfield,
+			5: soccer.PlayerPositionAttack,
+		},
+		Profile: soccer.FormationProfile{Possession: 1.02, ChanceCreation: 1.00, ChanceQuality: 1.01, DefSolidity: 0.98, InjuryRisk: 1.00},
+		Style:   soccer.FormationStyleBalanced,
+		ChanceMix: soccer.ChanceMix{
+			string(soccer.ChanceTypeLongRange): 1.20,
+			string(soccer.ChanceTypeCross):     0.85,
+		},
+	}
+}
+
+func TestBuiltInFormationsAreRegistered(t *testing.T) {
+	for _, want := range builtInFormations {
+		got, err := soccer.LookupFormation(want.FormationType)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	assert.GreaterOrEqual(t, len(soccer.Formations()), len(builtInFormations))
+}
+
+// Each supported team size ships a defensive, balanced and attacking shape
+// (five-a-side has two balanced ones).
+func TestBuiltInFormationsCoverEveryTeamSize(t *testing.T) {
+	styles := map[int]map[soccer.FormationStyle]bool{}
+	for _, f := range builtInFormations {
+		if styles[f.TeamSize()] == nil {
+			styles[f.TeamSize()] = map[soccer.FormationStyle]bool{}
+		}
+		styles[f.TeamSize()][f.Style] = true
+	}
+	for _, size := range []int{5, 7, 11} {
+		assert.Len(t, styles[size], 3, "%d-a-side should offer ATT, BAL and DEF shapes", size)
+	}
+}
+
+// A registered novelty formation plays through the engine with its own
+// profile, and is published alongside the built-ins.
+func TestRegisterFormation_CustomFormationPlays(t *testing.T) {
+	cfg := christmasTree("Christmas Tree (plays)")
+	cfg.AttackModifier = 99 // derived from Profile; the supplied value is ignored
+	require.NoError(t, soccer.RegisterFormation(cfg))
+
+	got, err := soccer.LookupFormation(cfg.FormationType)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Profile, got.Profile)
+	assert.Equal(t, cfg.Profile.ChanceCreation*cfg.Profile.ChanceQuality, got.AttackModifier)
+	assert.Contains(t, soccer.Formations(), got)
+
+	home := strongLineup(cfg.FormationType)
+	away := strongLineup(soccer.FormationTypeDiamond)
+	events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(1)), home, away)
+	require.NoError(t, err)
+	assert.NotEmpty(t, events)
+}
+
+func TestRegisterFormation_RejectsDuplicates(t *testing.T) {
+	cfg := christmasTree("Christmas Tree (duplicate)")
+	require.NoError(t, soccer.RegisterFormation(cfg))
+	assert.True(t, errors.Is(soccer.RegisterFormation(cfg), soccer.ErrFormationExists))
+	assert.True(t, errors.Is(soccer.RegisterFormation(soccer.TheBoxFormation), soccer.ErrFormationExists))
+}
+
+func TestRegisterFormation_Validates(t *testing.T) {
+	tests := map[string]func(*soccer.FormationConfig){
+		"missing name":    func(c *soccer.FormationConfig) { c.FormationType = "" },
+		"no slots":        func(c *soccer.FormationConfig) { c.Slots = nil },
+		"slot gap":        func(c *soccer.FormationConfig) { delete(c.Slots, 3); c.Slots[6] = soccer.PlayerPositionMidfield },
+		"no goalkeeper":   func(c *soccer.FormationConfig) { c.Slots[1] = soccer.PlayerPositionDefense },
+		"two goalkeepers": func(c *soccer.FormationConfig) { c.Slots[2] = soccer.PlayerPositionGoalkeeper },
+		"any position":    func(c *soccer.FormationConfig) { c.Slots[5] = soccer.PlayerPositionAny },
+		"missing style":   func(c *soccer.FormationConfig) { c.Style = "" },
+		"zero profile":    func(c *soccer.FormationConfig) { c.Profile.DefSolidity = 0 },
+		"unknown chance":  func(c *soccer.FormationConfig) { c.ChanceMix["Bicycle Kick"] = 1.1 },
+		"zero chance mix": func(c *soccer.FormationConfig) { c.ChanceMix[string(soccer.ChanceTypeCorner)] = 0 },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := christmasTree(soccer.FormationType("Christmas Tree (" + name + ")"))
+			mutate(&cfg)
+			err := soccer.RegisterFormation(cfg)
+			assert.True(t, errors.Is(err, soccer.ErrInvalidFormation), "got %v", err)
+			if cfg.FormationType != "" {
+				_, err := soccer.LookupFormation(cfg.FormationType)
+				assert.True(t, errors.Is(err, soccer.ErrUnknownFormation), "invalid formation must not be registered")
+			}
+		})
+	}
+}
+
+// The registry stores a copy — mutating the caller's maps after
+// registration must not change the formation the engine plays.
+func TestRegisterFormation_CopiesConfig(t *testing.T) {
+	cfg := christmasTree("Christmas Tree (copy)")
+	require.NoError(t, soccer.RegisterFormation(cfg))
+	cfg.Slots[5] = soccer.PlayerPositionDefense
+	cfg.ChanceMix[string(soccer.ChanceTypeCross)] = 5
+
+	got, err := soccer.LookupFormation(cfg.FormationType)
+	require.NoError(t, err)
+	assert.Equal(t, soccer.PlayerPositionAttack, got.Slots[5])
+	assert.Equal(t, 0.85, got.ChanceMix[string(soccer.ChanceTypeCross)])
+}
+
+// Unknown formations used to play silently with the neutral profile; a
+// typo in a lineup now fails loudly.
+func TestRunGameWithSeed_RejectsUnknownFormation(t *testing.T) {
+	home := strongLineup("The Pyramd")
+	away := strongLineup(soccer.FormationTypeDiamond)
+	_, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(1)), home, away)
+	assert.True(t, errors.Is(err, soccer.ErrUnknownFormation))
+
+	_, _, err = soccer.RunGameWithSeed(rand.New(rand.NewSource(1)), away, home)
+	assert.True(t, errors.Is(err, soccer.ErrUnknownFormation))
+}
@@ -106,6 +106,7 @@ type goldenInjury struct {
 	PlayerID     string                `json:"player_id"`
 	Severity     soccer.InjurySeverity `json:"severity"`
 	Name         string                `json:"name"`
+	Minute       int                   `json:"minute"`
 	DurationDays int                   `json:"duration_days"`
 }
 
@@ -123,6 +124,9 @@ func eventsToGolden(events []soccer.GameEvent) []goldenEvent {
 		case soccer.GameEventTypeMiss:
 			m := e.GetMissEvent()
 			ev.PlayerID, ev.TeamType = m.PlayerID, m.TeamType
+		case soccer.GameEventTypeInjury:
+			i := e.GetInjuryEvent()
+			ev.PlayerID, ev.TeamType = i.PlayerID, i.TeamType
 		}
 		out = append(out, ev)
 	}
@@ -146,6 +150,7 @@ func injuryListToGolden(events []soccer.InjuryEvent) []goldenInjury {
 			PlayerID:     e.PlayerID,
 			Severity:     e.Injury.Severity,
 			Name:         e.Injury.Name,
+			Minute:       e.Minute,
 			DurationDays: e.DurationDays,
 		})
 	}
@@ -1,22 +1,37 @@
 package soccer
 
 import (
+	"math"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/stein-f/oink-soccer-common/v2/internal/tuning"
 )
 
+// Injury is a catalogue entry. StatsReduction is the multiplier the injury
+// applies while the player carries it: to the attributes listed in Affects,
+// or, when Affects is empty, flat to every score (the v1 behavior, kept for
+// injuries built outside the catalogue).
 type Injury struct {
 	Severity       InjurySeverity `json:"severity"`
 	MinDays        int            `json:"min_days"`
 	MaxDays        int            `json:"max_days"`
 	Name           string         `json:"name"`
 	StatsReduction float64        `json:"stats_reduction"`
+	Affects        []Attribute    `json:"affects,omitempty"`
 	Description    string         `json:"description"`
 	Weight         uint           `json:"weight"`
 }
 
+// reduction returns StatsReduction, or 1.0 when it is outside (0, 1].
+func (i Injury) reduction() float64 {
+	if i.StatsReduction <= 0 || i.StatsReduction > 1 {
+		return 1.0
+	}
+	return i.StatsReduction
+}
+
 // InjuryEvent describes an injury picked up during a match.
 //
 // In v1 the engine populated Expires using time.Now() at simulation time,
@@ -26,9 +41,14 @@ type Injury struct {
 //
 // DurationDays carries the rolled duration so callers can pin Expires
 // against any clock without re-rolling and breaking determinism.
+//
+// Minute and TeamType are set for injuries picked up in a simulated match;
+// the same event also appears in the match's event stream.
 type InjuryEvent struct {
 	TeamID       string    `json:"team_id"`
+	TeamType     TeamType  `json:"team_type,omitempty"`
 	PlayerID     string    `json:"player_id"`
+	Minute       int       `json:"minute,omitempty"`
 	Expires      time.Time `json:"expires"`
 	DurationDays int       `json:"duration_days,omitempty"`
 	Injury       Injury    `json:"injury"`
@@ -39,6 +59,23 @@ type Injuries struct {
 	AwayTeamInjuries []InjuryEvent `json:"away_team_injuries"`
 }
 
+// ApplyInjuries returns the lineup with each injury given to its player, so
+// the next match (a second leg, extra time) is played carrying it. A player
+// already carrying a worse injury keeps it; injuries for players not in the
+// lineup are ignored. The input lineup is not modified.
+func ApplyInjuries(lineup GameLineup, injuries []InjuryEvent) GameLineup {
+	players := append([]SelectedPlayer(nil), lineup.Players...)
+	for _, inj := range injuries {
+		for i := range players {
+			if players[i].ID == inj.PlayerID && (players[i].Injury == nil || inj.Injury.reduction() < players[i].Injury.Injury.reduction()) {
+				players[i].Injury = &inj
+			}
+		}
+	}
+	lineup.Players = players
+	return lineup
+}
+
 // ResolveInjuryExpiry computes an absolute expiry timestamp for an injury
 // event using the supplied clock. v1 baked time.Now() into the simulation;
 // v2 splits that out so the engine itself stays deterministic.
@@ -54,39 +91,89 @@ func GetAllInjuries() []Injury {
 	return injuryCatalogue
 }
 
-// rollInjuries decides which players in a lineup picked up an injury this
-// match. opponentAggression is the average aggression rating of the other
-// team. opponentFormationInjuryRisk is the multiplier from the opponent's
-// formation profile (more aggressive shapes ⇒ more injuries inflicted).
-func rollInjuries(rand *rand.Rand, lineup GameLineup, opponentAggression int, opponentFormationInjuryRisk float64, teamID string) []InjuryEvent {
+// injuryClock accumulates one team's in-match injury risk. Each player's
+// per-match odds (see injuryMatchChance) are spread across the minutes
+// played by tuning.InjuryExposure, so a knock can land at any point of the
+// match rather than being rolled once at full time.
+type injuryClock struct {
+	teamID   string
+	teamType TeamType
+	odds     map[string]float64 // per-match injury probability by player ID
+	ramp     float64
+	minute   int // risk has been rolled up to and including this minute
+	injured  map[string]bool
+}
+
+// newInjuryClock prices each player's per-match injury odds against the
+// opponent. opponentAggression is the average aggression rating of the other
+// team; formationRisk combines the opponent formation's injury risk with
+// the team's own press (more aggressive shapes ⇒ more injuries inflicted).
+// Each player's own InjuryRisk multiplies on top.
+func newInjuryClock(lineup GameLineup, teamType TeamType, opponentAggression int, formationRisk, ramp float64) *injuryClock {
+	c := &injuryClock{
+		teamID:   lineup.Team.ID,
+		teamType: teamType,
+		odds:     make(map[string]float64, len(lineup.Players)),
+		ramp:     ramp,
+		injured:  make(map[string]bool),
+	}
+	for _, p := range lineup.Players {
+		c.odds[p.ID] = injuryMatchChance(p.Attributes.IsInjuryProne(), opponentAggression, formationRisk*p.injuryRiskFactor())
+	}
+	return c
+}
+
+// advance rolls the risk carried by minutes (c.minute, to] for every player
+// not yet injured this match and returns the new injuries in minute order.
+// Each injury's minute is drawn uniformly within the window.
+func (c *injuryClock) advance(r *rand.Rand, lineup GameLineup, to int) []InjuryEvent {
+	from := c.minute
+	if to <= from {
+		return nil
+	}
+	c.minute = to
+	exposure := tuning.InjuryExposure(from, to, c.ramp)
+	if exposure <= 0 {
+		return nil
+	}
+	last := min(to, tuning.InjuryMatchMinutes)
+
 	var out []InjuryEvent
 	for _, p := range lineup.Players {
-		got, injury := rollPlayerInjury(rand, p.Attributes.IsInjuryProne(), opponentAggression, opponentFormationInjuryRisk)
-		if !got {
+		if c.injured[p.ID] {
+			continue
+		}
+		if r.Float64() >= 1-math.Pow(1-c.odds[p.ID], exposure) {
 			continue
 		}
+		injury := pickInjury(r)
 		days := injury.MinDays
 		if injury.MaxDays > injury.MinDays {
-			days = rand.Intn(injury.MaxDays-injury.MinDays+1) + injury.MinDays
+			days = r.Intn(injury.MaxDays-injury.MinDays+1) + injury.MinDays
 		}
+		c.injured[p.ID] = true
 		out = append(out, InjuryEvent{
-			TeamID:       teamID,
+			TeamID:       c.teamID,
+			TeamType:     c.teamType,
 			PlayerID:     p.ID,
+			Minute:       from + 1 + r.Intn(last-from),
 			DurationDays: days,
 			Injury:       injury,
 		})
 	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Minute < out[j].Minute })
 	return out
 }
 
-// rollPlayerInjury performs the per-player injury roll. Weights:
+// injuryMatchChance returns a player's odds of picking up an injury over a
+// full match. Weights:
 //   - base: {false: NoInjuryWeightDefault, true: 1}  (≈ 30:1 ⇒ 1/31 odds)
 //   - injury-prone: {false: NoInjuryWeightInjuryProne, true: 1} (≈ 15:1)
 //
 // Opponent aggression scales the "no injury" weight downward (capped at
 // AggressionMaxNoInjuryReduction). Opponent formation injury risk
 // multiplies the same downward pressure.
-func rollPlayerInjury(r *rand.Rand, prone bool, aggression int, formationRisk float64) (bool, Injury) {
+func injuryMatchChance(prone bool, aggression int, formationRisk float64) float64 {
 	noInjuryW := float64(tuning.NoInjuryWeightDefault)
 	if prone {
 		noInjuryW = float64(tuning.NoInjuryWeightInjuryProne)
@@ -108,12 +195,7 @@ func rollPlayerInjury(r *rand.Rand, prone bool, aggression int, formationRisk fl
 	if noInjuryW < 1 {
 		noInjuryW = 1
 	}
-
-	totalW := noInjuryW + 1.0
-	if r.Float64()*totalW < noInjuryW {
-		return false, Injury{}
-	}
-	return true, pickInjury(r)
+	return 1.0 / (noInjuryW + 1.0)
 }
 
 func pickInjury(r *rand.Rand) Injury {
@@ -147,53 +229,54 @@ func teamAverageAggression(lineup GameLineup) int {
 	return total / len(lineup.Players)
 }
 
-// injuryCatalogue is ported verbatim from v1. Severity multipliers come
-// from tuning so any future balance change to "what does a high-severity
-// injury cost" lives in one place.
+// injuryCatalogue is ported from v1 — names, flavor text, durations and
+// weights are unchanged. Each entry names the attributes it hurts; severity
+// multipliers come from tuning so any future balance change to "what does a
+// high-severity injury cost" lives in one place.
 var injuryCatalogue = []Injury{
-	// low severity (1-day, -5%)
-	low("Minor sprain", "Overstretched a ligament performing an unsuccessful tackle.", 100),
-	low("Squirrel Scare", "Spooked by a squirrel running onto the field, leading to a comical but unfortunate tumble.", 100),
-	low("Pie Burn", "Out for a game after trying to eat pie too quickly during half-time match and burning the roof of their mouth.", 100),
-	low("Laugh Attack", "Couldn't stop laughing after a teammate's joke and ended up with a side stitch.", 100),
-	low("Dance-Off Defeat", "Suffered a minor ego bruise and twisted ankle during an impromptu pre-match dance-off.", 100),
-	low("Turf Toe", "Stubbed a toe on the turf while celebrating a goal.", 100),
-	low("Pepper Spray Incident", "Accidentally rubbed eyes after handling spicy food after the match.", 100),
-	low("Selfie Slip", "Lost balance while taking a selfie on the field after the match, resulting in a harmless but embarrassing fall.", 100),
-	low("Paparazzi Panic", "Momentarily blinded by a camera flash from an overzealous fan after the match.", 100),
-	low("Locker Room Slippery Floor", "Slipped on a wet spot in the locker room, causing a minor sprain.", 100),
-	low("Overzealous Autograph Signing", "Strained wrist after signing too many autographs post-match.", 100),
-
-	// medium severity (2-3 day, -10%)
-	med("Powerful sneeze", "'Nasty' back injury caused by a powerful sneeze.", 25),
-	med("Hamstring strain", "Minor hamstring tear after sprinting to catch up with a breakaway.", 25),
-	med("Concussion", "Head injury after a collision with a teammate during a header.", 25),
-	med("Mismatched Boots", "Wore two left boots to the game, resulting in blisters and confused running.", 25),
-	med("Charley Horse", "Severe muscle cramp from overexertion during the match.", 25),
-	med("Overenthusiastic Headbutt", "Minor concussion after an overzealous attempt to head the ball.", 25),
-	med("Mascot Mishap", "Collided with the team mascot during a halftime stunt, resulting in a bruised rib.", 25),
-	med("Helmet Hair Disaster", "Spent too much time adjusting hair under the helmet, leading to neck strain.", 25),
-	med("Post-Match Pizza Overload", "Ate too much pizza after the match, causing severe stomach cramps.", 25),
-
-	// high severity (3-5 day, -15%)
-	high("Achilles Tendon Rupture", "Achilles tendon rupture after a sudden acceleration to chase down a ball.", 10),
-	high("High-five fail", "Missed a high-five and accidentally poked themselves in the eye.", 5),
-	high("ACL Tear", "Severe knee injury after an awkward landing.", 10),
-	high("Ballistic Banana Slip", "Slipped on a stray banana peel on the field, causing a back injury.", 5),
-	high("Celebration Injury", "Pulled a muscle during an over-enthusiastic goal celebration.", 5),
-	high("Caught on the Corner Flag", "Twisted an ankle after getting tangled with the corner flag during a quick turn.", 5),
-	high("Post-Match Cramp", "Severe muscle cramp from dehydration after the match, requiring extended recovery.", 5),
-	high("Hydration Hazard", "Slipped on spilled water in the locker room after the match, resulting in a dislocated shoulder.", 5),
-}
-
-func low(name, desc string, w uint) Injury {
-	return Injury{Severity: InjurySeverityLow, StatsReduction: tuning.StatsReductionLow, MinDays: 1, MaxDays: 1, Name: name, Description: desc, Weight: w}
-}
-
-func med(name, desc string, w uint) Injury {
-	return Injury{Severity: InjurySeverityMid, StatsReduction: tuning.StatsReductionMed, MinDays: 2, MaxDays: 3, Name: name, Description: desc, Weight: w}
-}
-
-func high(name, desc string, w uint) Injury {
-	return Injury{Severity: InjurySeverityHigh, StatsReduction: tuning.StatsReductionHigh, MinDays: 3, MaxDays: 5, Name: name, Description: desc, Weight: w}
+	// low severity (1-day)
+	low("Minor sprain", "Overstretched a ligament performing an unsuccessful tackle.", 100, AttributeSpeed, AttributeTackling),
+	low("Squirrel Scare", "Spooked by a squirrel running onto the field, leading to a comical but unfortunate tumble.", 100, AttributeComposure),
+	low("Pie Burn", "Out for a game after trying to eat pie too quickly during half-time match and burning the roof of their mouth.", 100, AttributeComposure),
+	low("Laugh Attack", "Couldn't stop laughing after a teammate's joke and ended up with a side stitch.", 100, AttributeWorkRate),
+	low("Dance-Off Defeat", "Suffered a minor ego bruise and twisted ankle during an impromptu pre-match dance-off.", 100, AttributeSpeed, AttributeComposure),
+	low("Turf Toe", "Stubbed a toe on the turf while celebrating a goal.", 100, AttributeSpeed, AttributeFinishing),
+	low("Pepper Spray Incident", "Accidentally rubbed eyes after handling spicy food after the match.", 100, AttributeControl, AttributeReflexes),
+	low("Selfie Slip", "Lost balance while taking a selfie on the field after the match, resulting in a harmless but embarrassing fall.", 100, AttributeComposure),
+	low("Paparazzi Panic", "Momentarily blinded by a camera flash from an overzealous fan after the match.", 100, AttributeControl, AttributeReflexes),
+	low("Locker Room Slippery Floor", "Slipped on a wet spot in the locker room, causing a minor sprain.", 100, AttributeSpeed),
+	low("Overzealous Autograph Signing", "Strained wrist after signing too many autographs post-match.", 100, AttributeGoalkeeper, AttributeHandling),
+
+	// medium severity (2-3 day)
+	med("Powerful sneeze", "'Nasty' back injury caused by a powerful sneeze.", 25, AttributeWorkRate, AttributeHeading),
+	med("Hamstring strain", "Minor hamstring tear after sprinting to catch up with a breakaway.", 25, AttributeSpeed, AttributeWorkRate),
+	med("Concussion", "Head injury after a collision with a teammate during a header.", 25, AttributeComposure, AttributeHeading),
+	med("Mismatched Boots", "Wore two left boots to the game, resulting in blisters and confused running.", 25, AttributeSpeed, AttributeTechnique),
+	med("Charley Horse", "Severe muscle cramp from overexertion during the match.", 25, AttributeSpeed, AttributeWorkRate),
+	med("Overenthusiastic Headbutt", "Minor concussion after an overzealous attempt to head the ball.", 25, AttributeHeading, AttributeComposure),
+	med("Mascot Mishap", "Collided with the team mascot during a halftime stunt, resulting in a bruised rib.", 25, AttributeWorkRate, AttributeTackling),
+	med("Helmet Hair Disaster", "Spent too much time adjusting hair under the helmet, leading to neck strain.", 25, AttributeHeading),
+	med("Post-Match Pizza Overload", "Ate too much pizza after the match, causing severe stomach cramps.", 25, AttributeWorkRate),
+
+	// high severity (3-5 day)
+	high("Achilles Tendon Rupture", "Achilles tendon rupture after a sudden acceleration to chase down a ball.", 10, AttributeSpeed, AttributeWorkRate),
+	high("High-five fail", "Missed a high-five and accidentally poked themselves in the eye.", 5, AttributeControl, AttributeReflexes),
+	high("ACL Tear", "Severe knee injury after an awkward landing.", 10, AttributeSpeed, AttributeWorkRate, AttributeTackling),
+	high("Ballistic Banana Slip", "Slipped on a stray banana peel on the field, causing a back injury.", 5, AttributeWorkRate, AttributeHeading),
+	high("Celebration Injury", "Pulled a muscle during an over-enthusiastic goal celebration.", 5, AttributeSpeed, AttributeFinishing),
+	high("Caught on the Corner Flag", "Twisted an ankle after getting tangled with the corner flag during a quick turn.", 5, AttributeSpeed, AttributeTechnique),
+	high("Post-Match Cramp", "Severe muscle cramp from dehydration after the match, requiring extended recovery.", 5, AttributeSpeed, AttributeWorkRate),
+	high("Hydration Hazard", "Slipped on spilled water in the locker room after the match, resulting in a dislocated shoulder.", 5, AttributeGoalkeeper, AttributeHandling, AttributeTackling),
+}
+
+func low(name, desc string, w uint, affects ...Attribute) Injury {
+	return Injury{Severity: InjurySeverityLow, StatsReduction: tuning.StatsReductionLow, Affects: affects, MinDays: 1, MaxDays: 1, Name: name, Description: desc, Weight: w}
+}
+
+func med(name, desc string, w uint, affects ...Attribute) Injury {
+	return Injury{Severity: InjurySeverityMid, StatsReduction: tuning.StatsReductionMed, Affects: affects, MinDays: 2, MaxDays: 3, Name: name, Description: desc, Weight: w}
+}
+
+func high(name, desc string, w uint, affects ...Attribute) Injury {
+	return Injury{Severity: InjurySeverityHigh, StatsReduction: tuning.StatsReductionHigh, Affects: affects, MinDays: 3, MaxDays: 5, Name: name, Description: desc, Weight: w}
 }
@@ -0,0 +1,215 @@
+package soccer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stein-f/oink-soccer-common/v2/internal/tuning"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// injuries_test.go pins how in-match injury risk accrues through a match and
+// what a knock does to the rest of it.
+
+func injuryLineup() GameLineup {
+	attrs := PlayerAttributes{GoalkeeperRating: 70, DefenseRating: 70, ControlRating: 70, AttackRating: 70, SpeedRating: 70}
+	lineup := chanceLineup(FormationTypeDiamond, diamondSlots, attrs)
+	lineup.Team.ID = "team"
+	return lineup
+}
+
+func TestInjuryMatchChance(t *testing.T) {
+	base := injuryMatchChance(false, 0, 1)
+	assert.InDelta(t, 1.0/31, base, 1e-9)
+	assert.Greater(t, injuryMatchChance(true, 0, 1), base, "injury-prone players get hurt more")
+	assert.Greater(t, injuryMatchChance(false, 80, 1), base, "aggressive opponents hurt more")
+	assert.Greater(t, injuryMatchChance(false, 0, 1.1), base, "risky shapes and pressing hurt more")
+	assert.Equal(t, 0.5, injuryMatchChance(true, 100, 100), "the no-injury weight floors at 1")
+}
+
+func TestInjuryClock_MatchOddsAreUnchanged(t *testing.T) {
+	if testing.Short() {
+		t.Skip("injury rate harness is heavy")
+	}
+	lineup := injuryLineup()
+	const trials = 20000
+	r := rand.New(rand.NewSource(1))
+	var injuries int
+	for i := 0; i < trials; i++ {
+		clock := newInjuryClock(lineup, TeamTypeHome, 0, 1, tuning.InjuryRampDefault)
+		// Roll in uneven windows, as chances do.
+		for _, m := range []int{4, 17, 17, 33, 52, 60, 71, 88, 95} {
+			injuries += len(clock.advance(r, lineup, m))
+		}
+		injuries += len(clock.advance(r, lineup, tuning.InjuryMatchMinutes))
+	}
+	perPlayer := float64(injuries) / float64(trials*len(lineup.Players))
+	assert.InDelta(t, 1.0/31, perPlayer, 0.003, "spreading the roll across the match must keep the per-match odds")
+}
+
+func TestInjuryClock_HighPressPicksUpLateKnocks(t *testing.T) {
+	lineup := injuryLineup()
+	meanMinute := func(ramp float64) float64 {
+		r := rand.New(rand.NewSource(3))
+		var total, n int
+		for i := 0; i < 5000; i++ {
+			clock := newInjuryClock(lineup, TeamTypeHome, 0, 1, ramp)
+			for m := 15; m <= tuning.InjuryMatchMinutes; m += 15 {
+				for _, inj := range clock.advance(r, lineup, m) {
+					total += inj.Minute
+					n++
+				}
+			}
+		}
+		require.NotZero(t, n)
+		return float64(total) / float64(n)
+	}
+	assert.Greater(t, meanMinute(pressInjuryRamp(PressLevelHigh)), meanMinute(pressInjuryRamp(PressLevelLow)))
+}
+
+func TestInjuryClock_EachPlayerIsInjuredOnce(t *testing.T) {
+	lineup := injuryLineup()
+	clock := newInjuryClock(lineup, TeamTypeAway, 0, 1, tuning.InjuryRampDefault)
+	for id := range clock.odds {
+		clock.odds[id] = 1 // certain injury
+	}
+	r := rand.New(rand.NewSource(1))
+
+	first := clock.advance(r, lineup, 30)
+	require.Len(t, first, len(lineup.Players))
+	for _, inj := range first {
+		assert.Equal(t, "team", inj.TeamID)
+		assert.Equal(t, TeamTypeAway, inj.TeamType)
+		assert.True(t, inj.Minute >= 1 && inj.Minute <= 30, "minute %d outside the window", inj.Minute)
+		assert.Positive(t, inj.DurationDays)
+	}
+	assert.Empty(t, clock.advance(r, lineup, tuning.InjuryMatchMinutes))
+}
+
+func TestMatchSide_InjuryCostsTheRestOfTheMatch(t *testing.T) {
+	home, away := injuryLineup(), injuryLineup()
+	side := newMatchSide(home, away, tuning.LookupTeamSizeScaling(len(home.Players)))
+	side.controlBoost, side.defenseBoost = 1, 1
+	side.rescore()
+	control, defense := side.control, side.defense[ChanceTypeOpenPlay]
+
+	side.injuries = newInjuryClock(home, TeamTypeHome, 0, 1, tuning.InjuryRampDefault)
+	for id := range side.injuries.odds {
+		side.injuries.odds[id] = 0
+	}
+	side.injuries.odds["2"] = 1 // the defender goes down
+	knocks := side.advanceInjuries(rand.New(rand.NewSource(1)), 40)
+
+	require.Len(t, knocks, 1)
+	assert.Less(t, side.control, control)
+	assert.Less(t, side.defense[ChanceTypeOpenPlay], defense)
+	require.NotNil(t, side.lineup.Players[1].Injury)
+	assert.LessOrEqual(t, knocks[0].Minute, 40)
+	assert.Nil(t, home.Players[1].Injury, "the caller's lineup is not modified")
+}
+
+func TestMatchSide_WorseExistingInjuryIsKept(t *testing.T) {
+	home, away := injuryLineup(), injuryLineup()
+	existing := &InjuryEvent{Injury: Injury{StatsReduction: 0.5}}
+	home.Players[1].Injury = existing
+	side := newMatchSide(home, away, tuning.LookupTeamSizeScaling(len(home.Players)))
+	side.injuries = newInjuryClock(home, TeamTypeHome, 0, 1, tuning.InjuryRampDefault)
+	for id := range side.injuries.odds {
+		side.injuries.odds[id] = 0
+	}
+	side.injuries.odds["2"] = 1
+
+	require.Len(t, side.advanceInjuries(rand.New(rand.NewSource(1)), 40), 1)
+	assert.Same(t, existing, side.lineup.Players[1].Injury)
+}
+
+func TestApplyInjuries_CarriesTheWorseInjury(t *testing.T) {
+	lineup := injuryLineup()
+	mild := InjuryEvent{PlayerID: "2", Injury: Injury{StatsReduction: 0.9}}
+	bad := InjuryEvent{PlayerID: "2", Injury: Injury{StatsReduction: 0.6}}
+	other := InjuryEvent{PlayerID: "not-playing", Injury: Injury{StatsReduction: 0.5}}
+
+	carried := ApplyInjuries(lineup, []InjuryEvent{mild, bad, other})
+	require.NotNil(t, carried.Players[1].Injury)
+	assert.Equal(t, bad, *carried.Players[1].Injury)
+	assert.Nil(t, lineup.Players[1].Injury, "the caller's lineup is not modified")
+
+	again := ApplyInjuries(carried, []InjuryEvent{mild})
+	assert.Equal(t, bad, *again.Players[1].Injury, "a milder knock doesn't replace a worse one")
+	for i, p := range carried.Players {
+		if p.ID != "2" {
+			assert.Nil(t, p.Injury, "player %d", i)
+		}
+	}
+}
+
+func TestInjuryCatalogue_EveryInjuryNamesItsAttributes(t *testing.T) {
+	for _, inj := range GetAllInjuries() {
+		assert.NotEmpty(t, inj.Affects, "%s affects no attributes", inj.Name)
+		assert.True(t, inj.StatsReduction > 0 && inj.StatsReduction < 1, "%s has no reduction", inj.Name)
+	}
+}
+
+func TestInjuredAttributes_OnlyTheNamedAttributesDrop(t *testing.T) {
+	sp := SelectedPlayer{Attributes: PlayerAttributes{
+		GoalkeeperRating: 20, DefenseRating: 60, SpeedRating: 80, ControlRating: 70, AttackRating: 90, Technique: 75,
+	}}
+	sp.Injury = &InjuryEvent{Injury: Injury{StatsReduction: 0.5, Affects: []Attribute{AttributeSpeed, AttributeComposure}}}
+
+	got := injuredAttributes(sp)
+	assert.Equal(t, 40, got.SpeedRating)
+	assert.Equal(t, 40, got.EffectiveWorkRate(), "an unset WorkRate keeps following SpeedRating")
+	assert.Equal(t, 35, got.EffectiveComposure(), "a fallback specialist is pinned before it is cut")
+	assert.Equal(t, 70, got.ControlRating, "…so its composite is untouched")
+	assert.Equal(t, 90, got.AttackRating)
+	assert.Equal(t, 75, got.EffectiveTechnique())
+	assert.Equal(t, 80, sp.Attributes.SpeedRating, "the player's own attributes are not modified")
+}
+
+func TestInjuredAttributes_BaseAndSpecialistAreCutOnce(t *testing.T) {
+	sp := SelectedPlayer{Attributes: PlayerAttributes{SpeedRating: 80}}
+	sp.Injury = &InjuryEvent{Injury: Injury{StatsReduction: 0.5, Affects: []Attribute{AttributeSpeed, AttributeWorkRate}}}
+
+	assert.Equal(t, 40, injuredAttributes(sp).EffectiveWorkRate())
+}
+
+// A hamstring costs a sprinter's breakaways and nothing in the air; a flat
+// (legacy) injury costs both.
+func TestInjury_AffectsOnlyTheChancesItsAttributesDrive(t *testing.T) {
+	attrs := PlayerAttributes{AttackRating: 80, SpeedRating: 90, Finishing: 80, Heading: 85}
+	healthy := SelectedPlayer{Attributes: attrs, SelectedPosition: PlayerPositionAttack}
+	hamstring := healthy
+	hamstring.Injury = &InjuryEvent{Injury: Injury{StatsReduction: 0.75, Affects: []Attribute{AttributeSpeed, AttributeWorkRate}}}
+	flat := healthy
+	flat.Injury = &InjuryEvent{Injury: Injury{StatsReduction: 0.75}}
+
+	assert.Less(t, playerAttackForChance(hamstring, ChanceTypeGoalKeeperShot), playerAttackForChance(healthy, ChanceTypeGoalKeeperShot))
+	assert.Equal(t, playerAttackForChance(healthy, ChanceTypeCorner), playerAttackForChance(hamstring, ChanceTypeCorner))
+	assert.Less(t, playerAttackForChance(flat, ChanceTypeCorner), playerAttackForChance(healthy, ChanceTypeCorner))
+}
+
+func TestInjury_HandInjuryOnlyHurtsTheKeeper(t *testing.T) {
+	hand := &InjuryEvent{Injury: Injury{StatsReduction: 0.75, Affects: []Attribute{AttributeGoalkeeper}}}
+
+	keeper := SelectedPlayer{Attributes: PlayerAttributes{GoalkeeperRating: 85, SpeedRating: 60, PrimaryPosition: PlayerPositionGoalkeeper}, SelectedPosition: PlayerPositionGoalkeeper}
+	injuredKeeper := keeper
+	injuredKeeper.Injury = hand
+	assert.Less(t, playerDefenseForChance(injuredKeeper, Tactics{}, ChanceTypeOpenPlay), playerDefenseForChance(keeper, Tactics{}, ChanceTypeOpenPlay))
+
+	striker := SelectedPlayer{Attributes: PlayerAttributes{GoalkeeperRating: 20, AttackRating: 85, SpeedRating: 80}, SelectedPosition: PlayerPositionAttack}
+	injuredStriker := striker
+	injuredStriker.Injury = hand
+	assert.Equal(t, playerAttackForChance(striker, ChanceTypeOpenPlay), playerAttackForChance(injuredStriker, ChanceTypeOpenPlay))
+}
+
+func TestInjuryClock_PlayerInjuryRiskRaisesTheOdds(t *testing.T) {
+	lineup := injuryLineup()
+	lineup.Players[1].InjuryRisk = 2
+	lineup.Players[2].InjuryRisk = 0 // unset ⇒ 1.0
+	clock := newInjuryClock(lineup, TeamTypeHome, 0, 1, tuning.InjuryRampDefault)
+
+	assert.Equal(t, injuryMatchChance(false, 0, 1), clock.odds["3"])
+	assert.Equal(t, injuryMatchChance(false, 0, 2), clock.odds["2"])
+	assert.Greater(t, clock.odds["2"], clock.odds["3"])
+}
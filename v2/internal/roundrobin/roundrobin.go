@@ -0,0 +1,120 @@
+// Package roundrobin plays the league part of the competitions built on the
+// league package: a season's divisions and a tournament's group stage. Both
+// play a set of round-robin groups side by side, a matchday at a time, and
+// keep each group's table up to date after every matchday.
+//
+// Streams come from the competition's source under the group's name:
+//
+//	<name>-R<r>-<m>  a match (the match's ID)
+//	<name>/lot       the group's drawing of lots
+package roundrobin
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/league"
+)
+
+// Group is one group as a matchday sees it: its name in match IDs and
+// stream labels, its teams and fixture list, and the matches played so far.
+type Group struct {
+	Name    string
+	Teams   []string
+	Rounds  []league.Round
+	Matches []league.Match
+}
+
+// Played is a group after a matchday: every match it has played, and the
+// table they make.
+type Played struct {
+	Matches   []league.Match
+	Standings []league.Standing
+}
+
+// Matchday returns how many matchdays have been played.
+func Matchday(groups []Group) int {
+	var played int
+	for _, g := range groups {
+		if len(g.Matches) == 0 {
+			continue
+		}
+		played = max(played, g.Matches[len(g.Matches)-1].Round)
+	}
+	return played
+}
+
+// Matchdays is the length of the league stage: groups of different sizes
+// have different numbers of rounds.
+func Matchdays(groups []Group) int {
+	var n int
+	for _, g := range groups {
+		n = max(n, len(g.Rounds))
+	}
+	return n
+}
+
+// PlayMatchday plays the next matchday in every group that has one and
+// ranks every group. lineups are keyed by team ID. The whole matchday is
+// played before anything is returned, and the groups aren't modified, so a
+// failure leaves the caller's state as it was. A missing lineup is
+// league.ErrMissingLineup.
+func PlayMatchday(groups []Group, lineups map[string]soccer.GameLineup, rules league.Rules, source func(label string) *rand.Rand) ([]Played, error) {
+	day := Matchday(groups) + 1
+	out := make([]Played, len(groups))
+	for i, g := range groups {
+		out[i].Matches = slices.Clone(g.Matches)
+		if day > len(g.Rounds) {
+			continue
+		}
+		for j, f := range g.Rounds[day-1].Fixtures {
+			m, err := play(g.Name, j, f, lineups, source)
+			if err != nil {
+				return nil, err
+			}
+			out[i].Matches = append(out[i].Matches, m)
+		}
+	}
+	for i, g := range groups {
+		g.Matches = out[i].Matches
+		table, err := Rank(g, rules, source)
+		if err != nil {
+			return nil, err
+		}
+		out[i].Standings = table
+	}
+	return out, nil
+}
+
+// Rank returns a group's table from its matches.
+func Rank(g Group, rules league.Rules, source func(label string) *rand.Rand) ([]league.Standing, error) {
+	results := make([]league.Result, len(g.Matches))
+	for i, m := range g.Matches {
+		results[i] = m.Result()
+	}
+	table, err := league.Standings(g.Teams, results, source(g.Name+"/lot").Int63(), rules)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", g.Name, err)
+	}
+	return table, nil
+}
+
+// play plays one match. League matches can be drawn; there is no shootout.
+func play(group string, i int, f league.Fixture, lineups map[string]soccer.GameLineup, source func(label string) *rand.Rand) (league.Match, error) {
+	id := fmt.Sprintf("%s-R%d-%d", group, f.Round, i+1)
+	home, ok := lineups[f.Home]
+	if !ok {
+		return league.Match{}, fmt.Errorf("%w: %q", league.ErrMissingLineup, f.Home)
+	}
+	away, ok := lineups[f.Away]
+	if !ok {
+		return league.Match{}, fmt.Errorf("%w: %q", league.ErrMissingLineup, f.Away)
+	}
+	events, injuries, err := soccer.RunGameWithSeed(source(id), home, away)
+	if err != nil {
+		return league.Match{}, fmt.Errorf("match %s: %w", id, err)
+	}
+	return league.Match{ID: id, Fixture: f, Events: events, Injuries: injuries}, nil
+}
@@ -0,0 +1,75 @@
+package roundrobin_test
+
+import (
+	"math/rand"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/algorand"
+	"github.com/stein-f/oink-soccer-common/v2/internal/roundrobin"
+	"github.com/stein-f/oink-soccer-common/v2/league"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func source(label string) *rand.Rand {
+	return algorand.SeedFromBlockHash(testdata.BlockHash + "/" + label)
+}
+
+// groups draws a four-team and a three-team group, so the smaller one has
+// fewer matchdays.
+func groups(t *testing.T) ([]roundrobin.Group, map[string]soccer.GameLineup) {
+	t.Helper()
+	ids, lineups := testdata.Field("team-", 7, 4)
+	rules := league.Rules{Format: league.FormatSingle}
+	gs := []roundrobin.Group{{Name: "A", Teams: ids[:4]}, {Name: "B", Teams: ids[4:]}}
+	for i := range gs {
+		rounds, err := league.Schedule(source(gs[i].Name+"/schedule"), gs[i].Teams, rules)
+		require.NoError(t, err)
+		gs[i].Rounds = rounds
+	}
+	return gs, lineups
+}
+
+func TestPlayMatchday_PlaysEveryGroupInStep(t *testing.T) {
+	gs, lineups := groups(t)
+	rules := league.DefaultRules()
+	assert.Equal(t, 3, roundrobin.Matchdays(gs))
+
+	for day := 1; day <= roundrobin.Matchdays(gs); day++ {
+		played, err := roundrobin.PlayMatchday(gs, lineups, rules, source)
+		require.NoError(t, err)
+		for i := range gs {
+			gs[i].Matches = played[i].Matches
+		}
+		assert.Equal(t, day, roundrobin.Matchday(gs))
+	}
+
+	assert.Len(t, gs[0].Matches, 6)
+	assert.Len(t, gs[1].Matches, 3)
+	m := gs[0].Matches[0]
+	assert.Equal(t, "A-R1-1", m.ID)
+	events, _, err := soccer.RunGameWithSeed(source(m.ID), lineups[m.Home], lineups[m.Away])
+	require.NoError(t, err)
+	assert.Equal(t, events, m.Events, "each match is replayable from its ID")
+
+	table, err := roundrobin.Rank(gs[0], rules, source)
+	require.NoError(t, err)
+	var played int
+	for _, row := range table {
+		played += row.Played
+	}
+	assert.Equal(t, 12, played)
+}
+
+func TestPlayMatchday_FailureChangesNothing(t *testing.T) {
+	gs, lineups := groups(t)
+	delete(lineups, gs[1].Rounds[0].Fixtures[0].Away)
+
+	played, err := roundrobin.PlayMatchday(gs, lineups, league.DefaultRules(), source)
+	assert.ErrorIs(t, err, league.ErrMissingLineup)
+	assert.Nil(t, played)
+	assert.Empty(t, gs[0].Matches)
+	assert.Zero(t, roundrobin.Matchday(gs))
+}
@@ -32,13 +32,15 @@ const OutOfPositionScale = 0.85
 
 // --- Injury severity stat reductions ----------------------------------------
 
-// StatsReductionLow / Med / High describe how much an injured player's
-// active-match stats are scaled by while carrying an injury of that severity.
-// Lower number = larger penalty.
+// StatsReductionLow / Med / High describe how much the attributes an injury
+// of that severity affects are scaled by while the player carries it. Lower
+// number = larger penalty. Only the injury's own attributes drop, so the cut
+// is steeper than v1's flat 0.95 / 0.90 / 0.85 across every score — a
+// hamstring costs a lot of pace and nothing in the air.
 const (
-	StatsReductionLow  = 0.95
-	StatsReductionMed  = 0.90
-	StatsReductionHigh = 0.85
+	StatsReductionLow  = 0.88
+	StatsReductionMed  = 0.75
+	StatsReductionHigh = 0.65
 )
 
 // --- Injury probability weights ---------------------------------------------
@@ -55,6 +57,36 @@ const (
 // aggression=100 cuts the no-injury weight in half (doubling injury odds).
 const AggressionMaxNoInjuryReduction = 0.5
 
+// --- In-match injury timing -------------------------------------------------
+
+// A player's per-match injury odds are spread across InjuryMatchMinutes
+// rather than rolled once at full time. The hazard ramps linearly from
+// (1-ramp) at kick-off to (1+ramp) at the final whistle — tired legs pick up
+// more knocks — while a full match still carries exactly the per-match odds.
+// Stoppage time carries no extra risk.
+const InjuryMatchMinutes = 90
+
+// Ramps per press level: the harder a team presses, the more of its injury
+// risk lands in the closing stages. Must stay within [0, 1].
+const (
+	InjuryRampLowPress  = 0.3
+	InjuryRampDefault   = 0.5
+	InjuryRampHighPress = 0.8
+)
+
+// InjuryExposure returns the share of a full match's injury risk carried by
+// minutes (from, to] for the given ramp.
+func InjuryExposure(from, to int, ramp float64) float64 {
+	n := float64(InjuryMatchMinutes)
+	a := math.Max(0, math.Min(n, float64(from)))
+	b := math.Max(0, math.Min(n, float64(to)))
+	if b <= a {
+		return 0
+	}
+	// ∫ 1 + ramp·(2m/n − 1) dm over [a, b], normalised by n.
+	return ((b - a) + ramp*((b*b-a*a)/n-(b-a))) / n
+}
+
 // --- Defensive bias ---------------------------------------------------------
 
 // DefenseBiasMultiplier was a v1 fudge factor multiplied onto every team's
@@ -97,9 +129,10 @@ const (
 // shift weight toward work rate; passive teams lean further on skill. The
 // neutral baseline mirrors the legacy ControlSkillWeight/ControlSpeedWeight.
 type ControlWeights struct {
-	Skill    int
-	Physical int
-	Divisor  int
+	Skill     int
+	Physical  int
+	Technique int // zero unless the passing style asks for it
+	Divisor   int
 }
 
 // ControlWeightsForPress returns the (skill, physical, divisor) tuple used
@@ -120,6 +153,21 @@ func ControlWeightsForPress(pressLevel string) ControlWeights {
 	}
 }
 
+// ControlWeightsForPassingStyle layers the team's passing style on top of
+// the press weighting:
+//
+//   - Possession: keeping the ball under pressure is a technical job —
+//     Technique joins the control formula.
+//   - Direct / balanced / none: the press weights unchanged. Direct play
+//     bypasses midfield rather than asking it to do something different.
+func ControlWeightsForPassingStyle(style string, base ControlWeights) ControlWeights {
+	if style == "possession" {
+		base.Technique += 2
+		base.Divisor += 2
+	}
+	return base
+}
+
 // DefenseWeights describes how an outfield defender's score is built from
 // DefenseRating (positioning), Tackling (active dispossess), and Recovery
 // (chase / catch-up speed). Goalkeepers don't use this — they stay on the
@@ -362,6 +410,54 @@ var FormationChanceRanges = map[string]ChanceRange{
 // FallbackChanceRange is used if a formation-pair isn't in the table above.
 var FallbackChanceRange = ChanceRange{Min: 3, Max: 10}
 
+// Scale multiplies both ends of the range by f, rounding to the nearest
+// chance and never dropping below 1. Scale(1) returns the range unchanged.
+func (r ChanceRange) Scale(f float64) ChanceRange {
+	if f == 1 {
+		return r
+	}
+	scaled := ChanceRange{Min: int(math.Round(float64(r.Min) * f)), Max: int(math.Round(float64(r.Max) * f))}
+	if scaled.Min < 1 {
+		scaled.Min = 1
+	}
+	if scaled.Max < scaled.Min {
+		scaled.Max = scaled.Min
+	}
+	return scaled
+}
+
+// --- Team size ---------------------------------------------------------------
+
+// TeamSizeScaling normalises a match for the number of players per side.
+// The chance-range table and every profile were tuned for five-a-side.
+// Team scores are position-group means, so they don't grow with the squad.
+// A bigger pitch still produces more chances, though, and more bodies
+// behind the ball make each one harder to convert:
+//   - ChanceVolume multiplies both ends of the FormationChanceRanges entry
+//   - DefenseScale multiplies both teams' defense, pulling conversion back
+//     so goals per match stay in the five-a-side range
+type TeamSizeScaling struct {
+	ChanceVolume float64
+	DefenseScale float64
+}
+
+// TeamSizeScalings lists the supported team sizes. Formations with any
+// other number of slots are rejected at registration.
+var TeamSizeScalings = map[int]TeamSizeScaling{
+	5:  {ChanceVolume: 1.00, DefenseScale: 1.00},
+	7:  {ChanceVolume: 1.25, DefenseScale: 1.60},
+	11: {ChanceVolume: 1.60, DefenseScale: 2.40},
+}
+
+// LookupTeamSizeScaling returns the scaling for a team size, falling back
+// to the five-a-side identity when the size isn't known.
+func LookupTeamSizeScaling(size int) TeamSizeScaling {
+	if s, ok := TeamSizeScalings[size]; ok {
+		return s
+	}
+	return TeamSizeScalings[5]
+}
+
 // --- Event-minute distribution ----------------------------------------------
 
 // EventMinuteBucket weights when in the match an event is likely to occur.
@@ -381,6 +477,19 @@ var EventMinuteBuckets = []EventMinuteBucket{
 	{MinMinute: 76, MaxMinute: 98, Weight: 254},
 }
 
+// --- Extra time -------------------------------------------------------------
+
+// ExtraTimeChanceShare is extra time's chance volume as a share of a full
+// match. Thirty minutes is a third of ninety; tired legs and cagey
+// knockout football trim it a little further.
+const ExtraTimeChanceShare = 0.3
+
+// Extra-time events fall evenly across these minutes.
+const (
+	ExtraTimeFirstMinute = 91
+	ExtraTimeLastMinute  = 120
+)
+
 // --- Formation balance profiles ---------------------------------------------
 
 // FormationProfile is the trade-off matrix for a tactical shape. Every value
@@ -437,10 +546,29 @@ var FormationProfiles = map[string]FormationProfile{
 	// DefSolidity claws most of that back. Tuned against the balance
 	// harness (±3% win-rate spread, RUN_BALANCE_STRICT=1).
 	"The Box": {Possession: 1.12, ChanceCreation: 1.05, ChanceQuality: 1.05, DefSolidity: 0.90, InjuryRisk: 1.05},
+
+	// Seven- and eleven-a-side shapes start from the five-a-side archetype
+	// they resemble: the balanced shape takes the possession edge, the
+	// defensive shape trades volume for solidity and quality, the attacking
+	// shape pays in defense for volume and quality.
+
+	// "The Arrow" (2-3-1) — seven-a-side balanced; the Diamond's role.
+	"The Arrow": {Possession: 1.03, ChanceCreation: 1.00, ChanceQuality: 1.00, DefSolidity: 1.00, InjuryRisk: 1.00},
+	// "The Wall" (3-2-1) — seven-a-side defensive; the Pyramid's role.
+	"The Wall": {Possession: 1.00, ChanceCreation: 1.00, ChanceQuality: 1.03, DefSolidity: 1.02, InjuryRisk: 1.00},
+	// "The Fork" (2-2-2) — seven-a-side attacking; the Y's role.
+	"The Fork": {Possession: 1.00, ChanceCreation: 1.03, ChanceQuality: 1.02, DefSolidity: 0.97, InjuryRisk: 1.00},
+
+	// "The Classic" (4-4-2) — eleven-a-side balanced.
+	"The Classic": {Possession: 1.03, ChanceCreation: 1.00, ChanceQuality: 1.00, DefSolidity: 1.00, InjuryRisk: 1.00},
+	// "The Fortress" (5-3-2) — eleven-a-side defensive.
+	"The Fortress": {Possession: 1.00, ChanceCreation: 1.00, ChanceQuality: 1.03, DefSolidity: 1.02, InjuryRisk: 1.00},
+	// "The Trident" (4-3-3) — eleven-a-side attacking.
+	"The Trident": {Possession: 1.00, ChanceCreation: 1.03, ChanceQuality: 1.02, DefSolidity: 0.97, InjuryRisk: 1.00},
 }
 
-// NeutralProfile is returned for unknown formations so the engine can never
-// nil-deref on a profile lookup.
+// NeutralProfile is returned for unknown formations so a profile lookup can
+// never nil-deref. The engine itself rejects unregistered formations.
 var NeutralProfile = FormationProfile{
 	Possession: 1, ChanceCreation: 1, ChanceQuality: 1, DefSolidity: 1, InjuryRisk: 1,
 }
@@ -454,3 +582,394 @@ func LookupFormationProfile(name string) FormationProfile {
 	}
 	return NeutralProfile
 }
+
+// --- Chance-type mix ---------------------------------------------------------
+
+// ChanceMix is a set of per-chance-type multipliers applied on top of the
+// base chance-type weights (see chanceTypeProfiles in the soccer package).
+// Keyed by string(ChanceType) so this package stays import-cycle-free. A
+// missing key means 1.0 — a mix only lists the chance types it shifts.
+type ChanceMix map[string]float64
+
+// Factor returns the multiplier for a chance type, defaulting to 1.0.
+func (m ChanceMix) Factor(chanceType string) float64 {
+	if f, ok := m[chanceType]; ok {
+		return f
+	}
+	return 1.0
+}
+
+// FormationChanceMixes conditions the chance-type distribution on the
+// attacking team's shape. Before this table every formation drew from the
+// same fixed weights, so a Box with two target men produced exactly as many
+// crosses as a Diamond of playmakers.
+//
+// Each mix shifts weight around rather than adding it — the number of
+// chances is decided by the tempo phase, so a formation that gets more of
+// one chance type gets fewer of the others. The shifts are modest; chance
+// *volume* and *quality* stay the job of FormationProfile.
+var FormationChanceMixes = map[string]ChanceMix{
+	// Pyramid sits deep and breaks: more 1-on-1 breakaways and shots from
+	// distance, fewer patient open-play moves.
+	"The Pyramid": {"Goalkeeper Shot": 1.20, "Long Range": 1.10, "Open Play": 0.95, "Cross": 0.90},
+
+	// Diamond works the ball through the middle: open play and long range
+	// from the midfield pair, fewer balls into the box.
+	"The Diamond": {"Open Play": 1.15, "Long Range": 1.10, "Cross": 0.90, "Corner": 0.90},
+
+	// Y's front two attack the box from both channels — more crosses and
+	// combination play around the box, fewer shots from distance.
+	"The Y": {"Cross": 1.15, "Open Play": 1.05, "Long Range": 0.80},
+
+	// Box is wide and direct: no midfield to shoot from distance, two
+	// strikers attacking deliveries and winning set pieces.
+	"The Box": {"Cross": 1.20, "Corner": 1.15, "Open Play": 0.95, "Long Range": 0.80},
+
+	// Arrow's three-man midfield plays through the middle, like the Diamond.
+	"The Arrow": {"Open Play": 1.15, "Long Range": 1.05, "Cross": 0.90},
+	// Wall defends deep and breaks, like the Pyramid.
+	"The Wall": {"Goalkeeper Shot": 1.20, "Long Range": 1.10, "Open Play": 0.95, "Cross": 0.90},
+	// Fork's front pair attack deliveries, like the Y.
+	"The Fork": {"Cross": 1.15, "Open Play": 1.05, "Long Range": 0.80},
+
+	// Classic's wide midfielders deliver for the front two.
+	"The Classic": {"Cross": 1.10, "Corner": 1.05, "Long Range": 0.95},
+	// Fortress soaks up pressure and counters through the front two.
+	"The Fortress": {"Goalkeeper Shot": 1.20, "Long Range": 1.10, "Open Play": 0.95, "Cross": 0.90},
+	// Trident's wingers stretch the back line: crosses and balls in behind.
+	"The Trident": {"Cross": 1.10, "Goalkeeper Shot": 1.10, "Long Range": 0.85},
+}
+
+// LookupChanceMix returns the chance mix for a formation name, falling back
+// to an empty (neutral) mix when the formation isn't known.
+func LookupChanceMix(name string) ChanceMix {
+	if m, ok := FormationChanceMixes[name]; ok {
+		return m
+	}
+	return ChanceMix{}
+}
+
+// ChanceMixForTempo returns the chance-type shift for a team's tempo.
+//
+//   - Fast: transitions before the defense is set — more breakaways, fewer
+//     speculative long-range efforts.
+//   - Slow: the opponent is always set — breakaways dry up, the team works
+//     open-play openings and shoots from distance instead.
+//   - Normal / none: no shift.
+func ChanceMixForTempo(tempo string) ChanceMix {
+	switch tempo {
+	case "fast":
+		return ChanceMix{"Goalkeeper Shot": 1.30, "Long Range": 0.90}
+	case "slow":
+		return ChanceMix{"Goalkeeper Shot": 0.75, "Open Play": 1.05, "Long Range": 1.10}
+	default: // none, normal
+		return ChanceMix{}
+	}
+}
+
+// Lineup-driven chance mix. An aerial front line (EffectiveHeading of the
+// attackers) draws more crosses and corners; a technical midfield
+// (EffectiveTechnique of the midfielders) draws more open play and long
+// range. The factor is linear around a neutral rating and clamped so a
+// single specialist can't take over the distribution:
+//
+//	rating  factor
+//	   95    1.15
+//	   85    1.09
+//	   70    1.00 (neutral)
+//	   50    0.88
+//	   30    0.85 (clamped)
+const (
+	ChanceMixLineupNeutral   = 70
+	ChanceMixLineupGain      = 0.60
+	ChanceMixLineupMinFactor = 0.85
+	ChanceMixLineupMaxFactor = 1.20
+)
+
+// ChanceMixLineupFactor returns the chance-type multiplier for a lineup's
+// average specialist rating (0-100).
+func ChanceMixLineupFactor(rating float64) float64 {
+	f := 1.0 + (rating-ChanceMixLineupNeutral)/100.0*ChanceMixLineupGain
+	if f < ChanceMixLineupMinFactor {
+		return ChanceMixLineupMinFactor
+	}
+	if f > ChanceMixLineupMaxFactor {
+		return ChanceMixLineupMaxFactor
+	}
+	return f
+}
+
+// A team that tags a Ball Winner plays through them to win the ball back,
+// and its breakaways come from the balls they win. BallWinnerBreakaway*
+// shape BallWinnerBreakawayFactor, the team's own 1-on-1 breakaway weight
+// from the Ball Winner's EffectiveTackling. It is wider than the lineup
+// factor because a poor tackler in that role is the cost of the tag:
+//
+//	tackling  factor
+//	   95      1.25
+//	   70      1.00 (neutral)
+//	   50      0.80
+//	   30      0.60 (clamped)
+const (
+	BallWinnerBreakawayNeutral   = 70
+	BallWinnerBreakawayGain      = 1.0
+	BallWinnerBreakawayMinFactor = 0.60
+	BallWinnerBreakawayMaxFactor = 1.30
+)
+
+// BallWinnerBreakawayFactor returns the breakaway multiplier for a team's
+// Ball Winner's tackling (0-100).
+func BallWinnerBreakawayFactor(tackling float64) float64 {
+	f := 1.0 + (tackling-BallWinnerBreakawayNeutral)/100.0*BallWinnerBreakawayGain
+	if f < BallWinnerBreakawayMinFactor {
+		return BallWinnerBreakawayMinFactor
+	}
+	if f > BallWinnerBreakawayMaxFactor {
+		return BallWinnerBreakawayMaxFactor
+	}
+	return f
+}
+
+// ChanceMixForWidth returns the chance-type shift for a team's width when
+// it has the ball.
+//
+//   - Wide: full-backs and wingers hug the touchline — more crosses and
+//     the corners they win, fewer moves through the middle.
+//   - Narrow: overloads the centre — more open play, few deliveries.
+//   - Normal / none: no shift.
+func ChanceMixForWidth(width string) ChanceMix {
+	switch width {
+	case "wide":
+		return ChanceMix{"Cross": 1.25, "Corner": 1.10, "Open Play": 0.90}
+	case "narrow":
+		return ChanceMix{"Open Play": 1.15, "Cross": 0.80, "Corner": 0.90}
+	default:
+		return ChanceMix{}
+	}
+}
+
+// OpponentChanceMixForWidth returns the shift a team's width imposes on the
+// *opponent's* chance mix. A narrow shape leaves the flanks open, so the
+// opponent gets more crosses; a wide shape covers them.
+func OpponentChanceMixForWidth(width string) ChanceMix {
+	switch width {
+	case "narrow":
+		return ChanceMix{"Cross": 1.20}
+	case "wide":
+		return ChanceMix{"Cross": 0.90}
+	default:
+		return ChanceMix{}
+	}
+}
+
+// ChanceMixForPassingStyle returns the chance-type shift for a team's
+// passing style when it has the ball.
+//
+//   - Direct: early balls forward — more long-range efforts and runs in
+//     behind, fewer worked openings.
+//   - Possession: patient build-up — more open play, the defense is always
+//     set so breakaways and speculative shots dry up.
+//   - Balanced / none: no shift.
+func ChanceMixForPassingStyle(style string) ChanceMix {
+	switch style {
+	case "direct":
+		return ChanceMix{"Long Range": 1.25, "Goalkeeper Shot": 1.30, "Open Play": 0.85}
+	case "possession":
+		return ChanceMix{"Open Play": 1.15, "Long Range": 0.85, "Goalkeeper Shot": 0.85}
+	default:
+		return ChanceMix{}
+	}
+}
+
+// OpponentChanceMixForPassingStyle returns the shift a team's passing style
+// imposes on the *opponent's* chance mix. A possession side commits bodies
+// forward and is caught on the counter when it loses the ball.
+func OpponentChanceMixForPassingStyle(style string) ChanceMix {
+	switch style {
+	case "possession":
+		return ChanceMix{"Goalkeeper Shot": 1.50}
+	case "direct":
+		return ChanceMix{"Goalkeeper Shot": 0.85}
+	default:
+		return ChanceMix{}
+	}
+}
+
+// --- Man-marking -------------------------------------------------------------
+
+// Man-marking assigns one defender to shadow a named opponent. The marker's
+// EffectiveTackling sets how tight the job is: below MarkingTacklingFloor the
+// marker achieves nothing, at 100 the target gets the full cuts.
+//
+//   - MarkingMaxSelectionCut — fraction of the target's attacker-pick weight
+//     removed (they see less of the ball).
+//   - MarkingMaxConversionCut — fraction of the target's attack score
+//     removed on the chances they still get (no time to shoot).
+//   - MarkerDefenseScale — the marker's own defense score in teamDefense.
+//     A man-marker follows one player and leaves gaps elsewhere; this is
+//     the cost that keeps marking from being a free lunch. Marking a
+//     player who isn't the opponent's main threat loses more than it saves.
+const (
+	MarkingTacklingFloor    = 40
+	MarkingMaxSelectionCut  = 0.50
+	MarkingMaxConversionCut = 0.15
+	MarkerDefenseScale      = 0.85
+)
+
+// MarkingStrength maps a marker's tackling to 0..1.
+func MarkingStrength(tackling int) float64 {
+	s := float64(tackling-MarkingTacklingFloor) / float64(100-MarkingTacklingFloor)
+	switch {
+	case s < 0:
+		return 0
+	case s > 1:
+		return 1
+	}
+	return s
+}
+
+// MarkingSelectionFactor is the multiplier on the marked player's
+// attacker-pick weight.
+func MarkingSelectionFactor(tackling int) float64 {
+	return 1 - MarkingMaxSelectionCut*MarkingStrength(tackling)
+}
+
+// MarkingConversionFactor is the multiplier on the marked player's attack
+// score when they do get the chance.
+func MarkingConversionFactor(tackling int) float64 {
+	return 1 - MarkingMaxConversionCut*MarkingStrength(tackling)
+}
+
+// --- Rebounds ----------------------------------------------------------------
+
+// A keeper with a Handling rating can spill a save into a second ball. The
+// follow-up falls to the shooter and is as good a chance as the first. At
+// ReboundHandlingNeutral the keeper spills ReboundSpillNeutral of their
+// saves, and the engine takes those rebound goals out of the first shot, so
+// the goal odds are the legacy ones; better hands spill less and concede
+// less, worse hands more:
+//
+//	handling  spilled
+//	   95      2.5%
+//	   70     10.0% (neutral)
+//	   40     19.0%
+//	   15     25.0% (clamped)
+const (
+	ReboundHandlingNeutral = 70
+	ReboundSpillNeutral    = 0.10
+	ReboundSpillGain       = 0.30
+	ReboundSpillMin        = 0.02
+	ReboundSpillMax        = 0.25
+)
+
+// ReboundSpill returns the share of saves a keeper with the given Handling
+// spills.
+func ReboundSpill(handling int) float64 {
+	s := ReboundSpillNeutral + float64(ReboundHandlingNeutral-handling)/100.0*ReboundSpillGain
+	return math.Max(ReboundSpillMin, math.Min(ReboundSpillMax, s))
+}
+
+// --- Penalty mind games ------------------------------------------------------
+
+// A penalty is a guessing game. The taker picks a side (left / mid / right),
+// the keeper picks a dive, and the outcome depends on whether they match:
+//
+//   - Keeper guessed right: the shot/save duel q = atk/(atk+def) decides,
+//     sharpened to q^PenaltyMatchedDiveExponent — a keeper going the right
+//     way saves plenty.
+//   - Keeper guessed wrong: almost always a goal; a trailing leg still
+//     stops PenaltyWrongWaySaveScale of what the duel would.
+//   - Before either, the taker can miss the target. Corners are riskier
+//     than down the middle; Technique shrinks the risk.
+//
+// Tendencies shape the guesses. A taker's Composure sets their appetite for
+// going down the middle; a keeper stays up PenaltyKeeperStayWeight of the
+// time. A PenaltySide preference on either player shifts
+// PenaltyPreferredSideBias of their weight onto that side. A keeper given
+// the taker's history reads it with Positioning, up to PenaltyMaxRead once
+// PenaltyHistoryFullSample kicks are known.
+//
+// At neutral ratings (q ≈ 0.75) conversion lands around 80%, in line with
+// the single atk/(atk+def) roll this model replaced.
+const (
+	PenaltyTakerMidWeight       = 0.20
+	PenaltyTakerMidMin          = 0.05
+	PenaltyTakerMidMax          = 0.35
+	PenaltyMidComposureNeutral  = 70
+	PenaltyKeeperStayWeight     = 0.10
+	PenaltyPreferredSideBias    = 0.30
+	PenaltyMaxRead              = 0.60
+	PenaltyHistoryFullSample    = 5
+	PenaltyOffTargetCorner      = 0.06
+	PenaltyOffTargetMid         = 0.01
+	PenaltyOffTargetTechNeutral = 50
+	PenaltyOffTargetMax         = 0.50
+	PenaltyMatchedDiveExponent  = 2.5
+	PenaltyWrongWaySaveScale    = 0.15
+)
+
+// PenaltyTakerMidShare returns the share of a taker's kicks aimed down the
+// middle, from their composure. Only a composed taker risks it.
+func PenaltyTakerMidShare(composure int) float64 {
+	return clamp(PenaltyTakerMidWeight*float64(composure)/PenaltyMidComposureNeutral, PenaltyTakerMidMin, PenaltyTakerMidMax)
+}
+
+// PenaltyOffTargetChance returns the chance a kick misses the target,
+// from where it's aimed and the taker's technique.
+func PenaltyOffTargetChance(corner bool, technique int) float64 {
+	base := PenaltyOffTargetMid
+	if corner {
+		base = PenaltyOffTargetCorner
+	}
+	return clamp(base*float64(100-technique)/PenaltyOffTargetTechNeutral, 0, PenaltyOffTargetMax)
+}
+
+// PenaltyReadStrength returns how far a keeper leans on the taker's history
+// (0..PenaltyMaxRead), from the number of known kicks and the keeper's
+// positioning.
+func PenaltyReadStrength(samples, positioning int) float64 {
+	sample := math.Min(1, float64(samples)/PenaltyHistoryFullSample)
+	return PenaltyMaxRead * sample * clamp(float64(positioning)/100, 0, 1)
+}
+
+// PenaltyGoalChance returns the chance an on-target kick goes in, given the
+// shot/save duel q and whether the keeper dived the right way.
+func PenaltyGoalChance(q float64, matched bool) float64 {
+	if matched {
+		return math.Pow(q, PenaltyMatchedDiveExponent)
+	}
+	return 1 - (1-q)*PenaltyWrongWaySaveScale
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// --- Shootout pressure -------------------------------------------------------
+
+// Pressure scales how much a taker's Composure matters in a shootout. The
+// first three kicks are routine (pressure 0); kicks four and five carry
+// PenaltyPressureLateKick and sudden death PenaltyPressureSuddenDeath. At
+// full pressure every point of Composure away from PenaltyMidComposureNeutral
+// moves the taker's attack by PenaltyPressureComposureGain/100 — a composed
+// taker rises to it, a nervous one shrinks.
+//
+// On top of that, a taker who must score to keep the shootout alive loses
+// up to PenaltyMustScoreMaxCut of their attack, less the more composed they
+// are (nothing at Composure 100).
+const (
+	PenaltyPressureLateKick      = 0.5
+	PenaltyPressureSuddenDeath   = 1.0
+	PenaltyPressureComposureGain = 0.60
+	PenaltyMustScoreMaxCut       = 0.25
+)
+
+// PenaltyPressureFactor returns the multiplier on a shootout taker's attack
+// score for the given pressure (0..1) and composure.
+func PenaltyPressureFactor(composure int, pressure float64, mustScore bool) float64 {
+	f := 1 + pressure*float64(composure-PenaltyMidComposureNeutral)/100*PenaltyPressureComposureGain
+	if mustScore {
+		f *= 1 - PenaltyMustScoreMaxCut*clamp(float64(100-composure)/100, 0, 1)
+	}
+	return math.Max(f, 0.1)
+}
@@ -32,6 +32,20 @@ func TestStatsReductionOrdering(t *testing.T) {
 	assert.Greater(t, tuning.StatsReductionHigh, 0.0)
 }
 
+func TestInjuryExposure(t *testing.T) {
+	for _, ramp := range []float64{0, tuning.InjuryRampLowPress, tuning.InjuryRampDefault, tuning.InjuryRampHighPress} {
+		full := tuning.InjuryExposure(0, tuning.InjuryMatchMinutes, ramp)
+		assert.InDelta(t, 1.0, full, 1e-9, "a full match must carry the per-match odds (ramp %v)", ramp)
+
+		split := tuning.InjuryExposure(0, 37, ramp) + tuning.InjuryExposure(37, tuning.InjuryMatchMinutes, ramp)
+		assert.InDelta(t, full, split, 1e-9, "exposure must add up across windows (ramp %v)", ramp)
+	}
+	assert.Greater(t, tuning.InjuryExposure(75, 90, tuning.InjuryRampDefault), tuning.InjuryExposure(0, 15, tuning.InjuryRampDefault), "late minutes carry more risk")
+	assert.Greater(t, tuning.InjuryExposure(75, 90, tuning.InjuryRampHighPress), tuning.InjuryExposure(75, 90, tuning.InjuryRampDefault))
+	assert.Equal(t, 0.0, tuning.InjuryExposure(90, 98, tuning.InjuryRampDefault), "stoppage time carries no extra risk")
+	assert.Equal(t, 0.0, tuning.InjuryExposure(50, 50, tuning.InjuryRampDefault))
+}
+
 func TestBoostDecayBounds(t *testing.T) {
 	assert.Greater(t, tuning.BoostDecay, 0.0)
 	assert.Less(t, tuning.BoostDecay, 1.0)
@@ -48,6 +62,29 @@ func TestChanceRangesValid(t *testing.T) {
 	assert.LessOrEqual(t, tuning.FallbackChanceRange.Min, tuning.FallbackChanceRange.Max)
 }
 
+func TestChanceRangeScale(t *testing.T) {
+	r := tuning.ChanceRange{Min: 5, Max: 10}
+	assert.Equal(t, r, r.Scale(1))
+	assert.Equal(t, tuning.ChanceRange{Min: 8, Max: 16}, r.Scale(1.6))
+	assert.Equal(t, tuning.ChanceRange{Min: 1, Max: 1}, tuning.ChanceRange{Min: 1, Max: 2}.Scale(0.1))
+}
+
+// Five-a-side is the tuning baseline: its scaling must be the identity so
+// existing matches are unchanged, and larger teams must create more chances
+// and defend them harder.
+func TestTeamSizeScalings(t *testing.T) {
+	assert.Equal(t, tuning.TeamSizeScaling{ChanceVolume: 1, DefenseScale: 1}, tuning.TeamSizeScalings[5])
+	assert.Equal(t, tuning.TeamSizeScalings[5], tuning.LookupTeamSizeScaling(6))
+	prev := tuning.TeamSizeScalings[5]
+	for _, size := range []int{7, 11} {
+		s, ok := tuning.TeamSizeScalings[size]
+		assert.True(t, ok, "%d-a-side missing", size)
+		assert.Greater(t, s.ChanceVolume, prev.ChanceVolume)
+		assert.Greater(t, s.DefenseScale, prev.DefenseScale)
+		prev = s
+	}
+}
+
 func TestSkillCurve_ShapeAndBounds(t *testing.T) {
 	// Curve must be monotonically increasing, bounded to [floor, 100], and
 	// strictly convex (the whole point — amplifies skill differential).
@@ -89,3 +126,110 @@ func TestEventMinuteBucketsCoverMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestChanceMixes_PositiveAndBounded(t *testing.T) {
+	// A zero or negative factor would delete a chance type outright; mixes
+	// only ever reshape the distribution.
+	for name, mix := range tuning.FormationChanceMixes {
+		for ct, f := range mix {
+			assert.Greater(t, f, 0.0, "%s mix entry %q must be positive", name, ct)
+		}
+	}
+	for _, tempo := range []string{"", "slow", "normal", "fast"} {
+		for ct, f := range tuning.ChanceMixForTempo(tempo) {
+			assert.Greater(t, f, 0.0, "tempo %q mix entry %q must be positive", tempo, ct)
+		}
+	}
+	for _, width := range []string{"", "narrow", "normal", "wide"} {
+		for _, mix := range []tuning.ChanceMix{tuning.ChanceMixForWidth(width), tuning.OpponentChanceMixForWidth(width)} {
+			for ct, f := range mix {
+				assert.Greater(t, f, 0.0, "width %q mix entry %q must be positive", width, ct)
+			}
+		}
+	}
+	for _, style := range []string{"", "direct", "balanced", "possession"} {
+		for _, mix := range []tuning.ChanceMix{tuning.ChanceMixForPassingStyle(style), tuning.OpponentChanceMixForPassingStyle(style)} {
+			for ct, f := range mix {
+				assert.Greater(t, f, 0.0, "passing style %q mix entry %q must be positive", style, ct)
+			}
+		}
+	}
+
+	// Lineup factor: neutral at the neutral rating, monotonic, clamped.
+	assert.Equal(t, 1.0, tuning.ChanceMixLineupFactor(tuning.ChanceMixLineupNeutral))
+	assert.Equal(t, tuning.ChanceMixLineupMinFactor, tuning.ChanceMixLineupFactor(0))
+	assert.Equal(t, tuning.ChanceMixLineupMaxFactor, tuning.ChanceMixLineupFactor(200))
+	prev := tuning.ChanceMixLineupFactor(0)
+	for x := 1.0; x <= 100; x++ {
+		v := tuning.ChanceMixLineupFactor(x)
+		assert.GreaterOrEqual(t, v, prev, "lineup factor must be monotonic at x=%v", x)
+		prev = v
+	}
+}
+
+func TestBallWinnerBreakawayFactor(t *testing.T) {
+	assert.Equal(t, 1.0, tuning.BallWinnerBreakawayFactor(tuning.BallWinnerBreakawayNeutral))
+	assert.Equal(t, tuning.BallWinnerBreakawayMinFactor, tuning.BallWinnerBreakawayFactor(0))
+	assert.Equal(t, tuning.BallWinnerBreakawayMaxFactor, tuning.BallWinnerBreakawayFactor(200))
+	assert.Less(t, tuning.BallWinnerBreakawayMinFactor, tuning.ChanceMixLineupMinFactor,
+		"a poor Ball Winner must cost more than a poor specialist")
+}
+
+func TestReboundSpill(t *testing.T) {
+	assert.Equal(t, tuning.ReboundSpillNeutral, tuning.ReboundSpill(tuning.ReboundHandlingNeutral))
+	assert.Equal(t, tuning.ReboundSpillMax, tuning.ReboundSpill(0))
+	assert.Equal(t, tuning.ReboundSpillMin, tuning.ReboundSpill(200))
+	prev := tuning.ReboundSpill(0)
+	for h := 1; h <= 100; h++ {
+		v := tuning.ReboundSpill(h)
+		assert.LessOrEqual(t, v, prev, "better hands must never spill more at h=%d", h)
+		prev = v
+	}
+}
+
+func TestMarkingFactors(t *testing.T) {
+	assert.Equal(t, 0.0, tuning.MarkingStrength(0))
+	assert.Equal(t, 0.0, tuning.MarkingStrength(tuning.MarkingTacklingFloor))
+	assert.Equal(t, 1.0, tuning.MarkingStrength(100))
+	assert.Equal(t, 1.0, tuning.MarkingStrength(120))
+
+	assert.Equal(t, 1.0, tuning.MarkingSelectionFactor(tuning.MarkingTacklingFloor))
+	assert.InDelta(t, 1-tuning.MarkingMaxSelectionCut, tuning.MarkingSelectionFactor(100), 1e-9)
+	assert.Equal(t, 1.0, tuning.MarkingConversionFactor(tuning.MarkingTacklingFloor))
+	assert.InDelta(t, 1-tuning.MarkingMaxConversionCut, tuning.MarkingConversionFactor(100), 1e-9)
+
+	assert.Greater(t, tuning.MarkerDefenseScale, 0.0)
+	assert.Less(t, tuning.MarkerDefenseScale, 1.0)
+}
+
+func TestPenaltyMindGames(t *testing.T) {
+	assert.Less(t, tuning.PenaltyTakerMidShare(40), tuning.PenaltyTakerMidShare(95), "composure buys the nerve to go down the middle")
+	assert.Equal(t, tuning.PenaltyTakerMidMin, tuning.PenaltyTakerMidShare(0))
+	assert.Equal(t, tuning.PenaltyTakerMidMax, tuning.PenaltyTakerMidShare(200))
+
+	assert.Greater(t, tuning.PenaltyOffTargetChance(true, 60), tuning.PenaltyOffTargetChance(false, 60), "corners are riskier")
+	assert.Greater(t, tuning.PenaltyOffTargetChance(true, 40), tuning.PenaltyOffTargetChance(true, 90), "technique keeps it on target")
+	assert.Equal(t, 0.0, tuning.PenaltyOffTargetChance(true, 100))
+
+	assert.Equal(t, 0.0, tuning.PenaltyReadStrength(0, 90))
+	assert.Less(t, tuning.PenaltyReadStrength(2, 90), tuning.PenaltyReadStrength(tuning.PenaltyHistoryFullSample, 90))
+	assert.Equal(t, tuning.PenaltyReadStrength(tuning.PenaltyHistoryFullSample, 90), tuning.PenaltyReadStrength(50, 90))
+	assert.LessOrEqual(t, tuning.PenaltyReadStrength(50, 100), tuning.PenaltyMaxRead)
+
+	for _, q := range []float64{0.3, 0.75, 0.95} {
+		assert.Less(t, tuning.PenaltyGoalChance(q, true), tuning.PenaltyGoalChance(q, false), "guessing right must help the keeper (q=%.2f)", q)
+		assert.Less(t, tuning.PenaltyGoalChance(q, true), q, "a keeper going the right way beats the plain duel (q=%.2f)", q)
+	}
+}
+
+func TestPenaltyPressureFactor(t *testing.T) {
+	assert.Equal(t, 1.0, tuning.PenaltyPressureFactor(30, 0, false), "a routine kick carries no pressure")
+	assert.Equal(t, 1.0, tuning.PenaltyPressureFactor(tuning.PenaltyMidComposureNeutral, 1, false))
+
+	assert.Greater(t, tuning.PenaltyPressureFactor(95, 1, false), 1.0, "composure rises to sudden death")
+	assert.Less(t, tuning.PenaltyPressureFactor(40, 1, false), 1.0, "nerves show in sudden death")
+	assert.Less(t, tuning.PenaltyPressureFactor(40, 1, false), tuning.PenaltyPressureFactor(40, tuning.PenaltyPressureLateKick, false))
+
+	assert.Less(t, tuning.PenaltyPressureFactor(40, 0, true), tuning.PenaltyPressureFactor(40, 0, false), "having to score hurts")
+	assert.Equal(t, tuning.PenaltyPressureFactor(100, 0, true), tuning.PenaltyPressureFactor(100, 0, false), "unless the taker is ice cold")
+}
@@ -0,0 +1,210 @@
+// Package league schedules round-robin league seasons. The schedule is a
+// deterministic function of (seed, teams, rules) — seeded from the same
+// Algorand block hash as allocation, anyone can re-derive it and check that
+// nobody hand-picked their fixtures.
+//
+// The draw is the circle method with de Werra's canonical home/away
+// assignment:
+//
+//   - Teams are sorted by ID, then shuffled with the seed. The shuffle is the
+//     only randomness; everything after it is fixed, so the input order of
+//     the teams doesn't matter.
+//   - An odd number of teams gets a phantom opponent. Whoever draws the
+//     phantom in a round has a bye.
+//   - Every team plays every other team once per leg. Home games differ by at
+//     most one per leg. A double round-robin mirrors the first leg with home
+//     and away swapped, so each pairing is played once at each ground. The
+//     second leg opens with the return of the earliest first-leg round that
+//     keeps the rule below, so no pair meets twice running across the turn
+//     (unless there are only two teams).
+//   - No team plays more than two home (or away) games in a row, byes
+//     ignored.
+package league
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Format is how many times each pair of teams meets.
+type Format string
+
+const (
+	FormatSingle Format = "Single" // once, at one team's ground
+	FormatDouble Format = "Double" // twice, once at each ground
+)
+
+var (
+	ErrTooFewTeams   = errors.New("league: at least two teams are required")
+	ErrDuplicateTeam = errors.New("league: duplicate team")
+	ErrUnknownFormat = errors.New("league: unknown format")
+)
+
+// Rules are the dials of a league: how it is scheduled and how the table is
+// ranked.
+type Rules struct {
+	Format        Format `json:"format"`
+	PointsForWin  int    `json:"points_for_win"`
+	PointsForDraw int    `json:"points_for_draw"`
+	// TieBreakers separate teams level on points, in order. Teams still
+	// level after the last one are separated by lot.
+	TieBreakers []TieBreaker `json:"tie_breakers"`
+}
+
+// DefaultRules returns the rules used by the live game: a double
+// round-robin, three points for a win, and goal difference, goals scored,
+// head-to-head and xG as tie-breakers.
+func DefaultRules() Rules {
+	return Rules{
+		Format:        FormatDouble,
+		PointsForWin:  3,
+		PointsForDraw: 1,
+		TieBreakers: []TieBreaker{
+			TieBreakerGoalDifference,
+			TieBreakerGoalsScored,
+			TieBreakerHeadToHead,
+			TieBreakerXG,
+		},
+	}
+}
+
+// Fixture is one match. Round is 1-based.
+type Fixture struct {
+	Round int    `json:"round"`
+	Home  string `json:"home"`
+	Away  string `json:"away"`
+}
+
+// Round is a matchday. Bye is the team sitting the round out, "" when the
+// team count is even.
+type Round struct {
+	Number   int       `json:"number"`
+	Fixtures []Fixture `json:"fixtures"`
+	Bye      string    `json:"bye,omitempty"`
+}
+
+// Schedule draws a round-robin season for teams (by ID).
+func Schedule(r *rand.Rand, teams []string, rules Rules) ([]Round, error) {
+	if r == nil {
+		return nil, errors.New("league: rand source is required")
+	}
+	if rules.Format != FormatSingle && rules.Format != FormatDouble {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, rules.Format)
+	}
+	if len(teams) < 2 {
+		return nil, ErrTooFewTeams
+	}
+
+	order := append([]string(nil), teams...)
+	sort.Strings(order)
+	for i := 1; i < len(order); i++ {
+		if order[i] == order[i-1] {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateTeam, order[i])
+		}
+	}
+	r.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	if len(order)%2 == 1 {
+		order = append(order, "") // the phantom
+	}
+
+	rounds := circle(order)
+	if rules.Format == FormatDouble {
+		// The return leg starts as early in the first leg as it can. Starting
+		// with the return of the last round always keeps the venues in
+		// order, but has that pair meet twice running, so it is the last
+		// resort — only two teams need it.
+		first := len(rounds)
+		for shift := 0; shift < first; shift++ {
+			season := append(rounds[:first:first], mirror(rounds, shift)...)
+			if shift == first-1 || !tripleVenue(season) {
+				rounds = season
+				break
+			}
+		}
+	}
+	return rounds, nil
+}
+
+// mirror is the return leg of leg, numbered on from it: leg's rounds with
+// home and away swapped, starting from round shift+1 and wrapping round.
+func mirror(leg []Round, shift int) []Round {
+	n := len(leg)
+	out := make([]Round, n)
+	for i := range out {
+		src := leg[(i+shift)%n]
+		out[i] = Round{Number: n + i + 1, Bye: src.Bye}
+		for _, f := range src.Fixtures {
+			out[i].Fixtures = append(out[i].Fixtures, Fixture{Round: out[i].Number, Home: f.Away, Away: f.Home})
+		}
+	}
+	return out
+}
+
+// tripleVenue reports whether any team plays three home (or away) games in
+// a row, byes ignored.
+func tripleVenue(rounds []Round) bool {
+	type streak struct {
+		home bool
+		run  int
+	}
+	streaks := make(map[string]streak)
+	play := func(id string, home bool) bool {
+		s := streaks[id]
+		if s.run > 0 && s.home == home {
+			s.run++
+		} else {
+			s = streak{home: home, run: 1}
+		}
+		streaks[id] = s
+		return s.run >= 3
+	}
+	for _, round := range rounds {
+		for _, f := range round.Fixtures {
+			if play(f.Home, true) || play(f.Away, false) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// circle is one leg for an even number of teams, "" being the phantom. Team
+// n-1 is the pivot; in round k it meets team k, and every other pair is
+// (k+i, k-i) mod n-1. The pivot alternates home and away by round; in the
+// other pairs the (k+i) side is at home when i is odd. This gives every
+// team at most one break (two home or two away games running) per leg.
+func circle(teams []string) []Round {
+	n := len(teams)
+	m := n - 1
+	rounds := make([]Round, m)
+	for k := 0; k < m; k++ {
+		round := Round{Number: k + 1}
+		add := func(home, away string) {
+			switch {
+			case home == "":
+				round.Bye = away
+			case away == "":
+				round.Bye = home
+			default:
+				round.Fixtures = append(round.Fixtures, Fixture{Round: k + 1, Home: home, Away: away})
+			}
+		}
+		if k%2 == 0 {
+			add(teams[k], teams[m])
+		} else {
+			add(teams[m], teams[k])
+		}
+		for i := 1; i < n/2; i++ {
+			a, b := teams[(k+i)%m], teams[(k-i+m)%m]
+			if i%2 == 1 {
+				add(a, b)
+			} else {
+				add(b, a)
+			}
+		}
+		rounds[k] = round
+	}
+	return rounds
+}
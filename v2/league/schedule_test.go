@@ -0,0 +1,175 @@
+package league_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stein-f/oink-soccer-common/v2/algorand"
+	"github.com/stein-f/oink-soccer-common/v2/league"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func teams(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("team-%02d", i+1)
+	}
+	return out
+}
+
+func schedule(t *testing.T, seed int64, n int, format league.Format) []league.Round {
+	t.Helper()
+	rounds, err := league.Schedule(rand.New(rand.NewSource(seed)), teams(n), league.Rules{Format: format})
+	require.NoError(t, err)
+	return rounds
+}
+
+func TestSchedule_Deterministic(t *testing.T) {
+	a := schedule(t, 42, 10, league.FormatDouble)
+	b := schedule(t, 42, 10, league.FormatDouble)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, schedule(t, 43, 10, league.FormatDouble), "a different seed draws a different schedule")
+}
+
+func TestSchedule_InputOrderDoesNotMatter(t *testing.T) {
+	ts := teams(9)
+	reversed := make([]string, len(ts))
+	for i, id := range ts {
+		reversed[len(ts)-1-i] = id
+	}
+
+	a, err := league.Schedule(rand.New(rand.NewSource(5)), ts, league.DefaultRules())
+	require.NoError(t, err)
+	b, err := league.Schedule(rand.New(rand.NewSource(5)), reversed, league.DefaultRules())
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+}
+
+func TestSchedule_IsFair(t *testing.T) {
+	for _, format := range []league.Format{league.FormatSingle, league.FormatDouble} {
+		for n := 2; n <= 32; n++ {
+			for seed := int64(1); seed <= 3; seed++ {
+				t.Run(fmt.Sprintf("%s/%d/%d", format, n, seed), func(t *testing.T) {
+					assertFair(t, schedule(t, seed, n, format), n, format)
+				})
+			}
+		}
+	}
+}
+
+func assertFair(t *testing.T, rounds []league.Round, n int, format league.Format) {
+	legs := 1
+	if format == league.FormatDouble {
+		legs = 2
+	}
+	perLeg := n - 1
+	if n%2 == 1 {
+		perLeg = n
+	}
+	require.Len(t, rounds, legs*perLeg)
+
+	meetings := make(map[[2]string]int) // ordered: home, away
+	byes := make(map[string]int)
+	venues := make(map[string]string) // team -> "H"/"A" sequence, byes skipped
+	last := make(map[string]string)   // team -> opponent in its last match
+	for i, round := range rounds {
+		assert.Equal(t, i+1, round.Number)
+		playing := make(map[string]bool)
+		for _, f := range round.Fixtures {
+			assert.Equal(t, round.Number, f.Round)
+			for _, id := range []string{f.Home, f.Away} {
+				assert.False(t, playing[id], "%s plays twice in round %d", id, round.Number)
+				playing[id] = true
+			}
+			if n > 2 {
+				assert.False(t, last[f.Home] == f.Away && last[f.Away] == f.Home,
+					"%s and %s meet back to back in round %d", f.Home, f.Away, round.Number)
+			}
+			last[f.Home], last[f.Away] = f.Away, f.Home
+			meetings[[2]string{f.Home, f.Away}]++
+			venues[f.Home] += "H"
+			venues[f.Away] += "A"
+		}
+		if n%2 == 1 {
+			require.NotEmpty(t, round.Bye)
+			assert.False(t, playing[round.Bye])
+			byes[round.Bye]++
+		} else {
+			assert.Empty(t, round.Bye)
+		}
+		assert.Len(t, round.Fixtures, n/2)
+	}
+
+	ids := teams(n)
+	for _, a := range ids {
+		for _, b := range ids {
+			if a >= b {
+				continue
+			}
+			ab, ba := meetings[[2]string{a, b}], meetings[[2]string{b, a}]
+			if format == league.FormatDouble {
+				assert.Equal(t, 1, ab, "%s v %s", a, b)
+				assert.Equal(t, 1, ba, "%s v %s", b, a)
+			} else {
+				assert.Equal(t, 1, ab+ba, "%s and %s meet once", a, b)
+			}
+		}
+		if n%2 == 1 {
+			assert.Equal(t, legs, byes[a], "%s sits out once per leg", a)
+		}
+
+		v := venues[a]
+		var home int
+		for _, c := range v {
+			if c == 'H' {
+				home++
+			}
+		}
+		away := len(v) - home
+		if format == league.FormatDouble {
+			assert.Equal(t, home, away, "%s: %s", a, v)
+		} else {
+			assert.LessOrEqual(t, abs(home-away), 1, "%s: %s", a, v)
+		}
+		assert.NotContains(t, v, "HHH", "%s: %s", a, v)
+		assert.NotContains(t, v, "AAA", "%s: %s", a, v)
+	}
+}
+
+func TestSchedule_Errors(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	_, err := league.Schedule(r, teams(1), league.DefaultRules())
+	assert.ErrorIs(t, err, league.ErrTooFewTeams)
+
+	_, err = league.Schedule(r, []string{"a", "b", "a"}, league.DefaultRules())
+	assert.ErrorIs(t, err, league.ErrDuplicateTeam)
+
+	_, err = league.Schedule(r, teams(4), league.Rules{Format: "Triple"})
+	assert.ErrorIs(t, err, league.ErrUnknownFormat)
+
+	_, err = league.Schedule(nil, teams(4), league.DefaultRules())
+	assert.Error(t, err)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func TestSchedule_VerifiableFromBlockHash(t *testing.T) {
+	const hash = "VVMG2PTT6YGDPSF3YKC4AEZUYH5UNLDLZNMSBR24MQ6ZD7Y7IWNA"
+
+	a, err := league.Schedule(algorand.SeedFromBlockHash(hash), teams(8), league.DefaultRules())
+	require.NoError(t, err)
+	b, err := league.Schedule(algorand.SeedFromBlockHash(hash), teams(8), league.DefaultRules())
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+}
@@ -0,0 +1,252 @@
+package league
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+)
+
+// TieBreaker separates teams level on points.
+type TieBreaker string
+
+const (
+	TieBreakerGoalDifference TieBreaker = "Goal Difference"
+	TieBreakerGoalsScored    TieBreaker = "Goals Scored"
+	// TieBreakerHeadToHead ranks the tied teams on a mini-league of the
+	// matches between them: points, then goal difference, then goals scored.
+	// Teams the mini-league leaves level play it again among themselves,
+	// until it separates no one.
+	TieBreakerHeadToHead TieBreaker = "Head To Head"
+	// TieBreakerXG ranks on expected-goal difference (xG for minus xG
+	// against), rewarding the side that made the better chances.
+	TieBreakerXG TieBreaker = "xG"
+)
+
+var (
+	ErrUnknownTeam       = errors.New("league: result for a team not in the league")
+	ErrUnknownTieBreaker = errors.New("league: unknown tie-breaker")
+	// ErrMissingLineup is returned by the competitions built on league
+	// when a team due to play has no lineup.
+	ErrMissingLineup = errors.New("league: no lineup for team")
+)
+
+// Result is a played match: the two teams by ID and the events
+// RunGameWithSeed returned for it.
+type Result struct {
+	Home   string             `json:"home"`
+	Away   string             `json:"away"`
+	Events []soccer.GameEvent `json:"events"`
+}
+
+// Match is a played fixture as a competition records it. ID is
+// "<group>-R<round>-<fixture>", both 1-based, where the group is the
+// competition's label for the league: "D2" for a season's second division,
+// "A" for a tournament's first group.
+type Match struct {
+	ID string `json:"id"`
+	Fixture
+	Events   []soccer.GameEvent `json:"events"`
+	Injuries soccer.Injuries    `json:"injuries"`
+}
+
+// Result returns the match as Standings reads it.
+func (m Match) Result() Result {
+	return Result{Home: m.Home, Away: m.Away, Events: m.Events}
+}
+
+// Standing is one row of the table. Position is 1-based.
+type Standing struct {
+	Position     int     `json:"position"`
+	TeamID       string  `json:"team_id"`
+	Played       int     `json:"played"`
+	Won          int     `json:"won"`
+	Drawn        int     `json:"drawn"`
+	Lost         int     `json:"lost"`
+	GoalsFor     int     `json:"goals_for"`
+	GoalsAgainst int     `json:"goals_against"`
+	XGFor        float64 `json:"xg_for"`
+	XGAgainst    float64 `json:"xg_against"`
+	Points       int     `json:"points"`
+}
+
+func (s Standing) GoalDifference() int {
+	return s.GoalsFor - s.GoalsAgainst
+}
+
+// Standings builds the table for teams from results. Teams are ordered by
+// points, then by rules.TieBreakers in turn, each applied only to the teams
+// still level after the ones before it. Teams level on everything are
+// separated by lot: a hash of seed and the team ID, so the draw can be
+// replayed and doesn't depend on the order of teams or results.
+func Standings(teams []string, results []Result, seed int64, rules Rules) ([]Standing, error) {
+	for _, tb := range rules.TieBreakers {
+		switch tb {
+		case TieBreakerGoalDifference, TieBreakerGoalsScored, TieBreakerHeadToHead, TieBreakerXG:
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrUnknownTieBreaker, tb)
+		}
+	}
+	rows, err := tabulate(teams, results, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	t := table{rows: rows, results: results, seed: seed, rules: rules}
+	var out []Standing
+	for _, group := range splitBy(teams, func(id string) []float64 { return []float64{float64(rows[id].Points)} }) {
+		for _, id := range t.rank(group, rules.TieBreakers) {
+			row := *rows[id]
+			row.Position = len(out) + 1
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+// tabulate totals results for teams. Only results between two listed teams
+// count; any other team ID is an error.
+func tabulate(teams []string, results []Result, rules Rules) (map[string]*Standing, error) {
+	rows := make(map[string]*Standing, len(teams))
+	for _, id := range teams {
+		if _, dup := rows[id]; dup {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateTeam, id)
+		}
+		rows[id] = &Standing{TeamID: id}
+	}
+	for _, res := range results {
+		home, ok := rows[res.Home]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownTeam, res.Home)
+		}
+		away, ok := rows[res.Away]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownTeam, res.Away)
+		}
+		stats := soccer.CreateGameStats(res.Events)
+		record(home, stats.HomeTeamStats, stats.AwayTeamStats, stats.Outcome(soccer.TeamTypeHome), rules)
+		record(away, stats.AwayTeamStats, stats.HomeTeamStats, stats.Outcome(soccer.TeamTypeAway), rules)
+	}
+	return rows, nil
+}
+
+func record(row *Standing, own, opp soccer.TeamStats, outcome soccer.GameOutcomeType, rules Rules) {
+	row.Played++
+	row.GoalsFor += own.Goals
+	row.GoalsAgainst += opp.Goals
+	row.XGFor += own.XG
+	row.XGAgainst += opp.XG
+	switch outcome {
+	case soccer.GameOutcomeTypeWon:
+		row.Won++
+		row.Points += rules.PointsForWin
+	case soccer.GameOutcomeTypeDrawn:
+		row.Drawn++
+		row.Points += rules.PointsForDraw
+	default:
+		row.Lost++
+	}
+}
+
+type table struct {
+	rows    map[string]*Standing
+	results []Result
+	seed    int64
+	rules   Rules
+}
+
+// rank orders a group of teams level on everything so far, applying the
+// tie-breakers in turn and drawing lots for whoever is left level.
+func (t table) rank(group []string, breakers []TieBreaker) []string {
+	if len(group) < 2 {
+		return group
+	}
+	if len(breakers) == 0 {
+		return t.lot(group)
+	}
+	subs := splitBy(group, t.key(group, breakers[0]))
+	next := breakers[1:]
+	if breakers[0] == TieBreakerHeadToHead && len(subs) > 1 {
+		// A smaller group has its own mini-league: b and c, level in a
+		// three-way one, are split by the match between them.
+		next = breakers
+	}
+	var out []string
+	for _, sub := range subs {
+		out = append(out, t.rank(sub, next)...)
+	}
+	return out
+}
+
+// key returns the tie-breaker's sort key for a team in the group, higher
+// first.
+func (t table) key(group []string, tb TieBreaker) func(id string) []float64 {
+	switch tb {
+	case TieBreakerGoalDifference:
+		return func(id string) []float64 { return []float64{float64(t.rows[id].GoalDifference())} }
+	case TieBreakerGoalsScored:
+		return func(id string) []float64 { return []float64{float64(t.rows[id].GoalsFor)} }
+	case TieBreakerXG:
+		// Rounded so the order results were summed in can't split a tie.
+		return func(id string) []float64 {
+			return []float64{math.Round((t.rows[id].XGFor - t.rows[id].XGAgainst) * 1e6)}
+		}
+	default: // TieBreakerHeadToHead
+		var among []Result
+		in := make(map[string]bool, len(group))
+		for _, id := range group {
+			in[id] = true
+		}
+		for _, res := range t.results {
+			if in[res.Home] && in[res.Away] {
+				among = append(among, res)
+			}
+		}
+		mini, _ := tabulate(group, among, t.rules) // every team is in group
+		return func(id string) []float64 {
+			r := mini[id]
+			return []float64{float64(r.Points), float64(r.GoalDifference()), float64(r.GoalsFor)}
+		}
+	}
+}
+
+// lot orders teams by a hash of the seed and their ID.
+func (t table) lot(group []string) []string {
+	draw := func(id string) uint64 {
+		h := fnv.New64a()
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], uint64(t.seed))
+		_, _ = h.Write(buf[:])
+		_, _ = h.Write([]byte(id))
+		return h.Sum64()
+	}
+	out := slices.Clone(group)
+	slices.SortFunc(out, func(a, b string) int {
+		return cmp.Or(cmp.Compare(draw(a), draw(b)), cmp.Compare(a, b))
+	})
+	return out
+}
+
+// splitBy stably sorts ids by key, highest first, and splits them into runs
+// of equal keys.
+func splitBy(ids []string, key func(string) []float64) [][]string {
+	keys := make(map[string][]float64, len(ids))
+	for _, id := range ids {
+		keys[id] = key(id)
+	}
+	sorted := slices.Clone(ids)
+	slices.SortStableFunc(sorted, func(a, b string) int { return slices.Compare(keys[b], keys[a]) })
+	var out [][]string
+	for i, id := range sorted {
+		if i == 0 || slices.Compare(keys[id], keys[sorted[i-1]]) != 0 {
+			out = append(out, nil)
+		}
+		out[len(out)-1] = append(out[len(out)-1], id)
+	}
+	return out
+}
@@ -0,0 +1,204 @@
+package league_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/league"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// result builds a match from a scoreline. Each goal is worth xg expected
+// goals to the side that scored it.
+func result(home, away string, homeGoals, awayGoals int, xg float64) league.Result {
+	res := league.Result{Home: home, Away: away}
+	for range homeGoals {
+		res.Events = append(res.Events, soccer.GameEvent{Type: soccer.GameEventTypeGoal, XG: xg, Event: soccer.GoalEvent{TeamType: soccer.TeamTypeHome}})
+	}
+	for range awayGoals {
+		res.Events = append(res.Events, soccer.GameEvent{Type: soccer.GameEventTypeGoal, XG: xg, Event: soccer.GoalEvent{TeamType: soccer.TeamTypeAway}})
+	}
+	return res
+}
+
+func ids(table []league.Standing) []string {
+	out := make([]string, len(table))
+	for i, s := range table {
+		out[i] = s.TeamID
+	}
+	return out
+}
+
+func TestStandings_Totals(t *testing.T) {
+	table, err := league.Standings([]string{"a", "b", "c"}, []league.Result{
+		result("a", "b", 2, 0, 0.5),
+		result("b", "c", 1, 1, 0.5),
+		result("c", "a", 0, 3, 0.5),
+	}, 1, league.DefaultRules())
+	require.NoError(t, err)
+
+	assert.Equal(t, []league.Standing{
+		{Position: 1, TeamID: "a", Played: 2, Won: 2, GoalsFor: 5, XGFor: 2.5, Points: 6},
+		{Position: 2, TeamID: "b", Played: 2, Drawn: 1, Lost: 1, GoalsFor: 1, GoalsAgainst: 3, XGFor: 0.5, XGAgainst: 1.5, Points: 1},
+		{Position: 3, TeamID: "c", Played: 2, Drawn: 1, Lost: 1, GoalsFor: 1, GoalsAgainst: 4, XGFor: 0.5, XGAgainst: 2, Points: 1},
+	}, table)
+}
+
+func TestStandings_TeamsWithoutResultsAreListed(t *testing.T) {
+	table, err := league.Standings([]string{"a", "b"}, nil, 1, league.DefaultRules())
+	require.NoError(t, err)
+
+	require.Len(t, table, 2)
+	assert.Zero(t, table[0].Played)
+}
+
+func TestStandings_TieBreakersApplyInOrder(t *testing.T) {
+	// a, b and c all win once and lose once. a has the best goal
+	// difference, but b beat a.
+	results := []league.Result{
+		result("a", "c", 5, 0, 0.1),
+		result("b", "a", 1, 0, 0.1),
+		result("c", "b", 1, 0, 0.9),
+	}
+	teams := []string{"a", "b", "c"}
+
+	byGD, err := league.Standings(teams, results, 1, league.Rules{PointsForWin: 3, TieBreakers: []league.TieBreaker{league.TieBreakerGoalDifference}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, ids(byGD))
+
+	byXG, err := league.Standings(teams, results, 1, league.Rules{PointsForWin: 3, TieBreakers: []league.TieBreaker{league.TieBreakerXG}})
+	require.NoError(t, err)
+	// xG difference: a 0.5-0.1=+0.4, b 0.1-0.9=-0.8, c 0.9-0.5=+0.4.
+	assert.Equal(t, "b", byXG[2].TeamID)
+}
+
+func TestStandings_HeadToHeadUsesOnlyTheTiedTeams(t *testing.T) {
+	// a and b finish level on points; b won the meeting between them, but a
+	// thrashed c.
+	results := []league.Result{
+		result("a", "b", 0, 1, 0),
+		result("a", "c", 6, 0, 0),
+		result("b", "c", 0, 0, 0),
+		result("c", "a", 0, 0, 0),
+	}
+	rules := league.Rules{PointsForWin: 3, PointsForDraw: 1, TieBreakers: []league.TieBreaker{league.TieBreakerHeadToHead, league.TieBreakerGoalDifference}}
+
+	table, err := league.Standings([]string{"a", "b", "c"}, results, 1, rules)
+	require.NoError(t, err)
+
+	require.Equal(t, table[0].Points, table[1].Points)
+	assert.Equal(t, []string{"b", "a"}, ids(table)[:2])
+
+	rules.TieBreakers = []league.TieBreaker{league.TieBreakerGoalDifference, league.TieBreakerHeadToHead}
+	table, err = league.Standings([]string{"a", "b", "c"}, results, 1, rules)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids(table)[:2])
+}
+
+func TestStandings_HeadToHeadIsReappliedToSmallerTies(t *testing.T) {
+	// a, b and c all beat d and finish level on points. Their mini-league
+	// puts a first and leaves b and c level on everything, but b beat c.
+	results := []league.Result{
+		result("a", "b", 3, 0, 0),
+		result("c", "a", 2, 1, 0),
+		result("b", "c", 2, 0, 0),
+		result("a", "d", 1, 0, 0),
+		result("b", "d", 1, 0, 0),
+		result("c", "d", 5, 0, 0), // c's better goal difference mustn't count
+	}
+	rules := league.Rules{PointsForWin: 3, PointsForDraw: 1, TieBreakers: []league.TieBreaker{league.TieBreakerHeadToHead, league.TieBreakerGoalDifference}}
+
+	table, err := league.Standings([]string{"a", "b", "c", "d"}, results, 1, rules)
+	require.NoError(t, err)
+
+	require.Equal(t, table[0].Points, table[2].Points)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, ids(table))
+}
+
+func TestStandings_LotIsSeededAndOrderIndependent(t *testing.T) {
+	teams := []string{"a", "b", "c", "d", "e", "f"}
+	results := []league.Result{result("a", "b", 1, 1, 0.3), result("c", "d", 1, 1, 0.3), result("e", "f", 1, 1, 0.3)}
+
+	a, err := league.Standings(teams, results, 9, league.DefaultRules())
+	require.NoError(t, err)
+
+	shuffled := slices.Clone(teams)
+	slices.Reverse(shuffled)
+	reversed := slices.Clone(results)
+	slices.Reverse(reversed)
+	b, err := league.Standings(shuffled, reversed, 9, league.DefaultRules())
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	differs := false
+	for seed := int64(10); seed < 20 && !differs; seed++ {
+		c, err := league.Standings(teams, results, seed, league.DefaultRules())
+		require.NoError(t, err)
+		differs = !slices.Equal(ids(a), ids(c))
+	}
+	assert.True(t, differs, "another seed draws different lots")
+}
+
+func TestStandings_Errors(t *testing.T) {
+	_, err := league.Standings([]string{"a", "b"}, []league.Result{result("a", "z", 1, 0, 0)}, 1, league.DefaultRules())
+	assert.ErrorIs(t, err, league.ErrUnknownTeam)
+
+	_, err = league.Standings([]string{"a", "a"}, nil, 1, league.DefaultRules())
+	assert.ErrorIs(t, err, league.ErrDuplicateTeam)
+
+	_, err = league.Standings([]string{"a", "b"}, nil, 1, league.Rules{TieBreakers: []league.TieBreaker{"Fair Play"}})
+	assert.ErrorIs(t, err, league.ErrUnknownTieBreaker)
+}
+
+// A scheduled, simulated season adds up.
+func TestStandings_SimulatedSeason(t *testing.T) {
+	lineups := map[string]soccer.GameLineup{
+		"strong-diamond": testdata.StrongTeam(soccer.FormationTypeDiamond),
+		"strong-box":     testdata.StrongTeam(soccer.FormationTypeBox),
+		"weak-diamond":   testdata.WeakTeam(soccer.FormationTypeDiamond),
+		"weak-box":       testdata.WeakTeam(soccer.FormationTypeBox),
+		"weak-y":         testdata.WeakTeam(soccer.FormationTypeY),
+	}
+	teams := make([]string, 0, len(lineups))
+	for id := range lineups {
+		teams = append(teams, id)
+	}
+	rules := league.DefaultRules()
+	rounds, err := league.Schedule(rand.New(rand.NewSource(3)), teams, rules)
+	require.NoError(t, err)
+
+	var results []league.Result
+	for _, round := range rounds {
+		for i, f := range round.Fixtures {
+			seed := int64(round.Number*100 + i)
+			events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(seed)), lineups[f.Home], lineups[f.Away])
+			require.NoError(t, err)
+			results = append(results, league.Result{Home: f.Home, Away: f.Away, Events: events})
+		}
+	}
+
+	table, err := league.Standings(teams, results, 3, rules)
+	require.NoError(t, err)
+
+	var points, won, drawn, gf, ga int
+	for i, s := range table {
+		assert.Equal(t, 8, s.Played)
+		assert.Equal(t, s.Won+s.Drawn+s.Lost, s.Played)
+		assert.Equal(t, 3*s.Won+s.Drawn, s.Points)
+		if i > 0 {
+			assert.LessOrEqual(t, s.Points, table[i-1].Points)
+		}
+		points += s.Points
+		won += s.Won
+		drawn += s.Drawn
+		gf += s.GoalsFor
+		ga += s.GoalsAgainst
+	}
+	assert.Equal(t, 3*won+drawn, points)
+	assert.Equal(t, gf, ga)
+	assert.Contains(t, []string{"strong-diamond", "strong-box"}, table[0].TeamID)
+}
@@ -0,0 +1,91 @@
+package soccer
+
+import (
+	"sort"
+
+	"github.com/stein-f/oink-soccer-common/v2/internal/tuning"
+)
+
+// marking is a resolved man-marking assignment for one match: Marker, on
+// the defending team, shadows the attacking team's player TargetID. The
+// zero value means no marking.
+type marking struct {
+	TargetID string
+	Marker   SelectedPlayer
+}
+
+// active reports whether the assignment does anything.
+func (m marking) active() bool {
+	return m.TargetID != ""
+}
+
+// resolveMarking turns a team's MarkPlayerID / MarkerID instruction into an
+// assignment against a specific opponent. The instruction is ignored (zero
+// marking) when the named target isn't on the opponent's pitch or the named
+// marker isn't on the team's — the same rule as a SetPieceTaker who isn't
+// playing. Without a MarkerID, the outfield player with the highest
+// EffectiveTackling marks (ties broken by ID so the choice is deterministic).
+// Goalkeepers never mark.
+func resolveMarking(team, opponent GameLineup) marking {
+	tactics := team.Team.Tactics
+	if tactics.MarkPlayerID == "" || !hasPlayer(opponent, tactics.MarkPlayerID) {
+		return marking{}
+	}
+
+	players := make([]SelectedPlayer, 0, len(team.Players))
+	for _, p := range team.Players {
+		if p.SelectedPosition != PlayerPositionGoalkeeper {
+			players = append(players, p)
+		}
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].ID < players[j].ID })
+
+	if tactics.MarkerID != "" {
+		for _, p := range players {
+			if p.ID == tactics.MarkerID {
+				return marking{TargetID: tactics.MarkPlayerID, Marker: p}
+			}
+		}
+		return marking{}
+	}
+
+	var best *SelectedPlayer
+	for i := range players {
+		if best == nil || players[i].Attributes.EffectiveTackling() > best.Attributes.EffectiveTackling() {
+			best = &players[i]
+		}
+	}
+	if best == nil {
+		return marking{}
+	}
+	return marking{TargetID: tactics.MarkPlayerID, Marker: *best}
+}
+
+// selectionFactor returns the multiplier on a player's attacker-pick weight
+// under this marking — below 1.0 for the marked player, 1.0 for everyone
+// else.
+func (m marking) selectionFactor(p SelectedPlayer) float64 {
+	if !m.active() || p.ID != m.TargetID {
+		return 1.0
+	}
+	return tuning.MarkingSelectionFactor(m.Marker.Attributes.EffectiveTackling())
+}
+
+// conversionFactor returns the multiplier on the attacker's score for a
+// chance. A marker can't stop a free kick or a penalty, so dead-ball
+// chances are unaffected.
+func (m marking) conversionFactor(attacker SelectedPlayer, ct ChanceType) float64 {
+	if !m.active() || attacker.ID != m.TargetID || isSetPieceChance(ct) {
+		return 1.0
+	}
+	return tuning.MarkingConversionFactor(m.Marker.Attributes.EffectiveTackling())
+}
+
+func hasPlayer(lineup GameLineup, id string) bool {
+	for _, p := range lineup.Players {
+		if p.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package soccer
+
+import (
+	"testing"
+
+	"github.com/stein-f/oink-soccer-common/v2/internal/tuning"
+	"github.com/stretchr/testify/assert"
+)
+
+// marking_test.go pins how a MarkPlayerID instruction resolves against the
+// two lineups and what the resulting assignment does to the marked player.
+
+func markingLineups() (GameLineup, GameLineup) {
+	attrs := PlayerAttributes{GoalkeeperRating: 70, DefenseRating: 60, ControlRating: 70, AttackRating: 70, SpeedRating: 70}
+	team := chanceLineup(FormationTypeDiamond, diamondSlots, attrs)
+	opponent := chanceLineup(FormationTypeDiamond, diamondSlots, attrs)
+	for i := range opponent.Players {
+		opponent.Players[i].ID = "opp-" + opponent.Players[i].ID
+	}
+	team.Players[0].Attributes.DefenseRating = 99 // GK — never marks
+	team.Players[2].Attributes.DefenseRating = 85 // best outfield tackler
+	team.Players[3].Attributes.Tackling = 85      // tied via explicit Tackling
+	return team, opponent
+}
+
+func TestResolveMarking_DefaultsToBestOutfieldTackler(t *testing.T) {
+	team, opponent := markingLineups()
+	team.Team.Tactics.MarkPlayerID = "opp-5"
+
+	m := resolveMarking(team, opponent)
+
+	assert.True(t, m.active())
+	assert.Equal(t, "opp-5", m.TargetID)
+	assert.Equal(t, "3", m.Marker.ID, "tie on tackling breaks by ID; GK is skipped despite the higher rating")
+}
+
+func TestResolveMarking_NamedMarker(t *testing.T) {
+	team, opponent := markingLineups()
+	team.Team.Tactics.MarkPlayerID = "opp-5"
+	team.Team.Tactics.MarkerID = "5"
+
+	assert.Equal(t, "5", resolveMarking(team, opponent).Marker.ID)
+}
+
+func TestResolveMarking_InvalidInstructionIsIgnored(t *testing.T) {
+	tests := map[string]Tactics{
+		"no target":          {},
+		"target not playing": {MarkPlayerID: "opp-99"},
+		"target on own team": {MarkPlayerID: "2"},
+		"marker not playing": {MarkPlayerID: "opp-5", MarkerID: "99"},
+		"goalkeeper marker":  {MarkPlayerID: "opp-5", MarkerID: "1"},
+	}
+	for name, tactics := range tests {
+		t.Run(name, func(t *testing.T) {
+			team, opponent := markingLineups()
+			team.Team.Tactics = tactics
+			assert.False(t, resolveMarking(team, opponent).active())
+		})
+	}
+}
+
+func TestMarking_OnlyAffectsTargetInOpenPlay(t *testing.T) {
+	team, opponent := markingLineups()
+	team.Team.Tactics.MarkPlayerID = "opp-5"
+	m := resolveMarking(team, opponent)
+	target, other := opponent.Players[4], opponent.Players[3]
+
+	assert.InDelta(t, tuning.MarkingSelectionFactor(85), m.selectionFactor(target), 1e-9)
+	assert.Less(t, m.selectionFactor(target), 1.0)
+	assert.Equal(t, 1.0, m.selectionFactor(other))
+
+	assert.Less(t, m.conversionFactor(target, ChanceTypeOpenPlay), 1.0)
+	assert.Less(t, m.conversionFactor(target, ChanceTypeCross), 1.0)
+	assert.Equal(t, 1.0, m.conversionFactor(other, ChanceTypeOpenPlay))
+	assert.Equal(t, 1.0, m.conversionFactor(target, ChanceTypePenalty), "a marker can't stop a penalty")
+	assert.Equal(t, 1.0, m.conversionFactor(target, ChanceTypeFreeKick))
+
+	var none marking
+	assert.Equal(t, 1.0, none.selectionFactor(target))
+	assert.Equal(t, 1.0, none.conversionFactor(target, ChanceTypeOpenPlay))
+}
+
+func TestTeamDefenseForChance_MarkingCostsTheMarker(t *testing.T) {
+	team, opponent := markingLineups()
+	team.Team.Tactics.MarkPlayerID = "opp-5"
+
+	assert.Equal(t, teamDefense(team), teamDefenseForChance(team, ChanceTypeOpenPlay, marking{}))
+	assert.Less(t, teamDefenseForChance(team, ChanceTypeOpenPlay, resolveMarking(team, opponent)), teamDefense(team))
+}
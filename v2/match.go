@@ -16,97 +16,198 @@ import (
 //
 //  1. Tempo:    determine how many chances the match will produce, derived
 //     from the formation styles (more attacking shapes ⇒ more
-//     chances).
+//     chances) and scaled up for seven- and eleven-a-side.
 //  2. Schedule: scatter chance minutes across the match using the v1
 //     event-minute distribution (late-game weighting).
 //  3. Possess:  for each chance, decide which team has the ball based on
 //     possession-weighted team control.
-//  4. Resolve:  for each chance, pick the chance type, attacker, and
+//  4. Resolve:  for each chance, pick the chance type (conditioned on the
+//     attacking team's formation, tempo and lineup), attacker, and
 //     outcome (goal/miss). Outcome weights honour the chance type
 //     (penalties are easy, long-range hard) and the formations'
-//     profile multipliers.
-//  5. Injuries: roll injuries per team based on opponent aggression and
-//     opponent-formation injury risk.
+//     profile multipliers. Larger teams defend each chance harder
+//     (tuning.TeamSizeScalings) so goals per match stay in range.
+//  5. Injuries: between chances, each player's injury risk accrues with
+//     the minutes played (weighted late, more so for pressing teams).
+//     A knock joins the event stream and the player's stats are cut by
+//     the injury's StatsReduction for the chances that remain.
+//
+// The same phases play extra time (see matchPeriod): a third of the
+// chances, minutes 91-120, and no new injuries.
 //
 // Determinism: the function is a pure function of (rand, home, away). No
 // time.Now(), no globals, no I/O.
-func simulateMatch(r *rand.Rand, home, away GameLineup) ([]GameEvent, Injuries) {
-	homeProfile := formationProfileFor(home.Team.Formation)
-	awayProfile := formationProfileFor(away.Team.Formation)
-
+func simulateMatch(r *rand.Rand, home, away GameLineup, period matchPeriod) ([]GameEvent, Injuries) {
 	homeTactics := home.Team.Tactics
 	awayTactics := away.Team.Tactics
 
 	// Tactics modulate the chance volume *per team*, but we generate a
 	// single combined count to keep events interleaved chronologically.
 	tempoFactor := (tempoChanceFactor(homeTactics.Tempo) + tempoChanceFactor(awayTactics.Tempo)) / 2.0
-	totalChances := decideMatchTempo(r, home.Team.Formation, away.Team.Formation, tempoFactor)
-	minutes := scheduleMinutes(r, totalChances)
+	sizeScaling := tuning.LookupTeamSizeScaling(len(home.Players))
+	totalChances := decideMatchTempo(r, home.Team.Formation, away.Team.Formation, sizeScaling.ChanceVolume, tempoFactor*period.chanceShare)
+	minutes := period.minutes(r, totalChances)
 
-	// Compute team scores once — they don't change minute-to-minute.
-	// Pressing reduces *opponent* control; line height does the same.
-	homeControl := teamControl(home) * homeProfile.Possession * captainBoost(home) * teamBoost(r, home)
-	awayControl := teamControl(away) * awayProfile.Possession * captainBoost(away) * teamBoost(r, away)
-	homeControl *= pressControlFactor(awayTactics.Press) * lineHeightControlFactor(awayTactics.LineHeight)
-	awayControl *= pressControlFactor(homeTactics.Press) * lineHeightControlFactor(homeTactics.LineHeight)
+	homeSide := newMatchSide(home, away, sizeScaling)
+	awaySide := newMatchSide(away, home, sizeScaling)
+	homeSide.controlBoost = teamBoost(r, home)
+	awaySide.controlBoost = teamBoost(r, away)
+	homeSide.defenseBoost = teamBoost(r, home)
+	awaySide.defenseBoost = teamBoost(r, away)
+	homeSide.rescore()
+	awaySide.rescore()
 
-	homeDefense := teamDefense(home) * homeProfile.DefSolidity * captainBoost(home) * tuning.DefenseBiasMultiplier * teamBoost(r, home)
-	awayDefense := teamDefense(away) * awayProfile.DefSolidity * captainBoost(away) * tuning.DefenseBiasMultiplier * teamBoost(r, away)
-	homeDefense *= lineHeightDefenseFactor(homeTactics.LineHeight)
-	awayDefense *= lineHeightDefenseFactor(awayTactics.LineHeight)
+	// Injuries: own injury risk scales with own press level too.
+	homeSide.injuries = newInjuryClock(home, TeamTypeHome, teamAverageAggression(away), awaySide.profile.InjuryRisk*pressInjuryFactor(homeTactics.Press), pressInjuryRamp(homeTactics.Press))
+	awaySide.injuries = newInjuryClock(away, TeamTypeAway, teamAverageAggression(home), homeSide.profile.InjuryRisk*pressInjuryFactor(awayTactics.Press), pressInjuryRamp(awayTactics.Press))
 
 	events := make([]GameEvent, 0, totalChances)
+	var injuries Injuries
 	var prevType ChanceType
 
+	// knocks rolls both teams' injury risk up to the given minute, applies
+	// any injuries and records them in the event stream.
+	knocks := func(minute int) {
+		if !period.injuries {
+			return
+		}
+		homeKnocks := homeSide.advanceInjuries(r, minute)
+		awayKnocks := awaySide.advanceInjuries(r, minute)
+		injuries.HomeTeamInjuries = append(injuries.HomeTeamInjuries, homeKnocks...)
+		injuries.AwayTeamInjuries = append(injuries.AwayTeamInjuries, awayKnocks...)
+		knocked := append(append([]InjuryEvent(nil), homeKnocks...), awayKnocks...)
+		sort.SliceStable(knocked, func(i, j int) bool { return knocked[i].Minute < knocked[j].Minute })
+		for _, inj := range knocked {
+			events = append(events, GameEvent{Type: GameEventTypeInjury, Event: inj, Minute: inj.Minute})
+		}
+	}
+
 	for i := 0; i < totalChances; i++ {
+		knocks(minutes[i])
+
 		// Possession: which team gets this chance?
-		attacker := pickAttackingTeam(r, homeControl, awayControl)
-		var (
-			attackingLineup  GameLineup
-			attackingProfile FormationProfile
-			attackingTactics Tactics
-			defendingDefense float64
-		)
-		if attacker == TeamTypeHome {
-			attackingLineup = home
-			attackingProfile = homeProfile
-			attackingTactics = homeTactics
-			defendingDefense = awayDefense
-		} else {
-			attackingLineup = away
-			attackingProfile = awayProfile
-			attackingTactics = awayTactics
-			defendingDefense = homeDefense
+		attacker := pickAttackingTeam(r, homeSide.control, awaySide.control)
+		attacking, defending := homeSide, awaySide
+		if attacker == TeamTypeAway {
+			attacking, defending = awaySide, homeSide
 		}
 
-		ct := pickChanceType(r, prevType)
+		ct := pickChanceType(r, attacking.chanceWeights, prevType)
 		prevType = ct
 
-		ap := pickAttackerWithTactics(r, attackingLineup, ct, attackingTactics)
-		deliveryFactor := cornerDeliveryFactor(attackingLineup, ct, attackingTactics)
-		ev := resolveChance(r, ap, attacker, ct, attackingProfile, attackingTactics, defendingDefense, deliveryFactor, minutes[i])
+		ap := pickAttackerWithTactics(r, attacking.lineup, ct, attacking.tactics, defending.marking)
+		attackFactor := cornerDeliveryFactor(attacking.lineup, ct, attacking.tactics) * defending.marking.conversionFactor(ap, ct)
+		ev := resolveChance(r, ap, attacker, ct, attacking.profile, attacking.tactics, defending.tactics, defending.defense[ct], attackFactor, defending.handling, minutes[i])
 		events = append(events, ev)
 	}
+	knocks(tuning.InjuryMatchMinutes)
 
-	// Injuries: own injury risk scales with own press level too.
-	homeAggression := teamAverageAggression(home)
-	awayAggression := teamAverageAggression(away)
-	homeInjuries := rollInjuries(r, home, awayAggression, awayProfile.InjuryRisk*pressInjuryFactor(homeTactics.Press), home.Team.ID)
-	awayInjuries := rollInjuries(r, away, homeAggression, homeProfile.InjuryRisk*pressInjuryFactor(awayTactics.Press), away.Team.ID)
+	return events, injuries
+}
 
-	return events, Injuries{HomeTeamInjuries: homeInjuries, AwayTeamInjuries: awayInjuries}
+// matchPeriod is the stretch of play simulateMatch covers.
+type matchPeriod struct {
+	chanceShare float64 // of a full match's chance volume
+	minutes     func(r *rand.Rand, count int) []int
+	injuries    bool // whether the injury clock runs
+}
+
+var (
+	regulation = matchPeriod{chanceShare: 1, minutes: scheduleMinutes, injuries: true}
+	// Extra time rolls no new injuries: the injury clock's odds are per
+	// 90 minutes, and a knock this late changes nothing for the match.
+	extraTime = matchPeriod{chanceShare: tuning.ExtraTimeChanceShare, minutes: scheduleExtraTimeMinutes}
+)
+
+// matchSide is one team's state during a match: the lineup as it stands
+// (in-match injuries are applied to it) and the scores derived from it.
+// The boost rolls are drawn once at kick-off and reused whenever an injury
+// forces the scores to be recomputed.
+type matchSide struct {
+	lineup        GameLineup
+	profile       FormationProfile
+	tactics       Tactics
+	opponent      Tactics
+	sizeScaling   tuning.TeamSizeScaling
+	marking       marking // this team's man-marking instruction
+	controlBoost  float64
+	defenseBoost  float64
+	control       float64
+	defense       map[ChanceType]float64
+	handling      int // the keeper's rated Handling, 0 if unrated
+	chanceWeights []float64
+	injuries      *injuryClock
+}
+
+func newMatchSide(lineup, opponent GameLineup, sizeScaling tuning.TeamSizeScaling) *matchSide {
+	tactics := lineup.Team.Tactics
+	return &matchSide{
+		lineup:      lineup,
+		profile:     formationProfileFor(lineup.Team.Formation),
+		tactics:     tactics,
+		opponent:    opponent.Team.Tactics,
+		sizeScaling: sizeScaling,
+		// Man-marking: the instruction against the other team's lineup. The
+		// marker's absence from the shape is paid for in their own defense.
+		marking: resolveMarking(lineup, opponent),
+		// The chance-type mix depends on who has the ball: shape, tactics and
+		// the lineup's aerial / technical bias, shifted by the opponent's shape.
+		chanceWeights: chanceTypeWeights(lineup, formationChanceMixFor(lineup.Team.Formation), tactics, opponent.Team.Tactics),
+	}
+}
+
+// rescore recomputes the side's control and per-chance-type defense from
+// the current lineup. Pressing reduces *opponent* control; line height does
+// the same.
+func (s *matchSide) rescore() {
+	s.control = teamControl(s.lineup) * s.profile.Possession * captainBoost(s.lineup) * s.controlBoost
+	s.control *= pressControlFactor(s.opponent.Press) * lineHeightControlFactor(s.opponent.LineHeight)
+	s.control *= passingStyleControlFactor(s.tactics.PassingStyle)
+
+	// Defense is scored per chance type: the goalkeeper's save rating
+	// depends on what they're facing (see goalkeeperSaveRating).
+	s.defense = make(map[ChanceType]float64, len(chanceTypeOrder))
+	for _, ct := range chanceTypeOrder {
+		s.defense[ct] = teamDefenseForChance(s.lineup, ct, s.marking) * s.profile.DefSolidity * captainBoost(s.lineup) * tuning.DefenseBiasMultiplier * s.defenseBoost
+		s.defense[ct] *= lineHeightDefenseFactor(s.tactics.LineHeight) * s.sizeScaling.DefenseScale
+	}
+
+	// An injury to the keeper's hands pins their Handling, so it is read
+	// after the injury is applied.
+	s.handling = 0
+	for _, p := range s.lineup.Players {
+		if p.SelectedPosition == PlayerPositionGoalkeeper {
+			s.handling = injuredAttributes(p).Handling
+			break
+		}
+	}
+}
+
+// advanceInjuries rolls the side's injury risk up to minute and applies any
+// new injury to the injured player for the rest of the match. A player who
+// came in already carrying a worse injury keeps it.
+func (s *matchSide) advanceInjuries(r *rand.Rand, minute int) []InjuryEvent {
+	knocks := s.injuries.advance(r, s.lineup, minute)
+	if len(knocks) == 0 {
+		return nil
+	}
+	s.lineup = ApplyInjuries(s.lineup, knocks)
+	s.rescore()
+	return knocks
 }
 
 // decideMatchTempo picks the total number of chances using the truth-table
-// from tuning, then scales by the supplied tempoFactor (combined home+away
-// tempo tactic). With both teams on neutral tempo, factor=1.0 and behaviour
-// matches v1 exactly.
-func decideMatchTempo(r *rand.Rand, homeF, awayF FormationType, tempoFactor float64) int {
+// from tuning, widens the range for larger teams (sizeFactor, 1.0 for
+// five-a-side), then scales by the supplied tempoFactor (combined home+away
+// tempo tactic). With five-a-side teams on neutral tempo, both factors are
+// 1.0 and behaviour matches v1 exactly.
+func decideMatchTempo(r *rand.Rand, homeF, awayF FormationType, sizeFactor, tempoFactor float64) int {
 	key := "HOME:" + formationStyleKey(homeF) + "|AWAY:" + formationStyleKey(awayF)
 	rng, ok := tuning.FormationChanceRanges[key]
 	if !ok {
 		rng = tuning.FallbackChanceRange
 	}
+	rng = rng.Scale(sizeFactor)
 	base := rng.Min
 	if rng.Max > rng.Min {
 		base = r.Intn(rng.Max-rng.Min+1) + rng.Min
@@ -135,20 +236,10 @@ func scaleChances(r *rand.Rand, base int, factor float64) int {
 	return whole
 }
 
-// formationStyleKey buckets each formation into ATT / BAL / DEF for the
-// chance-range table. Box is treated as BAL despite being attacking-shaped
-// because its chance *quality* (not volume) is its lever.
+// formationStyleKey returns the registered formation's style code
+// (ATT / BAL / DEF) for the chance-range table.
 func formationStyleKey(f FormationType) string {
-	switch f {
-	case FormationTypePyramid:
-		return "DEF"
-	case FormationTypeY:
-		return "ATT"
-	case FormationTypeDiamond, FormationTypeBox:
-		return "BAL"
-	default:
-		return "BAL"
-	}
+	return string(registeredFormation(f).Style)
 }
 
 // scheduleMinutes scatters a sorted slice of minutes across the match using
@@ -162,6 +253,18 @@ func scheduleMinutes(r *rand.Rand, count int) []int {
 	return out
 }
 
+// scheduleExtraTimeMinutes scatters a sorted slice of minutes evenly across
+// extra time.
+func scheduleExtraTimeMinutes(r *rand.Rand, count int) []int {
+	span := tuning.ExtraTimeLastMinute - tuning.ExtraTimeFirstMinute + 1
+	out := make([]int, 0, count)
+	for i := 0; i < count; i++ {
+		out = append(out, tuning.ExtraTimeFirstMinute+r.Intn(span))
+	}
+	sort.Ints(out)
+	return out
+}
+
 func sampleMinute(r *rand.Rand) int {
 	var totalW uint
 	for _, b := range tuning.EventMinuteBuckets {
@@ -195,18 +298,18 @@ func pickAttackingTeam(r *rand.Rand, homeWeight, awayWeight float64) TeamType {
 }
 
 // resolveChance rolls the goal/miss outcome and assembles the GameEvent.
-// Tactics affect chance quality (faster tempo ⇒ rushed shots). deliveryFactor
-// scales the chance's effective attack — currently used by corners to bake in
-// the named SetPieceTaker's delivery quality (1.0 = neutral / no taker).
-func resolveChance(r *rand.Rand, attacker SelectedPlayer, team TeamType, ct ChanceType, attackingProfile FormationProfile, attackingTactics Tactics, defendingDefense, deliveryFactor float64, minute int) GameEvent {
-	atk := playerAttackForChance(attacker, ct)
-	atk *= attackingProfile.ChanceCreation * attackingProfile.ChanceQuality
-	atk *= chanceTypeAttackBoost(ct)
-	atk *= tempoQualityFactor(attackingTactics.Tempo)
-	atk *= pressFatigueFactor(attackingTactics.Press, minute)
-	atk *= deliveryFactor
+// Tactics affect chance quality (faster tempo ⇒ rushed shots). attackFactor
+// scales the chance's effective attack for situational modifiers the caller
+// works out: the named SetPieceTaker's corner delivery quality and any
+// man-marking on the attacker (1.0 = neutral). handling is the defending
+// keeper's rated Handling, 0 if unrated (see shotOutcome).
+func resolveChance(r *rand.Rand, attacker SelectedPlayer, team TeamType, ct ChanceType, attackingProfile FormationProfile, attackingTactics, defendingTactics Tactics, defendingDefense, attackFactor float64, handling, minute int) GameEvent {
+	atk := chanceAttack(attacker, ct, attackingProfile, attackingTactics, attackFactor, minute)
 
 	def := defendingDefense * chanceTypeDefenseScale(ct)
+	if ct == ChanceTypeCross || ct == ChanceTypeCorner {
+		def *= widthAerialDefenseFactor(defendingTactics.Width)
+	}
 
 	// Floor scores so weighted-rand always sees positive values.
 	if atk < 1 {
@@ -216,11 +319,10 @@ func resolveChance(r *rand.Rand, attacker SelectedPlayer, team TeamType, ct Chan
 		def = 1
 	}
 
-	// Goal probability = atk / (atk + def). r.Float64() < p ⇒ goal.
-	p := atk / (atk + def)
-	isGoal := r.Float64() < p
+	// Goal probability = atk / (atk + def), before the keeper's hands.
+	isGoal, rebound, xg := shotOutcome(r.Float64(), atk/(atk+def), handling)
 
-	ev := GameEvent{Minute: minute, ChanceType: ct}
+	ev := GameEvent{Minute: minute, ChanceType: ct, XG: xg, Rebound: rebound}
 	if isGoal {
 		ev.Type = GameEventTypeGoal
 		ev.Event = GoalEvent{PlayerID: attacker.ID, TeamType: team}
@@ -231,6 +333,54 @@ func resolveChance(r *rand.Rand, attacker SelectedPlayer, team TeamType, ct Chan
 	return ev
 }
 
+// shotOutcome decides a shot from one uniform draw u and the chance's goal
+// probability p. A keeper without a Handling rating (handling 0) concedes
+// when u < p, as the engine always has.
+//
+// A rated keeper can spill the save, and the shooter follows up with the
+// same chance again (tuning.ReboundSpill). The first shot beats them with
+// p1, the share of p left once a neutral keeper's rebound goals are taken
+// out; the rest of u's range is split into spilled saves, and within those
+// into follow-ups that go in. At neutral Handling the goals land on exactly
+// u < p, so the rating moves outcomes without moving the draw order that
+// replays and forecasts rely on. xg is the goal probability with this
+// keeper's hands.
+func shotOutcome(u, p float64, handling int) (goal, rebound bool, xg float64) {
+	if handling == 0 {
+		return u < p, false, p
+	}
+	neutral := tuning.ReboundSpill(tuning.ReboundHandlingNeutral)
+	spill := tuning.ReboundSpill(handling)
+	p1 := p * (1 - neutral) / (1 - neutral*p)
+	xg = p1 + (1-p1)*spill*p
+	if u < p1 {
+		return true, false, xg
+	}
+	v := (u - p1) / (1 - p1)
+	if v >= spill {
+		return false, false, xg
+	}
+	return v/spill < p, true, xg
+}
+
+// chanceAttack is the attacker's effective attack on a chance: their
+// chance-type score lifted or cut by the formation, the chance type, the
+// team's tempo, passing style and width, late-game press fatigue and the
+// caller's attackFactor. AnalyzeLineup reports the same number.
+func chanceAttack(attacker SelectedPlayer, ct ChanceType, profile FormationProfile, tactics Tactics, attackFactor float64, minute int) float64 {
+	atk := playerAttackForChance(attacker, ct)
+	atk *= profile.ChanceCreation * profile.ChanceQuality
+	atk *= chanceTypeAttackBoost(ct)
+	atk *= tempoQualityFactor(tactics.Tempo)
+	atk *= passingStyleQualityFactor(tactics.PassingStyle)
+	atk *= pressFatigueFactor(tactics.Press, minute)
+	atk *= attackFactor
+	if ct == ChanceTypeCross || ct == ChanceTypeCorner {
+		atk *= widthAerialAttackFactor(tactics.Width)
+	}
+	return atk
+}
+
 // pickAttackerWithTactics picks an attacker honoring tactical overrides:
 //
 //   - For *direct* set pieces (free kicks + penalties), the named SetPieceTaker
@@ -241,7 +391,10 @@ func resolveChance(r *rand.Rand, attacker SelectedPlayer, team TeamType, ct Chan
 //     instead boosts the chance via cornerDeliveryFactor in resolveChance.
 //   - TargetMan gets a selection-weight bonus on corners + crosses (handled
 //     inside pickAttacker).
-func pickAttackerWithTactics(r *rand.Rand, lineup GameLineup, ct ChanceType, tactics Tactics) SelectedPlayer {
+//   - A player man-marked by the defending team is picked less often
+//     (handled inside pickAttacker). A named set-piece taker still takes
+//     their dead balls — a marker can't stop those.
+func pickAttackerWithTactics(r *rand.Rand, lineup GameLineup, ct ChanceType, tactics Tactics, marked marking) SelectedPlayer {
 	if tactics.SetPieceTaker != "" && isSetPieceChance(ct) {
 		for _, p := range lineup.Players {
 			if p.ID == tactics.SetPieceTaker {
@@ -254,7 +407,7 @@ func pickAttackerWithTactics(r *rand.Rand, lineup GameLineup, ct ChanceType, tac
 		// Corner taker delivers; can't also be the header.
 		excludeID = tactics.SetPieceTaker
 	}
-	return pickAttacker(r, lineup, ct, excludeID)
+	return pickAttacker(r, lineup, ct, excludeID, marked)
 }
 
 // isSetPieceChance reports whether the named SetPieceTaker is the *attacker*
@@ -0,0 +1,334 @@
+// Package matchmaking pairs a queue of teams for friendlies and ladder
+// matches so that each match is as even as it can be.
+//
+// How even a pairing is comes from the engine, not from OverallRating
+// averages: by default it is the rating package's expected score, with any
+// team the table hasn't rated yet seeded from its lineup's strength
+// (soccer.LineupStrength); with Rules.Simulations set, it is the share of
+// points each side takes over that many simulated matches.
+//
+// Pair ranks the queue by strength and pairs teams close together in that
+// order: a team may only meet a team within eight places of it. Among
+// those pairs it chooses the pairings that leave the fewest teams waiting
+// and, among those, add up to the smallest squared mismatch: two 70–30
+// matches are better than a 90–10 and a coin toss. A team whose only
+// allowed opponents are further away, say because NoRepeat rules out its
+// eight nearest, waits. The seed decides between
+// teams of equal strength and which side of each pairing is at home, so the
+// same (seed, queue, ratings, rules) always gives the same pairings.
+package matchmaking
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"slices"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/rating"
+)
+
+var ErrDuplicateTeam = errors.New("matchmaking: duplicate team")
+
+// Rules are the dials of the matchmaker.
+type Rules struct {
+	// NoRepeat keeps a team from meeting any opponent it played in its last
+	// NoRepeat matches in the rating table's history. 0 allows rematches.
+	NoRepeat int `json:"no_repeat"`
+	// MaxMismatch leaves a team waiting rather than pair it with an
+	// opponent whose expected score is further than this from ½. 0 means no
+	// limit.
+	MaxMismatch float64 `json:"max_mismatch"`
+	// Simulations, when above 0, estimates each pairing's expected score
+	// from that many engine matches, home and away alternately, instead of
+	// from the ratings.
+	Simulations int `json:"simulations"`
+}
+
+// DefaultRules returns the rules used by the live game: no rematch within
+// a team's last three matches, no mismatch limit, ratings-based
+// expectations.
+func DefaultRules() Rules {
+	return Rules{NoRepeat: 3}
+}
+
+// Pairing is one match made.
+type Pairing struct {
+	Home string `json:"home"`
+	Away string `json:"away"`
+	// Expected is the home side's expected score, 0 to 1.
+	Expected float64 `json:"expected"`
+}
+
+// Mismatch is how far the pairing is from an even match: 0 is a coin toss,
+// 0.5 a foregone conclusion.
+func (p Pairing) Mismatch() float64 {
+	return math.Abs(p.Expected - 0.5)
+}
+
+// Result is the matchmaker's output.
+type Result struct {
+	Pairings []Pairing `json:"pairings"`
+	// Unpaired are the teams left in the queue, strongest first.
+	Unpaired []string `json:"unpaired"`
+}
+
+type entry struct {
+	id     string
+	lineup soccer.GameLineup
+	rating float64
+}
+
+// Pair pairs the queue. ratings may be nil, in which case every team is
+// rated from its lineup with rating.DefaultRules and there is no match
+// history to avoid repeats from.
+func Pair(r *rand.Rand, queue []soccer.GameLineup, ratings *rating.Table, rules Rules) (Result, error) {
+	if r == nil {
+		return Result{}, soccer.ErrNilRandSource
+	}
+	if ratings == nil {
+		ratings = rating.New(rating.DefaultRules())
+	}
+
+	teams := make([]entry, len(queue))
+	seen := make(map[string]bool, len(queue))
+	for i, lineup := range queue {
+		id := lineup.Team.ID
+		if seen[id] {
+			return Result{}, fmt.Errorf("%w: %q", ErrDuplicateTeam, id)
+		}
+		seen[id] = true
+		rated := ratings.Rules.FromStrength(soccer.LineupStrength(lineup))
+		if e, ok := ratings.Teams[id]; ok {
+			rated = e.Rating
+		}
+		teams[i] = entry{id: id, lineup: lineup, rating: rated}
+	}
+
+	// The seed orders teams of equal strength: sort by ID so the input
+	// order doesn't matter, shuffle, then sort by rating keeping the
+	// shuffled order among equals.
+	slices.SortFunc(teams, func(a, b entry) int { return cmp.Compare(a.id, b.id) })
+	r.Shuffle(len(teams), func(i, j int) { teams[i], teams[j] = teams[j], teams[i] })
+	slices.SortStableFunc(teams, func(a, b entry) int { return cmp.Compare(b.rating, a.rating) })
+	base := r.Int63()
+
+	m := matcher{
+		teams:    teams,
+		rules:    rules,
+		ratings:  ratings,
+		recent:   recentOpponents(ratings.History, rules.NoRepeat),
+		base:     base,
+		expected: make(map[[2]int]float64),
+	}
+	pairs, unpaired, err := m.solve()
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{Unpaired: []string{}}
+	for _, p := range pairs {
+		home, away := p[0], p[1]
+		if r.Intn(2) == 1 {
+			home, away = away, home
+		}
+		e, err := m.expectation(home, away)
+		if err != nil {
+			return Result{}, err
+		}
+		res.Pairings = append(res.Pairings, Pairing{Home: teams[home].id, Away: teams[away].id, Expected: e})
+	}
+	for _, i := range unpaired {
+		res.Unpaired = append(res.Unpaired, teams[i].id)
+	}
+	return res, nil
+}
+
+// recentOpponents returns each team's opponents from its last n matches
+// in the history.
+func recentOpponents(history []rating.Change, n int) map[string]map[string]bool {
+	out := make(map[string]map[string]bool)
+	counted := make(map[string]int)
+	add := func(team, opponent string) {
+		if counted[team] >= n {
+			return
+		}
+		counted[team]++
+		if out[team] == nil {
+			out[team] = make(map[string]bool)
+		}
+		out[team][opponent] = true
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		add(history[i].Home, history[i].Away)
+		add(history[i].Away, history[i].Home)
+	}
+	return out
+}
+
+// unpairedCost is what leaving a team waiting costs. Every pairing's
+// squared mismatch is at most ¼, so the solver always prefers making a
+// pair.
+const unpairedCost = 1
+
+// window is how many places down the strength order a team may look for
+// an opponent: enough room to step around several recent opponents without
+// pairing teams far apart.
+const window = 8
+
+// matcher finds the pairings over the strength order. Each team may only
+// be paired within window places of it; among those pairs the matching is
+// the best there is.
+type matcher struct {
+	teams    []entry
+	rules    Rules
+	ratings  *rating.Table
+	recent   map[string]map[string]bool
+	base     int64
+	expected map[[2]int]float64 // simulated, by (i, j), i < j: i's expected score
+}
+
+// Partners of a place in solve, other than the place it is paired with.
+const (
+	waiting = -1 // left in the queue
+	taken   = -2 // already paired with an earlier place
+)
+
+// solve settles the places in strength order. The state before place i is
+// which of the places i to i+window-1 earlier places have already taken;
+// place i then waits, is taken, or pairs with a free place within window.
+func (m *matcher) solve() ([][2]int, []int, error) {
+	n := len(m.teams)
+	type state struct {
+		cost    float64
+		from    int // the mask before the place
+		partner int
+	}
+	dp := make([][]state, n+1)
+	for i := range dp {
+		dp[i] = make([]state, 1<<window)
+		for mask := range dp[i] {
+			dp[i][mask].cost = math.Inf(1)
+		}
+	}
+	dp[0][0].cost = 0
+	for i := 0; i < n; i++ {
+		for mask, s := range dp[i] {
+			if math.IsInf(s.cost, 1) {
+				continue
+			}
+			relax := func(next int, cost float64, partner int) {
+				if cost < dp[i+1][next].cost {
+					dp[i+1][next] = state{cost: cost, from: mask, partner: partner}
+				}
+			}
+			if mask&1 == 1 {
+				relax(mask>>1, s.cost, taken)
+				continue
+			}
+			for k := 1; k <= window && i+k < n; k++ {
+				if mask&(1<<k) != 0 {
+					continue
+				}
+				penalty, allowed, err := m.pairCost(i, i+k)
+				if err != nil {
+					return nil, nil, err
+				}
+				if allowed {
+					relax((mask|1<<k)>>1, s.cost+penalty, i+k)
+				}
+			}
+			relax(mask>>1, s.cost+unpairedCost, waiting)
+		}
+	}
+
+	var pairs [][2]int
+	var unpaired []int
+	for i, mask := n, 0; i > 0; i-- {
+		s := dp[i][mask]
+		switch s.partner {
+		case waiting:
+			unpaired = append(unpaired, i-1)
+		case taken:
+		default:
+			pairs = append(pairs, [2]int{i - 1, s.partner})
+		}
+		mask = s.from
+	}
+	slices.Reverse(pairs)
+	slices.Reverse(unpaired)
+	return pairs, unpaired, nil
+}
+
+// pairCost returns the squared mismatch of i and j and whether they may
+// meet.
+func (m *matcher) pairCost(i, j int) (float64, bool, error) {
+	a, b := m.teams[i].id, m.teams[j].id
+	if m.recent[a][b] || m.recent[b][a] {
+		return 0, false, nil
+	}
+	e, err := m.expectation(i, j)
+	if err != nil {
+		return 0, false, err
+	}
+	mismatch := math.Abs(e - 0.5)
+	if m.rules.MaxMismatch > 0 && mismatch > m.rules.MaxMismatch {
+		return 0, false, nil
+	}
+	return mismatch * mismatch, true, nil
+}
+
+// expectation returns home's expected score against away.
+func (m *matcher) expectation(home, away int) (float64, error) {
+	if m.rules.Simulations <= 0 {
+		return m.ratings.Rules.Expected(m.teams[home].rating, m.teams[away].rating), nil
+	}
+	i, j := min(home, away), max(home, away)
+	e, ok := m.expected[[2]int{i, j}]
+	if !ok {
+		var err error
+		if e, err = m.simulate(i, j); err != nil {
+			return 0, err
+		}
+		m.expected[[2]int{i, j}] = e
+	}
+	if home != i {
+		return 1 - e, nil
+	}
+	return e, nil
+}
+
+// simulate plays the pairing Simulations times, alternating home and away,
+// and returns the share of the points i takes. The stream is derived from
+// the draw's base seed and the two team IDs, so the estimate doesn't depend
+// on which other pairings were tried first.
+func (m *matcher) simulate(i, j int) (float64, error) {
+	a, b := m.teams[i], m.teams[j]
+	h := fnv.New64a()
+	_ = binary.Write(h, binary.LittleEndian, m.base)
+	_, _ = h.Write([]byte(a.id + "/" + b.id))
+	r := rand.New(rand.NewSource(int64(h.Sum64())))
+
+	var points float64
+	for k := range m.rules.Simulations {
+		home, away, side := a.lineup, b.lineup, soccer.TeamTypeHome
+		if k%2 == 1 {
+			home, away, side = b.lineup, a.lineup, soccer.TeamTypeAway
+		}
+		events, _, err := soccer.RunGameWithSeed(r, home, away)
+		if err != nil {
+			return 0, fmt.Errorf("matchmaking: %s v %s: %w", a.id, b.id, err)
+		}
+		switch soccer.CreateGameStats(events).Outcome(side) {
+		case soccer.GameOutcomeTypeWon:
+			points++
+		case soccer.GameOutcomeTypeDrawn:
+			points += 0.5
+		}
+	}
+	return points / float64(m.rules.Simulations), nil
+}
@@ -0,0 +1,190 @@
+package matchmaking_test
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/matchmaking"
+	"github.com/stein-f/oink-soccer-common/v2/rating"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ladder returns a queue of teams rated 100 points apart, t1 the strongest,
+// and a table holding those ratings.
+func ladder(n int) ([]soccer.GameLineup, *rating.Table) {
+	table := rating.New(rating.DefaultRules())
+	queue := make([]soccer.GameLineup, n)
+	for i := range queue {
+		lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+		lineup.Team.ID = fmt.Sprintf("t%d", i+1)
+		queue[i] = lineup
+		table.Teams[lineup.Team.ID] = rating.Entry{Rating: float64(2000 - 100*i)}
+	}
+	return queue, table
+}
+
+func opponents(res matchmaking.Result) map[string]string {
+	out := make(map[string]string)
+	for _, p := range res.Pairings {
+		out[p.Home], out[p.Away] = p.Away, p.Home
+	}
+	return out
+}
+
+func TestPair_PairsNeighboursInStrength(t *testing.T) {
+	queue, table := ladder(6)
+	// Shuffle the queue: the pairings don't depend on its order.
+	rand.New(rand.NewSource(7)).Shuffle(len(queue), func(i, j int) { queue[i], queue[j] = queue[j], queue[i] })
+
+	res, err := matchmaking.Pair(rand.New(rand.NewSource(1)), queue, table, matchmaking.DefaultRules())
+	require.NoError(t, err)
+
+	require.Len(t, res.Pairings, 3)
+	assert.Empty(t, res.Unpaired)
+	vs := opponents(res)
+	assert.Equal(t, "t2", vs["t1"])
+	assert.Equal(t, "t4", vs["t3"])
+	assert.Equal(t, "t6", vs["t5"])
+	for _, p := range res.Pairings {
+		assert.InDelta(t, 0.14, p.Mismatch(), 0.01, "100 points apart")
+	}
+
+	again, err := matchmaking.Pair(rand.New(rand.NewSource(1)), queue, table, matchmaking.DefaultRules())
+	require.NoError(t, err)
+	assert.Equal(t, res, again)
+}
+
+func TestPair_NoRepeatOpponents(t *testing.T) {
+	queue, table := ladder(4)
+	_, err := table.Record("m1", "t1", "t2", soccer.GameStats{})
+	require.NoError(t, err)
+	for _, id := range []string{"t1", "t2"} {
+		e := table.Teams[id]
+		e.Rating = map[string]float64{"t1": 2000, "t2": 1900}[id]
+		table.Teams[id] = e
+	}
+
+	res, err := matchmaking.Pair(rand.New(rand.NewSource(1)), queue, table, matchmaking.DefaultRules())
+	require.NoError(t, err)
+	vs := opponents(res)
+	assert.Equal(t, "t3", vs["t1"], "t1 and t2 met last match")
+	assert.Equal(t, "t4", vs["t2"])
+
+	// Three more matches each and the rematch is allowed again.
+	for i, pair := range [][2]string{{"t1", "t3"}, {"t1", "t4"}, {"t2", "t3"}, {"t2", "t4"}, {"t1", "t3"}, {"t2", "t4"}} {
+		_, err := table.Record(fmt.Sprintf("m%d", i+2), pair[0], pair[1], soccer.GameStats{})
+		require.NoError(t, err)
+	}
+	res, err = matchmaking.Pair(rand.New(rand.NewSource(1)), queue, table, matchmaking.DefaultRules())
+	require.NoError(t, err)
+	assert.Equal(t, "t2", opponents(res)["t1"])
+}
+
+// t1 has played the teams right below it, so it has to look further down
+// the ladder; past the window it waits.
+func TestPair_NoRepeatReachesDownTheLadder(t *testing.T) {
+	played := func(n, recent int) ([]soccer.GameLineup, *rating.Table, matchmaking.Rules) {
+		queue, table := ladder(n)
+		ratings := maps.Clone(table.Teams)
+		for i := 2; i < 2+recent; i++ {
+			_, err := table.Record(fmt.Sprintf("m%d", i), "t1", fmt.Sprintf("t%d", i), soccer.GameStats{})
+			require.NoError(t, err)
+		}
+		for id, e := range ratings {
+			table.Teams[id] = e
+		}
+		return queue, table, matchmaking.Rules{NoRepeat: recent}
+	}
+
+	queue, table, rules := played(6, 4)
+	res, err := matchmaking.Pair(rand.New(rand.NewSource(1)), queue, table, rules)
+	require.NoError(t, err)
+	assert.Empty(t, res.Unpaired)
+	vs := opponents(res)
+	assert.Equal(t, "t6", vs["t1"], "five places down")
+	assert.Equal(t, "t3", vs["t2"])
+	assert.Equal(t, "t5", vs["t4"])
+
+	queue, table, rules = played(10, 8)
+	res, err = matchmaking.Pair(rand.New(rand.NewSource(1)), queue, table, rules)
+	require.NoError(t, err)
+	assert.Contains(t, res.Unpaired, "t1", "t10 is nine places down")
+	assert.NotContains(t, opponents(res), "t1")
+}
+
+func TestPair_OddQueuesAndMismatchLimit(t *testing.T) {
+	queue, table := ladder(5)
+	res, err := matchmaking.Pair(rand.New(rand.NewSource(1)), queue, table, matchmaking.DefaultRules())
+	require.NoError(t, err)
+	assert.Len(t, res.Pairings, 2)
+	assert.Len(t, res.Unpaired, 1)
+
+	// A lone team far below the rest waits rather than take a thrashing.
+	table.Teams["t5"] = rating.Entry{Rating: 900}
+	rules := matchmaking.DefaultRules()
+	rules.MaxMismatch = 0.3
+	res, err = matchmaking.Pair(rand.New(rand.NewSource(1)), queue, table, rules)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t5"}, res.Unpaired)
+	for _, p := range res.Pairings {
+		assert.LessOrEqual(t, p.Mismatch(), 0.3)
+	}
+}
+
+func TestPair_SeedBreaksTies(t *testing.T) {
+	queue, table := ladder(8)
+	for id := range table.Teams {
+		table.Teams[id] = rating.Entry{Rating: 1500}
+	}
+	draws := map[string]bool{}
+	for seed := range int64(20) {
+		res, err := matchmaking.Pair(rand.New(rand.NewSource(seed)), queue, table, matchmaking.DefaultRules())
+		require.NoError(t, err)
+		require.Len(t, res.Pairings, 4)
+		var key []string
+		for _, p := range res.Pairings {
+			assert.Equal(t, 0.5, p.Expected)
+			key = append(key, p.Home+"-"+p.Away)
+		}
+		slices.Sort(key)
+		draws[fmt.Sprint(key)] = true
+	}
+	assert.Greater(t, len(draws), 5, "equal teams are paired differently by different seeds")
+}
+
+// Without ratings, teams are rated from their lineups, and the engine-based
+// estimate agrees that strong and weak sides are a mismatch.
+func TestPair_RatesFromTheEngine(t *testing.T) {
+	var queue []soccer.GameLineup
+	for i, f := range []soccer.FormationType{soccer.FormationTypeDiamond, soccer.FormationTypeBox} {
+		strong, weak := testdata.StrongTeam(f), testdata.WeakTeam(f)
+		strong.Team.ID, weak.Team.ID = fmt.Sprintf("strong-%d", i), fmt.Sprintf("weak-%d", i)
+		queue = append(queue, strong, weak)
+	}
+
+	for _, rules := range []matchmaking.Rules{{}, {Simulations: 200}} {
+		res, err := matchmaking.Pair(rand.New(rand.NewSource(3)), queue, nil, rules)
+		require.NoError(t, err)
+		vs := opponents(res)
+		assert.Equal(t, "strong-1", vs["strong-0"], "simulations: %d", rules.Simulations)
+		assert.Equal(t, "weak-1", vs["weak-0"], "simulations: %d", rules.Simulations)
+		for _, p := range res.Pairings {
+			assert.Less(t, p.Mismatch(), 0.2)
+		}
+	}
+}
+
+func TestPair_Errors(t *testing.T) {
+	queue, table := ladder(2)
+	_, err := matchmaking.Pair(nil, queue, table, matchmaking.DefaultRules())
+	assert.ErrorIs(t, err, soccer.ErrNilRandSource)
+
+	_, err = matchmaking.Pair(rand.New(rand.NewSource(1)), append(queue, queue[0]), table, matchmaking.DefaultRules())
+	assert.ErrorIs(t, err, matchmaking.ErrDuplicateTeam)
+}
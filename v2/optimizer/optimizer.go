@@ -0,0 +1,526 @@
+// Package optimizer picks a squad's best lineup: which players start,
+// in which formation and positions, with which roles and who takes the set
+// pieces.
+//
+// The search runs in two stages. A local search over every allowed
+// formation scores lineups with the engine's own kick-off numbers
+// (soccer.AnalyzeLineup): the share of chances each side gets and how
+// likely each is to be scored, against the opponent. The best lineups it
+// finds, including the best of every formation, then play the opponent
+// Rules.Simulations times each on the same dice (see package forecast),
+// and the one that wins most often is returned. Injuries and out-of-position
+// penalties are in the players' scores, so an injured star or a striker in
+// goal is weighed at what they would actually bring.
+//
+// Without an opponent, lineups are measured against the squad's benchmark:
+// the lineup a manager would pick by rating alone, in the team's formation.
+//
+// The budget is counted in moves, not time, so the same (seed, squad,
+// opponent, rules) always gives the same lineup.
+package optimizer
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/forecast"
+)
+
+var (
+	ErrInvalidRules    = errors.New("optimizer: invalid rules")
+	ErrSquadTooSmall   = errors.New("optimizer: squad too small")
+	ErrDuplicatePlayer = errors.New("optimizer: duplicate player")
+)
+
+// Rules are the optimizer's budget and search space.
+type Rules struct {
+	// TeamSize is how many players start. 0 fields as many as the opponent,
+	// or five without one.
+	TeamSize int `json:"team_size"`
+	// Formations limits the search to these formations. Empty searches
+	// every registered formation of the team size.
+	Formations []soccer.FormationType `json:"formations"`
+	// Iterations is the local search's budget in moves, shared between the
+	// formations.
+	Iterations int `json:"iterations"`
+	// Finalists is how many lineups are played out; Simulations is how many
+	// matches each plays.
+	Finalists   int `json:"finalists"`
+	Simulations int `json:"simulations"`
+	// AnyKeeper lets any player go in goal. By default only players who
+	// can play there do, as long as the squad has one: the engine scores
+	// an outfielder in goal on their outfield defense, which can rate a
+	// centre-back above a real keeper.
+	AnyKeeper bool `json:"any_keeper"`
+}
+
+// DefaultRules returns the rules the squad screen uses: every formation,
+// 4000 moves, and eight finalists over 200 matches each.
+func DefaultRules() Rules {
+	return Rules{Iterations: 4000, Finalists: 8, Simulations: 200}
+}
+
+// Roles are the roles the search hands out. A lineup has at most one
+// captain; the other roles can be shared.
+var Roles = []soccer.PlayerRole{
+	soccer.PlayerRoleNone,
+	soccer.PlayerRoleCaptain,
+	soccer.PlayerRolePlaymaker,
+	soccer.PlayerRoleTargetMan,
+	soccer.PlayerRoleBallWinner,
+}
+
+// Finalist is a lineup that was played out and how it went.
+type Finalist struct {
+	Lineup   soccer.GameLineup `json:"lineup"`
+	Forecast forecast.Forecast `json:"forecast"`
+}
+
+// Result is the optimizer's pick.
+type Result struct {
+	Lineup   soccer.GameLineup `json:"lineup"`
+	Forecast forecast.Forecast `json:"forecast"`
+	// Opponent is who the lineups were measured against: the opponent
+	// given, or the squad's benchmark.
+	Opponent soccer.GameLineup `json:"opponent"`
+	// Finalists are every lineup played out, best first; Lineup is the
+	// first.
+	Finalists []Finalist `json:"finalists"`
+	// Evaluated is how many lineups the local search scored.
+	Evaluated int `json:"evaluated"`
+}
+
+// Optimize returns the best lineup the squad can field against opponent,
+// which may be nil. team supplies the lineup's ID, name and tactics; its
+// formation and set-piece taker are replaced by the ones chosen.
+func Optimize(r *rand.Rand, team soccer.Team, squad []soccer.SelectedPlayer, opponent *soccer.GameLineup, rules Rules) (Result, error) {
+	if r == nil {
+		return Result{}, soccer.ErrNilRandSource
+	}
+	if rules.Iterations < 0 || rules.Finalists < 1 || rules.Simulations < 1 {
+		return Result{}, fmt.Errorf("%w: need at least one finalist and one simulation", ErrInvalidRules)
+	}
+	size := rules.TeamSize
+	if size == 0 {
+		size = 5
+		if opponent != nil {
+			size = len(opponent.Players)
+		}
+	}
+	if len(squad) < size {
+		return Result{}, fmt.Errorf("%w: %d players for a %d-a-side lineup", ErrSquadTooSmall, len(squad), size)
+	}
+	seen := make(map[string]bool, len(squad))
+	for _, p := range squad {
+		if seen[p.ID] {
+			return Result{}, fmt.Errorf("%w: %q", ErrDuplicatePlayer, p.ID)
+		}
+		seen[p.ID] = true
+	}
+	formations, err := formationsFor(size, rules.Formations)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Sort the squad so its order doesn't matter.
+	squad = slices.Clone(squad)
+	slices.SortFunc(squad, func(a, b soccer.SelectedPlayer) int { return cmp.Compare(a.ID, b.ID) })
+
+	var against soccer.GameLineup
+	if opponent != nil {
+		against = *opponent
+	} else {
+		bench := formations[0]
+		if i := slices.IndexFunc(formations, func(f soccer.FormationConfig) bool { return f.FormationType == team.Formation }); i >= 0 {
+			bench = formations[i]
+		}
+		against = benchmark(team, squad, bench)
+	}
+
+	profile, err := soccer.AnalyzeLineup(against)
+	if err != nil {
+		return Result{}, err
+	}
+	s := search{r: r, team: team, squad: squad, opponent: profile, optima: make(map[string]candidate)}
+	if !rules.AnyKeeper {
+		s.keepers = keepers(squad)
+	}
+	for i, f := range formations {
+		moves := rules.Iterations / len(formations)
+		if i < rules.Iterations%len(formations) {
+			moves++
+		}
+		s.climb(f, moves)
+	}
+
+	finalists := s.finalists(rules.Finalists)
+	seed := r.Int63()
+	res := Result{Opponent: against, Evaluated: s.evaluated}
+	for _, c := range finalists {
+		f, err := forecast.Simulate(seed, c.lineup, against, rules.Simulations)
+		if err != nil {
+			return Result{}, err
+		}
+		res.Finalists = append(res.Finalists, Finalist{Lineup: c.lineup, Forecast: f})
+	}
+	slices.SortStableFunc(res.Finalists, func(a, b Finalist) int {
+		if c := cmp.Compare(b.Forecast.Win, a.Forecast.Win); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Forecast.Points(), a.Forecast.Points())
+	})
+	res.Lineup, res.Forecast = res.Finalists[0].Lineup, res.Finalists[0].Forecast
+	return res, nil
+}
+
+// formationsFor returns the formations to search, sorted by type.
+func formationsFor(size int, only []soccer.FormationType) ([]soccer.FormationConfig, error) {
+	var out []soccer.FormationConfig
+	if len(only) == 0 {
+		for _, f := range soccer.Formations() {
+			if f.TeamSize() == size {
+				out = append(out, f)
+			}
+		}
+		if len(out) == 0 {
+			return nil, fmt.Errorf("%w: no formation fields %d", ErrInvalidRules, size)
+		}
+		return out, nil
+	}
+	for _, t := range slices.Compact(slices.Sorted(slices.Values(only))) {
+		f, err := soccer.LookupFormation(t)
+		if err != nil {
+			return nil, err
+		}
+		if f.TeamSize() != size {
+			return nil, fmt.Errorf("%w: %q fields %d, not %d", ErrInvalidRules, t, f.TeamSize(), size)
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// benchmark is the lineup a manager would pick by rating alone: the
+// formation's slots filled in order with the highest OverallRating player
+// who can play there, or the highest-rated player left when nobody can.
+// It has no roles and no set-piece taker.
+func benchmark(team soccer.Team, squad []soccer.SelectedPlayer, formation soccer.FormationConfig) soccer.GameLineup {
+	ranked := slices.Clone(squad)
+	slices.SortStableFunc(ranked, func(a, b soccer.SelectedPlayer) int {
+		return cmp.Compare(b.Attributes.OverallRating, a.Attributes.OverallRating)
+	})
+	team.Formation = formation.FormationType
+	team.Tactics.SetPieceTaker = ""
+	lineup := soccer.GameLineup{Team: team}
+	used := make(map[string]bool)
+	for _, pos := range slots(formation) {
+		pick := -1
+		for i, p := range ranked {
+			if used[p.ID] {
+				continue
+			}
+			p.SelectedPosition = pos
+			if !p.IsOutOfPosition() {
+				pick = i
+				break
+			}
+			if pick < 0 {
+				pick = i
+			}
+		}
+		p := ranked[pick]
+		p.SelectedPosition, p.Role = pos, soccer.PlayerRoleNone
+		used[p.ID] = true
+		lineup.Players = append(lineup.Players, p)
+	}
+	return lineup
+}
+
+// keepers returns the squad indexes of the players who can play in goal,
+// or nil if nobody can.
+func keepers(squad []soccer.SelectedPlayer) map[int]bool {
+	var out map[int]bool
+	for i, p := range squad {
+		p.SelectedPosition = soccer.PlayerPositionGoalkeeper
+		if !p.IsOutOfPosition() {
+			if out == nil {
+				out = make(map[int]bool)
+			}
+			out[i] = true
+		}
+	}
+	return out
+}
+
+// slots returns the formation's positions in slot order.
+func slots(f soccer.FormationConfig) []soccer.PlayerPosition {
+	out := make([]soccer.PlayerPosition, f.TeamSize())
+	for i := range out {
+		out[i] = f.Slots[uint64(i+1)]
+	}
+	return out
+}
+
+// state is a lineup under search: the squad index and role in each slot,
+// and the slot of the set-piece taker (-1 for none).
+type state struct {
+	formation soccer.FormationConfig
+	positions []soccer.PlayerPosition
+	picks     []int
+	roles     []soccer.PlayerRole
+	taker     int
+}
+
+func (s state) clone() state {
+	s.picks, s.roles = slices.Clone(s.picks), slices.Clone(s.roles)
+	return s
+}
+
+type candidate struct {
+	lineup soccer.GameLineup
+	score  float64
+	order  int // when it was found
+}
+
+type search struct {
+	r         *rand.Rand
+	team      soccer.Team
+	squad     []soccer.SelectedPlayer
+	opponent  soccer.TeamProfile
+	keepers   map[int]bool // who may go in goal; nil for anyone
+	optima    map[string]candidate
+	evaluated int
+}
+
+// allowed reports whether st's goalkeeper may play in goal.
+func (s *search) allowed(st state) bool {
+	if s.keepers == nil {
+		return true
+	}
+	for i, pos := range st.positions {
+		if pos == soccer.PlayerPositionGoalkeeper && !s.keepers[st.picks[i]] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *search) lineup(st state) soccer.GameLineup {
+	team := s.team
+	team.Formation = st.formation.FormationType
+	team.Tactics.SetPieceTaker = ""
+	lineup := soccer.GameLineup{Team: team, Players: make([]soccer.SelectedPlayer, len(st.picks))}
+	for i, pick := range st.picks {
+		p := s.squad[pick]
+		p.SelectedPosition, p.Role = st.positions[i], st.roles[i]
+		lineup.Players[i] = p
+	}
+	if st.taker >= 0 {
+		lineup.Team.Tactics.SetPieceTaker = lineup.Players[st.taker].ID
+	}
+	return lineup
+}
+
+func (s *search) score(st state) float64 {
+	s.evaluated++
+	profile, err := soccer.AnalyzeLineup(s.lineup(st))
+	if err != nil {
+		// Can't happen: formationsFor only hands out registered formations
+		// of the squad's size. Never prefer such a lineup all the same.
+		return math.Inf(-1)
+	}
+	return edge(profile, s.opponent)
+}
+
+// climb runs the local search in one formation for the given number of
+// moves. It starts from the benchmark pick and takes the first neighbour,
+// in a random order, that scores better. When no neighbour does, the lineup
+// is a local best: it is kept and the search starts again from a random
+// pick.
+func (s *search) climb(f soccer.FormationConfig, moves int) {
+	start := benchmark(s.team, s.squad, f)
+	index := make(map[string]int, len(s.squad))
+	for i, p := range s.squad {
+		index[p.ID] = i
+	}
+	cur := state{formation: f, positions: slots(f), roles: make([]soccer.PlayerRole, f.TeamSize()), taker: -1}
+	for _, p := range start.Players {
+		cur.picks = append(cur.picks, index[p.ID])
+	}
+	score := s.score(cur)
+
+	for moves > 0 {
+		improved := false
+		neighbours := s.neighbours(cur)
+		s.r.Shuffle(len(neighbours), func(i, j int) { neighbours[i], neighbours[j] = neighbours[j], neighbours[i] })
+		for _, next := range neighbours {
+			if moves == 0 {
+				break
+			}
+			moves--
+			if ns := s.score(next); ns > score {
+				cur, score, improved = next, ns, true
+				break
+			}
+		}
+		if !improved && moves > 0 {
+			s.record(cur, score)
+			cur.picks = s.randomPicks(cur.positions)
+			clear(cur.roles)
+			cur.taker = -1
+			score = s.score(cur)
+			moves--
+		}
+	}
+	s.record(cur, score)
+}
+
+// randomPicks returns a random set of starters for the positions, with a
+// keeper in goal if one is required.
+func (s *search) randomPicks(positions []soccer.PlayerPosition) []int {
+	perm := s.r.Perm(len(s.squad))
+	gk := slices.Index(positions, soccer.PlayerPositionGoalkeeper)
+	if s.keepers != nil && !s.keepers[perm[gk]] {
+		// Swap in the first keeper in the permutation, from the starters
+		// or the bench.
+		k := slices.IndexFunc(perm, func(i int) bool { return s.keepers[i] })
+		perm[gk], perm[k] = perm[k], perm[gk]
+	}
+	return perm[:len(positions)]
+}
+
+// neighbours returns every lineup one change away from st: two starters
+// swap places, a starter makes way for someone on the bench, a starter's
+// role changes or the set-piece taker changes.
+func (s *search) neighbours(st state) []state {
+	var out []state
+	add := func(next state) {
+		if s.allowed(next) {
+			out = append(out, next)
+		}
+	}
+	n := len(st.picks)
+	starting := make(map[int]bool, n)
+	for _, p := range st.picks {
+		starting[p] = true
+	}
+	for i := range n {
+		for j := i + 1; j < n; j++ {
+			if st.positions[i] != st.positions[j] || st.roles[i] != st.roles[j] {
+				next := st.clone()
+				next.picks[i], next.picks[j] = next.picks[j], next.picks[i]
+				add(next)
+			}
+		}
+		for b := range s.squad {
+			if !starting[b] {
+				next := st.clone()
+				next.picks[i] = b
+				add(next)
+			}
+		}
+		for _, role := range Roles {
+			if role == st.roles[i] {
+				continue
+			}
+			next := st.clone()
+			if role == soccer.PlayerRoleCaptain {
+				for j, r := range next.roles {
+					if r == soccer.PlayerRoleCaptain {
+						next.roles[j] = soccer.PlayerRoleNone
+					}
+				}
+			}
+			next.roles[i] = role
+			add(next)
+		}
+	}
+	for taker := -1; taker < n; taker++ {
+		if taker != st.taker {
+			next := st.clone()
+			next.taker = taker
+			add(next)
+		}
+	}
+	return out
+}
+
+// record keeps a lineup the search settled on.
+func (s *search) record(st state, score float64) {
+	lineup := s.lineup(st)
+	key := lineupKey(lineup)
+	if _, ok := s.optima[key]; !ok {
+		s.optima[key] = candidate{lineup: lineup, score: score, order: len(s.optima)}
+	}
+}
+
+// finalists returns up to n of the best lineups found: the best of each
+// formation first, then the best of the rest.
+func (s *search) finalists(n int) []candidate {
+	all := slices.SortedFunc(maps.Values(s.optima), func(a, b candidate) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.order, b.order)
+	})
+	var out []candidate
+	picked := make(map[int]bool)
+	formations := make(map[soccer.FormationType]bool)
+	for i, c := range all {
+		if len(out) < n && !formations[c.lineup.Team.Formation] {
+			formations[c.lineup.Team.Formation] = true
+			picked[i] = true
+			out = append(out, c)
+		}
+	}
+	for i, c := range all {
+		if len(out) < n && !picked[i] {
+			out = append(out, c)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b candidate) int { return cmp.Compare(b.score, a.score) })
+	return out
+}
+
+func lineupKey(l soccer.GameLineup) string {
+	var b strings.Builder
+	b.WriteString(string(l.Team.Formation) + "|" + l.Team.Tactics.SetPieceTaker)
+	for _, p := range l.Players {
+		b.WriteString("|" + p.ID + ":" + string(p.Role))
+	}
+	return b.String()
+}
+
+// edge is a's expected goal difference per chance against b: the share of
+// the chances each side gets times how likely each side's chances are to go
+// in.
+func edge(a, b soccer.TeamProfile) float64 {
+	share := a.Control / (a.Control + b.Control)
+	return share*conversion(a, b) - (1-share)*conversion(b, a)
+}
+
+// conversion is how likely an average chance of att's is to be scored
+// against def: the goal probability of every chance type and taker,
+// weighted by how often each comes up.
+func conversion(att, def soccer.TeamProfile) float64 {
+	var total float64
+	for _, ct := range slices.Sorted(maps.Keys(att.ChanceMix)) {
+		d := max(def.Defense[ct], 1)
+		var p float64
+		for _, c := range att.Players {
+			if share := c.ChanceShare[ct]; share > 0 {
+				a := max(c.Attack[ct], 1)
+				p += share * a / (a + d)
+			}
+		}
+		total += att.ChanceMix[ct] * p
+	}
+	return total
+}
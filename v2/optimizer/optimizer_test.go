@@ -0,0 +1,133 @@
+package optimizer_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/optimizer"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// squad returns the strong Y fixture's players, "1".."5", and the weak
+// one's, "6".."10", shuffled.
+func squad() []soccer.SelectedPlayer {
+	var players []soccer.SelectedPlayer
+	for _, l := range []soccer.GameLineup{testdata.StrongTeam(soccer.FormationTypeY), testdata.WeakTeam(soccer.FormationTypeY)} {
+		for _, p := range l.Players {
+			a := p.Attributes
+			p.Attributes.OverallRating = (max(a.GoalkeeperRating, a.DefenseRating, a.ControlRating, a.AttackRating) + a.SpeedRating) / 2
+			players = append(players, p)
+		}
+	}
+	rand.New(rand.NewSource(9)).Shuffle(len(players), func(i, j int) { players[i], players[j] = players[j], players[i] })
+	return players
+}
+
+func rules() optimizer.Rules {
+	return optimizer.Rules{Iterations: 800, Finalists: 4, Simulations: 60}
+}
+
+func ids(l soccer.GameLineup) []string {
+	var out []string
+	for _, p := range l.Players {
+		out = append(out, p.ID)
+	}
+	return out
+}
+
+func TestOptimize_PicksTheStrongPlayers(t *testing.T) {
+	opponent := testdata.WeakTeam(soccer.FormationTypeDiamond)
+	team := soccer.Team{ID: "me"}
+	res, err := optimizer.Optimize(rand.New(rand.NewSource(1)), team, squad(), &opponent, rules())
+	require.NoError(t, err)
+
+	require.Len(t, res.Lineup.Players, 5)
+	assert.Equal(t, "me", res.Lineup.Team.ID)
+	var strong int
+	for _, id := range ids(res.Lineup) {
+		if id <= "5" && len(id) == 1 {
+			strong++
+		}
+	}
+	assert.GreaterOrEqual(t, strong, 4)
+	assert.Equal(t, soccer.PlayerPositionGoalkeeper, res.Lineup.Players[slices.Index(ids(res.Lineup), "1")].SelectedPosition)
+	assert.Greater(t, res.Forecast.Win, 0.7)
+	assert.Positive(t, res.Evaluated)
+
+	_, _, err = soccer.RunGameWithSeed(rand.New(rand.NewSource(1)), res.Lineup, opponent)
+	assert.NoError(t, err, "the lineup is playable")
+
+	again, err := optimizer.Optimize(rand.New(rand.NewSource(1)), team, squad(), &opponent, rules())
+	require.NoError(t, err)
+	assert.Equal(t, res, again)
+}
+
+func TestOptimize_HonoursInjuries(t *testing.T) {
+	players := squad()
+	for i := range players {
+		if players[i].ID == "1" {
+			players[i].Injury = &soccer.InjuryEvent{PlayerID: "1", Injury: soccer.Injury{StatsReduction: 0.1}}
+		}
+	}
+	opponent := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	res, err := optimizer.Optimize(rand.New(rand.NewSource(1)), soccer.Team{ID: "me"}, players, &opponent, rules())
+	require.NoError(t, err)
+
+	keeper := slices.IndexFunc(res.Lineup.Players, func(p soccer.SelectedPlayer) bool {
+		return p.SelectedPosition == soccer.PlayerPositionGoalkeeper
+	})
+	assert.Equal(t, "6", res.Lineup.Players[keeper].ID, "the fit reserve keeper starts")
+}
+
+func TestOptimize_WithoutAnOpponent(t *testing.T) {
+	r := rules()
+	r.Formations = []soccer.FormationType{soccer.FormationTypeBox, soccer.FormationTypeDiamond, soccer.FormationTypeBox}
+	res, err := optimizer.Optimize(rand.New(rand.NewSource(2)), soccer.Team{ID: "me", Formation: soccer.FormationTypeDiamond}, squad(), nil, r)
+	require.NoError(t, err)
+
+	assert.Equal(t, soccer.FormationTypeDiamond, res.Opponent.Team.Formation, "measured against the rating-only pick")
+	assert.Equal(t, []string{"1", "2", "3", "8", "4"}, ids(res.Opponent), "the second midfielder is the best one left")
+	assert.Contains(t, []soccer.FormationType{soccer.FormationTypeBox, soccer.FormationTypeDiamond}, res.Lineup.Team.Formation)
+
+	require.LessOrEqual(t, len(res.Finalists), 4)
+	formations := map[soccer.FormationType]bool{}
+	for i, f := range res.Finalists {
+		formations[f.Lineup.Team.Formation] = true
+		if i > 0 {
+			assert.LessOrEqual(t, f.Forecast.Win, res.Finalists[i-1].Forecast.Win)
+		}
+	}
+	assert.Len(t, formations, 2, "every formation reaches the final")
+	assert.Equal(t, res.Finalists[0].Lineup, res.Lineup)
+}
+
+func TestOptimize_Errors(t *testing.T) {
+	team := soccer.Team{ID: "me"}
+	_, err := optimizer.Optimize(nil, team, squad(), nil, rules())
+	assert.ErrorIs(t, err, soccer.ErrNilRandSource)
+
+	_, err = optimizer.Optimize(rand.New(rand.NewSource(1)), team, squad()[:4], nil, rules())
+	assert.ErrorIs(t, err, optimizer.ErrSquadTooSmall)
+
+	players := squad()
+	_, err = optimizer.Optimize(rand.New(rand.NewSource(1)), team, append(players, players[0]), nil, rules())
+	assert.ErrorIs(t, err, optimizer.ErrDuplicatePlayer)
+
+	r := rules()
+	r.Formations = []soccer.FormationType{soccer.FormationTypeClassic}
+	_, err = optimizer.Optimize(rand.New(rand.NewSource(1)), team, squad(), nil, r)
+	assert.ErrorIs(t, err, optimizer.ErrInvalidRules)
+
+	r.Formations = []soccer.FormationType{"Christmas Tree"}
+	_, err = optimizer.Optimize(rand.New(rand.NewSource(1)), team, squad(), nil, r)
+	assert.ErrorIs(t, err, soccer.ErrUnknownFormation)
+
+	opponent := testdata.WeakTeam(soccer.FormationTypeDiamond)
+	opponent.Team.Formation = "Christmas Tree"
+	_, err = optimizer.Optimize(rand.New(rand.NewSource(1)), team, squad(), &opponent, rules())
+	assert.ErrorIs(t, err, soccer.ErrUnknownFormation)
+}
@@ -2,15 +2,23 @@ package soccer
 
 import (
 	"errors"
+	"fmt"
 	"math/rand"
+
+	"github.com/stein-f/oink-soccer-common/v2/internal/tuning"
 )
 
 // ErrNoPenaltyTakers is returned by RunShootoutWithSeed when a lineup has no
 // players to take penalties.
 var ErrNoPenaltyTakers = errors.New("soccer: shootout requires players on both teams")
 
-// PenaltyDirection is the side of the goal a penalty is aimed at. The set is
-// dictated by the available artwork (left / mid / right), not by the physics.
+// ErrInvalidShootoutPlan is returned by RunShootoutWithSeed when a lineup's
+// ShootoutPlan can't be applied. The wrapped message names the problem.
+var ErrInvalidShootoutPlan = errors.New("soccer: invalid shootout plan")
+
+// PenaltyDirection is the side of the goal a penalty is aimed at, or the way
+// the keeper dives (Mid = stays up). The set is dictated by the available
+// artwork (left / mid / right), not by the physics.
 type PenaltyDirection string
 
 const (
@@ -21,7 +29,8 @@ const (
 
 // PenaltyResult is the binary outcome of a penalty. There is no distinct
 // "saved" value: the artwork only distinguishes scored from missed, and a save
-// and an off-target shot both map to Missed (commentary text may still differ).
+// and an off-target shot both map to Missed. PenaltyOutcome.MissType carries
+// the distinction for commentary.
 type PenaltyResult string
 
 const (
@@ -29,6 +38,15 @@ const (
 	PenaltyResultMissed PenaltyResult = "Missed"
 )
 
+// PenaltyMissType says how a missed penalty was missed. Empty for a goal.
+type PenaltyMissType string
+
+const (
+	PenaltyMissTypeNone      PenaltyMissType = ""
+	PenaltyMissTypeSaved     PenaltyMissType = "Saved"
+	PenaltyMissTypeOffTarget PenaltyMissType = "Off Target"
+)
+
 // penaltyDirections pins iteration / index order for deterministic direction
 // selection.
 var penaltyDirections = []PenaltyDirection{
@@ -42,12 +60,17 @@ var penaltyDirections = []PenaltyDirection{
 // future in-match spot kick) can flow through the same highlight pipeline
 // unchanged. Team colour (home=red, away=blue) is derived by the consumer from
 // TeamType — it is not baked in here.
+//
+// Direction is where the taker aimed and KeeperDive is where the keeper went;
+// the keeper saves far more often when the two match.
 type PenaltyOutcome struct {
-	TakerID   string           `json:"taker_id"`
-	KeeperID  string           `json:"keeper_id"`
-	TeamType  TeamType         `json:"team_type"`
-	Direction PenaltyDirection `json:"direction"`
-	Result    PenaltyResult    `json:"result"`
+	TakerID    string           `json:"taker_id"`
+	KeeperID   string           `json:"keeper_id"`
+	TeamType   TeamType         `json:"team_type"`
+	Direction  PenaltyDirection `json:"direction"`
+	KeeperDive PenaltyDirection `json:"keeper_dive"`
+	Result     PenaltyResult    `json:"result"`
+	MissType   PenaltyMissType  `json:"miss_type,omitempty"`
 }
 
 // IsGoal reports whether the penalty was scored.
@@ -56,18 +79,48 @@ func (p PenaltyOutcome) IsGoal() bool {
 }
 
 // TakePenaltyWithSeed resolves a single penalty between a taker and a keeper,
-// deterministically from the supplied random source. It is attribute-driven and
-// knows nothing about shootouts or cups, so it is reusable for any spot kick.
-//
-// Conversion mirrors the in-match penalty model: the taker's score pairs their
-// AttackRating with Composure (the clutch-finisher attribute) and applies the
-// penalty AttackBoost; the keeper's score is their goalkeeper save ability
-// scaled down by the penalty DefenseScale. Goal probability is atk/(atk+def) —
-// the same formula the match engine uses for open play — which yields the high
-// conversion rates expected of penalties.
+// deterministically from the supplied random source, with no history for the
+// keeper to read. See TakePenaltyWithHistory.
 func TakePenaltyWithSeed(r *rand.Rand, taker, keeper SelectedPlayer, teamType TeamType) PenaltyOutcome {
+	return TakePenaltyWithHistory(r, taker, keeper, teamType, nil)
+}
+
+// TakePenaltyWithHistory resolves a single penalty between a taker and a
+// keeper, deterministically from the supplied random source. It is
+// attribute-driven and knows nothing about shootouts or cups, so it is
+// reusable for any spot kick. history is the taker's previous penalty
+// directions (any order), as the keeper's analysts would know them; nil is
+// fine.
+//
+// The penalty is a guessing game (see tuning's "Penalty mind games"):
+//
+//  1. The taker picks a side from their tendencies — Composure sets how
+//     often they go down the middle, PenaltySide their favourite corner.
+//  2. The keeper picks a dive from their own tendencies (PenaltySide is
+//     their favourite way to dive), leaning on the taker's history in
+//     proportion to their Positioning.
+//  3. The taker may miss the target: more likely in the corners, less with
+//     high Technique.
+//  4. On target, the shot/save duel mirrors the in-match penalty model —
+//     atk/(atk+def) with the taker's AttackRating + Composure against the
+//     keeper's save rating (Reflexes) — and is decided mostly by
+//     whether the keeper guessed right.
+func TakePenaltyWithHistory(r *rand.Rand, taker, keeper SelectedPlayer, teamType TeamType, history []PenaltyDirection) PenaltyOutcome {
+	return takePenalty(r, taker, keeper, teamType, history, penaltyPressure{})
+}
+
+// penaltyPressure is the shootout situation a kick is taken in. The zero
+// value is a routine kick.
+type penaltyPressure struct {
+	level     float64 // 0..1, see tuning's "Shootout pressure"
+	mustScore bool    // missing loses the shootout
+}
+
+func takePenalty(r *rand.Rand, taker, keeper SelectedPlayer, teamType TeamType, history []PenaltyDirection, pressure penaltyPressure) PenaltyOutcome {
+	takerAttrs, keeperAttrs := injuredAttributes(taker), injuredAttributes(keeper)
 	atk := playerAttackForChance(taker, ChanceTypePenalty) * chanceTypeAttackBoost(ChanceTypePenalty)
-	def := playerDefense(keeper, Tactics{}) * chanceTypeDefenseScale(ChanceTypePenalty)
+	atk *= tuning.PenaltyPressureFactor(takerAttrs.EffectiveComposure(), pressure.level, pressure.mustScore)
+	def := playerDefenseForChance(keeper, Tactics{}, ChanceTypePenalty) * chanceTypeDefenseScale(ChanceTypePenalty)
 
 	if atk < 1 {
 		atk = 1
@@ -76,31 +129,137 @@ func TakePenaltyWithSeed(r *rand.Rand, taker, keeper SelectedPlayer, teamType Te
 		def = 1
 	}
 
-	scored := r.Float64() < atk/(atk+def)
-	direction := penaltyDirections[r.Intn(len(penaltyDirections))]
+	direction := pickPenaltyDirection(r, penaltyTakerWeights(takerAttrs))
+	dive := pickPenaltyDirection(r, penaltyKeeperWeights(keeperAttrs, history))
+
+	outcome := PenaltyOutcome{
+		TakerID:    taker.ID,
+		KeeperID:   keeper.ID,
+		TeamType:   teamType,
+		Direction:  direction,
+		KeeperDive: dive,
+		Result:     PenaltyResultMissed,
+	}
+
+	offTarget := tuning.PenaltyOffTargetChance(direction != PenaltyDirectionMid, takerAttrs.EffectiveTechnique())
+	if r.Float64() < offTarget {
+		outcome.MissType = PenaltyMissTypeOffTarget
+		return outcome
+	}
+	if r.Float64() < tuning.PenaltyGoalChance(atk/(atk+def), direction == dive) {
+		outcome.Result = PenaltyResultScored
+		return outcome
+	}
+	outcome.MissType = PenaltyMissTypeSaved
+	return outcome
+}
+
+// penaltyWeights are selection weights aligned with penaltyDirections.
+type penaltyWeights [3]float64
+
+// penaltyTakerWeights returns where a taker aims: Composure sets the share
+// down the middle, the rest splits between the corners, tilted towards
+// PenaltySide when the player has one.
+func penaltyTakerWeights(p PlayerAttributes) penaltyWeights {
+	mid := tuning.PenaltyTakerMidShare(p.EffectiveComposure())
+	w := penaltyWeights{(1 - mid) / 2, mid, (1 - mid) / 2}
+	return w.prefer(p.PenaltySide)
+}
+
+// penaltyKeeperWeights returns where a keeper dives: usually to a corner,
+// tilted towards their own PenaltySide, then blended towards the taker's
+// history as far as the keeper can read it.
+func penaltyKeeperWeights(p PlayerAttributes, history []PenaltyDirection) penaltyWeights {
+	stay := tuning.PenaltyKeeperStayWeight
+	w := penaltyWeights{(1 - stay) / 2, stay, (1 - stay) / 2}.prefer(p.PenaltySide)
 
-	result := PenaltyResultMissed
-	if scored {
-		result = PenaltyResultScored
+	var seen penaltyWeights
+	var n int
+	for _, d := range history {
+		if i := penaltyDirectionIndex(d); i >= 0 {
+			seen[i]++
+			n++
+		}
+	}
+	if n == 0 {
+		return w
 	}
+	read := tuning.PenaltyReadStrength(n, p.EffectivePositioning())
+	for i := range w {
+		w[i] = (1-read)*w[i] + read*seen[i]/float64(n)
+	}
+	return w
+}
 
-	return PenaltyOutcome{
-		TakerID:   taker.ID,
-		KeeperID:  keeper.ID,
-		TeamType:  teamType,
-		Direction: direction,
-		Result:    result,
+// prefer shifts tuning.PenaltyPreferredSideBias of the weight onto side.
+// An empty or unknown side leaves the weights alone.
+func (w penaltyWeights) prefer(side PenaltyDirection) penaltyWeights {
+	i := penaltyDirectionIndex(side)
+	if i < 0 {
+		return w
 	}
+	for j := range w {
+		w[j] *= 1 - tuning.PenaltyPreferredSideBias
+	}
+	w[i] += tuning.PenaltyPreferredSideBias
+	return w
+}
+
+func penaltyDirectionIndex(d PenaltyDirection) int {
+	for i, candidate := range penaltyDirections {
+		if candidate == d {
+			return i
+		}
+	}
+	return -1
+}
+
+func pickPenaltyDirection(r *rand.Rand, w penaltyWeights) PenaltyDirection {
+	total := w[0] + w[1] + w[2]
+	pick := r.Float64() * total
+	var cum float64
+	for i, d := range penaltyDirections {
+		cum += w[i]
+		if pick < cum {
+			return d
+		}
+	}
+	return penaltyDirections[len(penaltyDirections)-1]
 }
 
 // ShootoutResult is the outcome of a penalty shootout. Kicks is the full ordered
 // stream of penalties (home, away, home, away, …) so consumers can replay it as
-// highlights.
+// highlights. DecisiveKick is the index in Kicks of the kick that settled the
+// shootout, or -1 in the (practically unreachable) case that sudden death hit
+// its cap and the winner was drawn at random.
 type ShootoutResult struct {
-	HomeScore int              `json:"home_score"`
-	AwayScore int              `json:"away_score"`
-	Winner    TeamType         `json:"winner"`
-	Kicks     []PenaltyOutcome `json:"kicks"`
+	HomeScore    int              `json:"home_score"`
+	AwayScore    int              `json:"away_score"`
+	Winner       TeamType         `json:"winner"`
+	Kicks        []PenaltyOutcome `json:"kicks"`
+	DecisiveKick int              `json:"decisive_kick"`
+}
+
+// ShootoutPlan is a manager's preparation for a shootout. Both fields are
+// optional; the zero value is the default order with the lineup's own
+// goalkeeper.
+//
+//   - Order lists player IDs in the order they take their kicks. Anyone not
+//     listed follows in the default order (outfield players in lineup order,
+//     then the goalkeeper), so a manager can name just the first five.
+//   - Keeper is a substitute brought on to face the opponent's kicks — a
+//     shootout specialist. They replace the lineup's goalkeeper, including
+//     in the taker rotation. Their ID must not belong to another player in
+//     the lineup.
+type ShootoutPlan struct {
+	Order  []string        `json:"order,omitempty"`
+	Keeper *SelectedPlayer `json:"keeper,omitempty"`
+}
+
+// IsZero reports whether the plan leaves everything to the defaults. An
+// empty but non-nil Order counts, so a decoded "order": [] is still omitted.
+func (p ShootoutPlan) IsZero() bool {
+	return len(p.Order) == 0 && p.Keeper == nil
 }
 
 const (
@@ -115,29 +274,45 @@ const (
 )
 
 // RunShootoutWithSeed runs a penalty shootout between two lineups,
-// deterministically from the supplied random source. It is a thin loop over
-// TakePenaltyWithSeed: each team's players take in turn (every player takes a
-// penalty), best-of-5 regulation followed by sudden death until one team leads
-// after an equal number of kicks.
+// deterministically from the supplied random source. Each team's players take
+// in turn (every player takes a penalty), in the order set by the lineup's
+// ShootoutPlan, best-of-5 regulation followed by sudden death until one team
+// leads after an equal number of kicks. Each kick is a TakePenaltyWithHistory
+// (the history being the taker's earlier kicks in this shootout) taken under
+// the shootout's pressure: Composure counts for more on kicks four and five
+// and in sudden death, and a taker who must score to stay alive is weighed
+// down by it.
+//
+// Returns ErrNoPenaltyTakers if either lineup is empty and
+// ErrInvalidShootoutPlan if a plan names an unknown or repeated taker or a
+// substitute keeper who clashes with the lineup.
 func RunShootoutWithSeed(r *rand.Rand, home, away GameLineup) (ShootoutResult, error) {
 	if r == nil {
 		return ShootoutResult{}, ErrNilRandSource
 	}
-
-	homeTakers := penaltyTakerOrder(home)
-	awayTakers := penaltyTakerOrder(away)
-	if len(homeTakers) == 0 || len(awayTakers) == 0 {
+	if len(home.Players) == 0 || len(away.Players) == 0 {
 		return ShootoutResult{}, ErrNoPenaltyTakers
 	}
 
-	homeKeeper := findShootoutKeeper(home)
-	awayKeeper := findShootoutKeeper(away)
+	homeTakers, homeKeeper, err := shootoutSquad(home)
+	if err != nil {
+		return ShootoutResult{}, fmt.Errorf("home: %w", err)
+	}
+	awayTakers, awayKeeper, err := shootoutSquad(away)
+	if err != nil {
+		return ShootoutResult{}, fmt.Errorf("away: %w", err)
+	}
+
+	result := ShootoutResult{DecisiveKick: -1}
 
-	var result ShootoutResult
+	// Each keeper sees where a taker went earlier in this shootout when the
+	// rotation brings them round again in sudden death.
+	history := make(map[string][]PenaltyDirection)
 
-	kick := func(takers []SelectedPlayer, keeper SelectedPlayer, team TeamType, index int) {
+	kick := func(takers []SelectedPlayer, keeper SelectedPlayer, team TeamType, index int, pressure penaltyPressure) {
 		taker := takers[index%len(takers)]
-		outcome := TakePenaltyWithSeed(r, taker, keeper, team)
+		outcome := takePenalty(r, taker, keeper, team, history[taker.ID], pressure)
+		history[taker.ID] = append(history[taker.ID], outcome.Direction)
 		result.Kicks = append(result.Kicks, outcome)
 		if outcome.IsGoal() {
 			if team == TeamTypeHome {
@@ -160,24 +335,43 @@ func RunShootoutWithSeed(r *rand.Rand, home, away GameLineup) (ShootoutResult, e
 		return result.HomeScore > result.AwayScore+awayRemaining ||
 			result.AwayScore > result.HomeScore+homeRemaining
 	}
+	// regulationPressure is the pressure on a team's (taken+1)th kick: a miss
+	// loses if the opponent is already out of reach of the kicks left after it.
+	regulationPressure := func(own, opponent, taken int) penaltyPressure {
+		level := 0.0
+		if taken >= shootoutRegulationKicks-2 {
+			level = tuning.PenaltyPressureLateKick
+		}
+		return penaltyPressure{level: level, mustScore: opponent > own+shootoutRegulationKicks-taken-1}
+	}
 	for i := 0; i < shootoutRegulationKicks; i++ {
-		kick(homeTakers, awayKeeper, TeamTypeHome, i)
+		kick(homeTakers, awayKeeper, TeamTypeHome, i, regulationPressure(result.HomeScore, result.AwayScore, homeTaken))
 		homeTaken++
 		if decided() {
+			result.DecisiveKick = len(result.Kicks) - 1
 			break
 		}
-		kick(awayTakers, homeKeeper, TeamTypeAway, i)
+		kick(awayTakers, homeKeeper, TeamTypeAway, i, regulationPressure(result.AwayScore, result.HomeScore, awayTaken))
 		awayTaken++
 		if decided() {
+			result.DecisiveKick = len(result.Kicks) - 1
 			break
 		}
 	}
 
 	// Sudden death: paired kicks (both teams take, then compare) until decided.
+	// The home taker can't lose it on their own; the away taker must score
+	// whenever home just did.
 	for round := 0; result.HomeScore == result.AwayScore && round < shootoutMaxSuddenDeathRounds; round++ {
 		index := shootoutRegulationKicks + round
-		kick(homeTakers, awayKeeper, TeamTypeHome, index)
-		kick(awayTakers, homeKeeper, TeamTypeAway, index)
+		kick(homeTakers, awayKeeper, TeamTypeHome, index, penaltyPressure{level: tuning.PenaltyPressureSuddenDeath})
+		kick(awayTakers, homeKeeper, TeamTypeAway, index, penaltyPressure{
+			level:     tuning.PenaltyPressureSuddenDeath,
+			mustScore: result.HomeScore > result.AwayScore,
+		})
+		if result.HomeScore != result.AwayScore {
+			result.DecisiveKick = len(result.Kicks) - 1
+		}
 	}
 
 	// Crown the winner. If still level after the sudden-death cap (effectively
@@ -198,14 +392,69 @@ func RunShootoutWithSeed(r *rand.Rand, home, away GameLineup) (ShootoutResult, e
 	return result, nil
 }
 
-// penaltyTakerOrder returns the order in which a lineup's players take penalties:
+// shootoutSquad applies a lineup's ShootoutPlan: the substitute keeper (if
+// any) replaces the lineup's goalkeeper, then the named takers go first and
+// everyone else follows in the default order.
+func shootoutSquad(lineup GameLineup) ([]SelectedPlayer, SelectedPlayer, error) {
+	plan := lineup.Shootout
+	keeper := findShootoutKeeper(lineup)
+	players := lineup.Players
+	var subID string
+	if plan.Keeper != nil {
+		sub := *plan.Keeper
+		if sub.ID == "" {
+			return nil, SelectedPlayer{}, fmt.Errorf("%w: substitute keeper has no ID", ErrInvalidShootoutPlan)
+		}
+		players = make([]SelectedPlayer, len(lineup.Players))
+		for i, p := range lineup.Players {
+			switch {
+			case p.ID == keeper.ID:
+				players[i] = sub
+			case p.ID == sub.ID:
+				return nil, SelectedPlayer{}, fmt.Errorf("%w: substitute keeper %q is already in the lineup", ErrInvalidShootoutPlan, sub.ID)
+			default:
+				players[i] = p
+			}
+		}
+		keeper = sub
+		subID = sub.ID
+	}
+
+	byID := make(map[string]SelectedPlayer, len(players))
+	for _, p := range players {
+		byID[p.ID] = p
+	}
+	takers := make([]SelectedPlayer, 0, len(players))
+	named := make(map[string]bool, len(plan.Order))
+	for _, id := range plan.Order {
+		p, ok := byID[id]
+		if !ok {
+			return nil, SelectedPlayer{}, fmt.Errorf("%w: taker %q is not in the lineup", ErrInvalidShootoutPlan, id)
+		}
+		if named[id] {
+			return nil, SelectedPlayer{}, fmt.Errorf("%w: taker %q is listed twice", ErrInvalidShootoutPlan, id)
+		}
+		named[id] = true
+		takers = append(takers, p)
+	}
+	for _, p := range penaltyTakerOrder(players, subID) {
+		if !named[p.ID] {
+			takers = append(takers, p)
+		}
+	}
+	return takers, keeper, nil
+}
+
+// penaltyTakerOrder returns the default order in which players take penalties:
 // outfield players first (in lineup order), then any goalkeeper last — the
-// conventional ordering, though every player takes a kick.
-func penaltyTakerOrder(lineup GameLineup) []SelectedPlayer {
-	outfield := make([]SelectedPlayer, 0, len(lineup.Players))
+// conventional ordering, though every player takes a kick. keeperID marks a
+// substitute shootout keeper, who goes last even without goalkeeper
+// attributes; empty for none.
+func penaltyTakerOrder(players []SelectedPlayer, keeperID string) []SelectedPlayer {
+	outfield := make([]SelectedPlayer, 0, len(players))
 	var keepers []SelectedPlayer
-	for _, p := range lineup.Players {
-		if p.SelectedPosition == PlayerPositionGoalkeeper || isGoalkeeper(p.Attributes) {
+	for _, p := range players {
+		if p.ID == keeperID || p.SelectedPosition == PlayerPositionGoalkeeper || isGoalkeeper(p.Attributes) {
 			keepers = append(keepers, p)
 			continue
 		}
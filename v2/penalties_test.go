@@ -1,10 +1,13 @@
 package soccer_test
 
 import (
+	"encoding/json"
 	"math/rand"
+	"strings"
 	"testing"
 
 	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -51,6 +54,90 @@ func TestTakePenaltyWithSeed_GoodTakersConvertMoreThanWeakOnes(t *testing.T) {
 	assert.Greater(t, eliteGoals, n/2)
 }
 
+// Penalty saves are a reflex job: a keeper's Reflexes, not Positioning,
+// decide how many spot kicks they keep out.
+func TestTakePenaltyWithSeed_ReflexesSavePenalties(t *testing.T) {
+	taker := player("taker", soccer.PlayerPositionAttack, 10, 20, 70, 80, 70)
+	shotStopper := player("keeper", soccer.PlayerPositionGoalkeeper, 75, 30, 20, 15, 70)
+	shotStopper.Attributes.Reflexes, shotStopper.Attributes.Positioning = 95, 50
+	reader := shotStopper
+	reader.Attributes.Reflexes, reader.Attributes.Positioning = 50, 95
+
+	const n = 4000
+	stopperGoals, readerGoals := 0, 0
+	for i := 0; i < n; i++ {
+		if soccer.TakePenaltyWithSeed(rand.New(rand.NewSource(int64(i))), taker, shotStopper, soccer.TeamTypeHome).IsGoal() {
+			stopperGoals++
+		}
+		if soccer.TakePenaltyWithSeed(rand.New(rand.NewSource(int64(i))), taker, reader, soccer.TeamTypeHome).IsGoal() {
+			readerGoals++
+		}
+	}
+
+	assert.Less(t, stopperGoals, readerGoals, "a high-Reflexes keeper should concede fewer penalties")
+}
+
+func TestTakePenaltyWithSeed_MissTypeMatchesResult(t *testing.T) {
+	taker := player("taker", soccer.PlayerPositionAttack, 10, 20, 60, 70, 70)
+	keeper := player("keeper", soccer.PlayerPositionGoalkeeper, 85, 30, 20, 15, 70)
+	directions := []soccer.PenaltyDirection{soccer.PenaltyDirectionLeft, soccer.PenaltyDirectionMid, soccer.PenaltyDirectionRight}
+
+	missTypes := map[soccer.PenaltyMissType]int{}
+	var rightWaySaves, wrongWaySaves int
+	for i := 0; i < 2000; i++ {
+		out := soccer.TakePenaltyWithSeed(rand.New(rand.NewSource(int64(i))), taker, keeper, soccer.TeamTypeHome)
+		assert.Contains(t, directions, out.Direction)
+		assert.Contains(t, directions, out.KeeperDive)
+		if out.IsGoal() {
+			assert.Equal(t, soccer.PenaltyMissTypeNone, out.MissType)
+			continue
+		}
+		missTypes[out.MissType]++
+		if out.MissType == soccer.PenaltyMissTypeSaved {
+			if out.Direction == out.KeeperDive {
+				rightWaySaves++
+			} else {
+				wrongWaySaves++
+			}
+		}
+	}
+	assert.Greater(t, rightWaySaves, 3*wrongWaySaves, "most saves should come from the keeper guessing right")
+	assert.Positive(t, missTypes[soccer.PenaltyMissTypeSaved], "some penalties should be saved")
+	assert.Positive(t, missTypes[soccer.PenaltyMissTypeOffTarget], "some penalties should miss the target")
+	assert.Zero(t, missTypes[soccer.PenaltyMissTypeNone], "a miss must say how it was missed")
+}
+
+// A taker with a favourite side goes there more often — and a keeper who has
+// their history reads it and saves more.
+func TestTakePenaltyWithHistory_KeeperReadsPredictableTaker(t *testing.T) {
+	taker := player("taker", soccer.PlayerPositionAttack, 10, 20, 70, 80, 70)
+	taker.Attributes.PenaltySide = soccer.PenaltyDirectionLeft
+	keeper := player("keeper", soccer.PlayerPositionGoalkeeper, 80, 30, 20, 15, 70)
+	keeper.Attributes.Positioning = 90
+	history := []soccer.PenaltyDirection{
+		soccer.PenaltyDirectionLeft, soccer.PenaltyDirectionLeft, soccer.PenaltyDirectionRight,
+		soccer.PenaltyDirectionLeft, soccer.PenaltyDirectionLeft,
+	}
+
+	const n = 4000
+	var left, blindGoals, readGoals int
+	for i := 0; i < n; i++ {
+		blind := soccer.TakePenaltyWithSeed(rand.New(rand.NewSource(int64(i))), taker, keeper, soccer.TeamTypeHome)
+		if blind.Direction == soccer.PenaltyDirectionLeft {
+			left++
+		}
+		if blind.IsGoal() {
+			blindGoals++
+		}
+		if soccer.TakePenaltyWithHistory(rand.New(rand.NewSource(int64(i))), taker, keeper, soccer.TeamTypeHome, history).IsGoal() {
+			readGoals++
+		}
+	}
+
+	assert.Greater(t, left, n/2, "a taker with a favourite side should go there most of the time")
+	assert.Less(t, readGoals, blindGoals, "a keeper with the taker's history should save more")
+}
+
 func TestRunShootoutWithSeed(t *testing.T) {
 	home := strongLineup(soccer.FormationTypeDiamond)
 	home.Team.ID = "home"
@@ -146,3 +233,135 @@ func TestRunShootoutWithSeed(t *testing.T) {
 		}
 	})
 }
+
+// In an eleven-a-side shootout every outfield player takes a kick before
+// anyone takes a second, and the keeper goes last.
+func TestRunShootoutWithSeed_ElevenASideRotation(t *testing.T) {
+	home := testdata.StrongTeam(soccer.FormationTypeClassic)
+	away := testdata.WeakTeam(soccer.FormationTypeClassic)
+	keeper := home.Players[0].ID
+
+	for seed := int64(0); seed < 200; seed++ {
+		res, err := soccer.RunShootoutWithSeed(rand.New(rand.NewSource(seed)), home, away)
+		require.NoError(t, err)
+		var order []string
+		for _, kick := range res.Kicks {
+			if kick.TeamType == soccer.TeamTypeHome {
+				order = append(order, kick.TakerID)
+			}
+		}
+		for i, id := range order {
+			if i < len(home.Players)-1 {
+				assert.NotEqual(t, keeper, id, "seed %d: keeper took kick %d before the outfield players", seed, i+1)
+			}
+			assert.Equal(t, order[i%len(home.Players)], id, "seed %d: kick %d repeats a taker early", seed, i+1)
+		}
+	}
+}
+
+func TestRunShootoutWithSeed_ShootoutPlan(t *testing.T) {
+	home := testdata.StrongTeam(soccer.FormationTypeDiamond) // IDs 1..5, keeper "1"
+	away := testdata.WeakTeam(soccer.FormationTypeDiamond)   // IDs 6..10, keeper "6"
+
+	t.Run("named takers go first, the rest follow in default order", func(t *testing.T) {
+		planned := home
+		planned.Shootout = soccer.ShootoutPlan{Order: []string{"5", "1"}}
+		res, err := soccer.RunShootoutWithSeed(rand.New(rand.NewSource(3)), planned, away)
+		require.NoError(t, err)
+
+		want := []string{"5", "1", "2", "3", "4"}
+		var i int
+		for _, kick := range res.Kicks {
+			if kick.TeamType == soccer.TeamTypeHome {
+				assert.Equal(t, want[i%len(want)], kick.TakerID, "home kick %d", i+1)
+				i++
+			}
+		}
+	})
+
+	t.Run("substitute keeper faces every kick and takes the keeper's turn", func(t *testing.T) {
+		sub := player("sub-gk", soccer.PlayerPositionGoalkeeper, 95, 10, 10, 10, 70)
+		planned := home
+		planned.Shootout = soccer.ShootoutPlan{Keeper: &sub}
+		for seed := int64(0); seed < 50; seed++ {
+			res, err := soccer.RunShootoutWithSeed(rand.New(rand.NewSource(seed)), planned, away)
+			require.NoError(t, err)
+			for _, kick := range res.Kicks {
+				if kick.TeamType == soccer.TeamTypeAway {
+					assert.Equal(t, "sub-gk", kick.KeeperID)
+				} else {
+					assert.NotEqual(t, "1", kick.TakerID, "the substituted keeper is off the pitch")
+				}
+			}
+		}
+	})
+
+	t.Run("rejects invalid plans", func(t *testing.T) {
+		clash := player("2", soccer.PlayerPositionGoalkeeper, 95, 10, 10, 10, 70)
+		noID := player("", soccer.PlayerPositionGoalkeeper, 95, 10, 10, 10, 70)
+		tests := map[string]soccer.ShootoutPlan{
+			"unknown taker":          {Order: []string{"99"}},
+			"repeated taker":         {Order: []string{"2", "2"}},
+			"away player as taker":   {Order: []string{"7"}},
+			"keeper without ID":      {Keeper: &noID},
+			"keeper already playing": {Keeper: &clash},
+		}
+		for name, plan := range tests {
+			t.Run(name, func(t *testing.T) {
+				planned := home
+				planned.Shootout = plan
+				_, err := soccer.RunShootoutWithSeed(rand.New(rand.NewSource(1)), planned, away)
+				assert.ErrorIs(t, err, soccer.ErrInvalidShootoutPlan)
+			})
+		}
+	})
+}
+
+// A lineup without a shootout plan leaves it out of its JSON; one with a
+// plan carries it.
+func TestGameLineup_ShootoutPlanJSON(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	body, err := json.Marshal(lineup)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), `"shootout"`)
+
+	lineup.Shootout = soccer.ShootoutPlan{Order: []string{"5", "4"}}
+	body, err = json.Marshal(lineup)
+	require.NoError(t, err)
+	var decoded soccer.GameLineup
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, lineup.Shootout, decoded.Shootout)
+
+	// An empty order is no plan: it decodes, then drops out again.
+	body, err = json.Marshal(testdata.StrongTeam(soccer.FormationTypeDiamond))
+	require.NoError(t, err)
+	withEmpty := strings.Replace(string(body), `"players":`, `"shootout":{"order":[]},"players":`, 1)
+	require.Contains(t, withEmpty, `"order":[]`)
+	decoded = soccer.GameLineup{}
+	require.NoError(t, json.Unmarshal([]byte(withEmpty), &decoded))
+	assert.NotNil(t, decoded.Shootout.Order)
+	assert.True(t, decoded.Shootout.IsZero())
+	again, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.NotContains(t, string(again), `"shootout"`)
+}
+
+// The decisive kick is the one that settled it: before it the shootout was
+// still open, after it the winner is known.
+func TestRunShootoutWithSeed_MarksDecisiveKick(t *testing.T) {
+	home := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	away := testdata.StrongTeam(soccer.FormationTypeDiamond)
+
+	for seed := int64(0); seed < 300; seed++ {
+		res, err := soccer.RunShootoutWithSeed(rand.New(rand.NewSource(seed)), home, away)
+		require.NoError(t, err)
+		require.Equal(t, len(res.Kicks)-1, res.DecisiveKick, "seed %d: the shootout stops on the decisive kick", seed)
+
+		decisive := res.Kicks[res.DecisiveKick]
+		if decisive.IsGoal() {
+			assert.Equal(t, res.Winner, decisive.TeamType, "seed %d: a decisive goal wins it", seed)
+		} else {
+			assert.NotEqual(t, res.Winner, decisive.TeamType, "seed %d: a decisive miss loses it", seed)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package soccer
 
-import "slices"
+import (
+	"slices"
+
+	"github.com/stein-f/oink-soccer-common/v2/internal/tuning"
+)
 
 // PlayerAttributes is the canonical bundle of ratings for a player.
 //
@@ -48,6 +52,25 @@ type PlayerAttributes struct {
 	Technique int `json:"technique,omitempty"`
 	Composure int `json:"composure,omitempty"`
 	Tackling  int `json:"tackling,omitempty"`
+
+	// Goalkeeper specialist attributes. Like the outfield specialists they
+	// are optional; when zero, the Effective* accessors fall back to
+	// GoalkeeperRating so legacy keepers keep a single, uniform rating.
+	//
+	// Mapping to FIFA columns (used by the allocation pipeline):
+	//   Reflexes     → avg(goalkeeping_reflexes, goalkeeping_diving)
+	//   Handling     → goalkeeping_handling
+	//   Positioning  → goalkeeping_positioning
+	//   Distribution → goalkeeping_kicking
+	Reflexes     int `json:"reflexes,omitempty"`
+	Handling     int `json:"handling,omitempty"`
+	Positioning  int `json:"positioning,omitempty"`
+	Distribution int `json:"distribution,omitempty"`
+
+	// PenaltySide is an optional penalty habit: the side a taker favours,
+	// or the way a keeper prefers to dive. Empty means no habit. See
+	// TakePenaltyWithHistory.
+	PenaltySide PenaltyDirection `json:"penalty_side,omitempty"`
 }
 
 // EffectiveWorkRate returns WorkRate if set, otherwise SpeedRating.
@@ -107,6 +130,127 @@ func (p PlayerAttributes) EffectiveTackling() int {
 	return p.DefenseRating
 }
 
+// EffectiveReflexes returns Reflexes if set, otherwise GoalkeeperRating.
+// Reflexes drive close-range saves: open play, crosses, corners, penalties
+// and 1-on-1 breakaways.
+func (p PlayerAttributes) EffectiveReflexes() int {
+	if p.Reflexes > 0 {
+		return p.Reflexes
+	}
+	return p.GoalkeeperRating
+}
+
+// EffectiveHandling returns Handling if set, otherwise the neutral rebound
+// rating. Handling decides how often the keeper spills a save into a
+// rebound. Like Distribution it doesn't fall back to GoalkeeperRating: a
+// keeper without it concedes the goals they always did.
+func (p PlayerAttributes) EffectiveHandling() int {
+	if p.Handling > 0 {
+		return p.Handling
+	}
+	return tuning.ReboundHandlingNeutral
+}
+
+// EffectivePositioning returns Positioning if set, otherwise
+// GoalkeeperRating. Positioning drives long-range and free-kick saves,
+// where the keeper has time to set but has to be in the right place.
+func (p PlayerAttributes) EffectivePositioning() int {
+	if p.Positioning > 0 {
+		return p.Positioning
+	}
+	return p.GoalkeeperRating
+}
+
+// EffectiveDistribution returns Distribution if set, otherwise the neutral
+// rating of the lineup chance mix. Distribution is the keeper's attacking
+// contribution: a quick, accurate release springs the team's breakaways.
+// Unlike the other keeper attributes it doesn't fall back to
+// GoalkeeperRating, so a keeper without it leaves the chance mix as it was.
+func (p PlayerAttributes) EffectiveDistribution() int {
+	if p.Distribution > 0 {
+		return p.Distribution
+	}
+	return tuning.ChanceMixLineupNeutral
+}
+
+// Effective returns the rating the engine reads for a: the field itself,
+// or for a specialist left at zero, the rating it falls back to. Unknown
+// attributes are zero.
+func (p PlayerAttributes) Effective(a Attribute) int {
+	switch a {
+	case AttributeGoalkeeper:
+		return p.GoalkeeperRating
+	case AttributeDefense:
+		return p.DefenseRating
+	case AttributeSpeed:
+		return p.SpeedRating
+	case AttributeControl:
+		return p.ControlRating
+	case AttributeAttack:
+		return p.AttackRating
+	case AttributeWorkRate:
+		return p.EffectiveWorkRate()
+	case AttributeFinishing:
+		return p.EffectiveFinishing()
+	case AttributeHeading:
+		return p.EffectiveHeading()
+	case AttributeTechnique:
+		return p.EffectiveTechnique()
+	case AttributeComposure:
+		return p.EffectiveComposure()
+	case AttributeTackling:
+		return p.EffectiveTackling()
+	case AttributeReflexes:
+		return p.EffectiveReflexes()
+	case AttributeHandling:
+		return p.EffectiveHandling()
+	case AttributePositioning:
+		return p.EffectivePositioning()
+	case AttributeDistribution:
+		return p.EffectiveDistribution()
+	}
+	return 0
+}
+
+// WithAttribute returns p with a set to v. Setting a specialist pins it, so
+// it no longer follows the rating it falls back to. Unknown attributes
+// leave p unchanged.
+func (p PlayerAttributes) WithAttribute(a Attribute, v int) PlayerAttributes {
+	switch a {
+	case AttributeGoalkeeper:
+		p.GoalkeeperRating = v
+	case AttributeDefense:
+		p.DefenseRating = v
+	case AttributeSpeed:
+		p.SpeedRating = v
+	case AttributeControl:
+		p.ControlRating = v
+	case AttributeAttack:
+		p.AttackRating = v
+	case AttributeWorkRate:
+		p.WorkRate = v
+	case AttributeFinishing:
+		p.Finishing = v
+	case AttributeHeading:
+		p.Heading = v
+	case AttributeTechnique:
+		p.Technique = v
+	case AttributeComposure:
+		p.Composure = v
+	case AttributeTackling:
+		p.Tackling = v
+	case AttributeReflexes:
+		p.Reflexes = v
+	case AttributeHandling:
+		p.Handling = v
+	case AttributePositioning:
+		p.Positioning = v
+	case AttributeDistribution:
+		p.Distribution = v
+	}
+	return p
+}
+
 // IsInjuryProne reports whether a player carries the InjuryProne tag.
 func (p PlayerAttributes) IsInjuryProne() bool {
 	for _, t := range p.Tag {
@@ -126,6 +270,18 @@ type SelectedPlayer struct {
 	// Role is optional. The zero value (PlayerRoleNone) means no special
 	// contribution — the engine treats this player the same as any other.
 	Role PlayerRole `json:"role,omitempty"`
+	// InjuryRisk multiplies the player's odds of picking up an injury this
+	// match, on top of the InjuryProne tag (fitness.Prepare raises it for
+	// players just back from injury). Zero means 1.0.
+	InjuryRisk float64 `json:"injury_risk,omitempty"`
+}
+
+// injuryRiskFactor returns InjuryRisk, or 1.0 when it is unset.
+func (p SelectedPlayer) injuryRiskFactor() float64 {
+	if p.InjuryRisk <= 0 {
+		return 1.0
+	}
+	return p.InjuryRisk
 }
 
 // IsOutOfPosition reports whether SelectedPosition isn't one the player can
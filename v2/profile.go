@@ -0,0 +1,147 @@
+package soccer
+
+import "github.com/stein-f/oink-soccer-common/v2/internal/tuning"
+
+// TeamProfile is a lineup's strength the way the engine sees it at
+// kick-off: after the formation profile, tactics, roles, out-of-position
+// penalties, injuries and the captain, but before item boosts (rolled per
+// match), anything the opponent brings (their press, line, width and
+// man-marking) and late-game press fatigue.
+//
+// AnalyzeLineup reads these numbers from the same code the engine scores a
+// match with, so they can't drift apart.
+type TeamProfile struct {
+	TeamID    string        `json:"team_id"`
+	Formation FormationType `json:"formation"`
+	// Control decides possession: each chance goes to a team in
+	// proportion to its control.
+	Control float64 `json:"control"`
+	// Defense is what an attacker's score is weighed against on a chance of
+	// each type; the chance is scored with probability attack / (attack +
+	// defense).
+	Defense map[ChanceType]float64 `json:"defense"`
+	// Attack is the expected attack on a chance of each type, over who it
+	// is likely to fall to.
+	Attack map[ChanceType]float64 `json:"attack"`
+	// ChanceMix is the share of the team's chances of each type.
+	ChanceMix map[ChanceType]float64 `json:"chance_mix"`
+	// CaptainBoost is the captain's multiplier on the team's control and
+	// defense, 1 without a captain.
+	CaptainBoost float64              `json:"captain_boost"`
+	Players      []PlayerContribution `json:"players"`
+}
+
+// PlayerContribution is one player's part in a TeamProfile.
+type PlayerContribution struct {
+	PlayerID      string         `json:"player_id"`
+	Position      PlayerPosition `json:"position"` // as selected
+	Role          PlayerRole     `json:"role,omitempty"`
+	OutOfPosition bool           `json:"out_of_position"`
+	Injured       bool           `json:"injured"`
+	// Control is the player's own control score; ControlShare is the part
+	// of the team's control it accounts for, 0 to 1.
+	Control      float64 `json:"control"`
+	ControlShare float64 `json:"control_share"`
+	// Defense and DefenseShare are the same for open-play defense.
+	Defense      float64 `json:"defense"`
+	DefenseShare float64 `json:"defense_share"`
+	// Attack is the player's effective attack when a chance of each type
+	// falls to them; ChanceShare is how likely it is to.
+	Attack      map[ChanceType]float64 `json:"attack"`
+	ChanceShare map[ChanceType]float64 `json:"chance_share"`
+}
+
+// AnalyzeLineup returns the lineup's TeamProfile. It checks the lineup the
+// way RunGameWithSeed does: an unregistered formation is ErrUnknownFormation
+// and a lineup that doesn't fill its formation is ErrLineupSize.
+func AnalyzeLineup(lineup GameLineup) (TeamProfile, error) {
+	if _, err := validateLineup(lineup); err != nil {
+		return TeamProfile{}, err
+	}
+	tactics := lineup.Team.Tactics
+	side := newMatchSide(lineup, GameLineup{}, tuning.LookupTeamSizeScaling(len(lineup.Players)))
+	side.controlBoost, side.defenseBoost = 1, 1
+	side.rescore()
+
+	profile := TeamProfile{
+		TeamID:       lineup.Team.ID,
+		Formation:    lineup.Team.Formation,
+		Control:      side.control,
+		Defense:      make(map[ChanceType]float64, len(chanceTypeOrder)),
+		Attack:       make(map[ChanceType]float64, len(chanceTypeOrder)),
+		ChanceMix:    make(map[ChanceType]float64, len(chanceTypeOrder)),
+		CaptainBoost: captainBoost(lineup),
+		Players:      make([]PlayerContribution, len(lineup.Players)),
+	}
+
+	controlShares := rolePositionShares(lineup.Players, tuning.ControlPositionWeights, controlScore(tactics))
+	defenseShares := rolePositionShares(lineup.Players, tuning.DefensePositionWeights, defenseScore(tactics, ChanceTypeOpenPlay, marking{}))
+	for i, p := range lineup.Players {
+		profile.Players[i] = PlayerContribution{
+			PlayerID:      p.ID,
+			Position:      p.SelectedPosition,
+			Role:          p.Role,
+			OutOfPosition: p.IsOutOfPosition(),
+			Injured:       p.Injury != nil,
+			Control:       playerControl(p, tactics),
+			ControlShare:  controlShares[i],
+			Defense:       playerDefenseForChance(p, tactics, ChanceTypeOpenPlay),
+			DefenseShare:  defenseShares[i],
+			Attack:        make(map[ChanceType]float64, len(chanceTypeOrder)),
+			ChanceShare:   make(map[ChanceType]float64, len(chanceTypeOrder)),
+		}
+	}
+
+	var totalWeight float64
+	for _, w := range side.chanceWeights {
+		totalWeight += w
+	}
+	for i, ct := range chanceTypeOrder {
+		profile.Defense[ct] = side.defense[ct] * chanceTypeDefenseScale(ct)
+		if totalWeight > 0 {
+			profile.ChanceMix[ct] = side.chanceWeights[i] / totalWeight
+		}
+
+		attackFactor := cornerDeliveryFactor(lineup, ct, tactics)
+		shares := attackerShares(lineup, ct, tactics)
+		for j, p := range lineup.Players {
+			c := &profile.Players[j]
+			c.Attack[ct] = chanceAttack(p, ct, side.profile, tactics, attackFactor, 1)
+			if share, ok := shares[p.ID]; ok {
+				c.ChanceShare[ct] = share
+				profile.Attack[ct] += share * c.Attack[ct]
+			}
+		}
+	}
+	return profile, nil
+}
+
+// attackerShares is pickAttackerWithTactics as probabilities: how likely a
+// chance of the given type is to fall to each player, with no man-marking.
+func attackerShares(lineup GameLineup, ct ChanceType, tactics Tactics) map[string]float64 {
+	if tactics.SetPieceTaker != "" && isSetPieceChance(ct) && hasPlayer(lineup, tactics.SetPieceTaker) {
+		return map[string]float64{tactics.SetPieceTaker: 1}
+	}
+	excludeID := ""
+	if ct == ChanceTypeCorner {
+		excludeID = tactics.SetPieceTaker
+	}
+	players, weights, total := attackerPool(lineup, ct, excludeID, marking{})
+	shares := make(map[string]float64, len(players))
+	if total == 0 {
+		// pickAttacker's fallback: the first player by ID not excluded.
+		for _, p := range players {
+			if excludeID == "" || p.ID != excludeID {
+				shares[p.ID] = 1
+				break
+			}
+		}
+		return shares
+	}
+	for i, p := range players {
+		if weights[i] > 0 {
+			shares[p.ID] = weights[i] / total
+		}
+	}
+	return shares
+}
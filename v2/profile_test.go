@@ -0,0 +1,145 @@
+package soccer_test
+
+import (
+	"math/rand"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func analyze(t *testing.T, lineup soccer.GameLineup) soccer.TeamProfile {
+	t.Helper()
+	profile, err := soccer.AnalyzeLineup(lineup)
+	require.NoError(t, err)
+	return profile
+}
+
+// The profile is the engine's own numbers: until someone is injured, every
+// chance's xG is the taker's Attack against the other side's Defense.
+func TestAnalyzeLineup_MatchesTheEngine(t *testing.T) {
+	home := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	away := testdata.WeakTeam(soccer.FormationTypeBox)
+	away.Team.Tactics = soccer.Tactics{Tempo: soccer.TempoLevelFast, PassingStyle: soccer.PassingStyleDirect}
+	profiles := map[soccer.TeamType]soccer.TeamProfile{
+		soccer.TeamTypeHome: analyze(t, home),
+		soccer.TeamTypeAway: analyze(t, away),
+	}
+	attack := func(p soccer.TeamProfile, id string, ct soccer.ChanceType) float64 {
+		for _, c := range p.Players {
+			if c.PlayerID == id {
+				return c.Attack[ct]
+			}
+		}
+		t.Fatalf("no player %s", id)
+		return 0
+	}
+
+	var checked int
+	for seed := int64(0); seed < 50; seed++ {
+		events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(seed)), home, away)
+		require.NoError(t, err)
+		for _, e := range events {
+			if e.IsInjury() {
+				break
+			}
+			var id string
+			var team soccer.TeamType
+			if e.Type == soccer.GameEventTypeGoal {
+				id, team = e.GetGoalEvent().PlayerID, e.GetGoalEvent().TeamType
+			} else {
+				id, team = e.GetMissEvent().PlayerID, e.GetMissEvent().TeamType
+			}
+			other := soccer.TeamTypeAway
+			if team == soccer.TeamTypeAway {
+				other = soccer.TeamTypeHome
+			}
+			atk := max(attack(profiles[team], id, e.ChanceType), 1)
+			def := max(profiles[other].Defense[e.ChanceType], 1)
+			assert.InDelta(t, atk/(atk+def), e.XG, 1e-12, "seed %d minute %d", seed, e.Minute)
+			checked++
+		}
+	}
+	assert.Greater(t, checked, 100)
+}
+
+func TestAnalyzeLineup_SharesAddUp(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypePyramid)
+	profile := analyze(t, lineup)
+
+	require.Len(t, profile.Players, len(lineup.Players))
+	var control, defense float64
+	chances := map[soccer.ChanceType]float64{}
+	for _, c := range profile.Players {
+		control += c.ControlShare
+		defense += c.DefenseShare
+		for ct, share := range c.ChanceShare {
+			chances[ct] += share
+		}
+	}
+	assert.InDelta(t, 1, control, 1e-9)
+	assert.InDelta(t, 1, defense, 1e-9)
+	var mix float64
+	for ct, share := range profile.ChanceMix {
+		mix += share
+		assert.InDelta(t, 1, chances[ct], 1e-9, "%s", ct)
+		assert.Positive(t, profile.Attack[ct], "%s", ct)
+		assert.Positive(t, profile.Defense[ct], "%s", ct)
+	}
+	assert.InDelta(t, 1, mix, 1e-9)
+	assert.Equal(t, 1.0, profile.CaptainBoost)
+}
+
+func TestAnalyzeLineup_ReflectsRolesInjuriesAndPositions(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	base := analyze(t, lineup)
+
+	var striker, midfielder int
+	for i, p := range lineup.Players {
+		switch p.SelectedPosition {
+		case soccer.PlayerPositionAttack:
+			striker = i
+		case soccer.PlayerPositionMidfield:
+			midfielder = i
+		}
+	}
+
+	captained := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	captained.Players[midfielder].Role = soccer.PlayerRoleCaptain
+	assert.NotEqual(t, 1.0, analyze(t, captained).CaptainBoost)
+
+	injured := soccer.ApplyInjuries(lineup, []soccer.InjuryEvent{{PlayerID: lineup.Players[striker].ID, Injury: soccer.Injury{StatsReduction: 0.5}}})
+	hurt := analyze(t, injured)
+	assert.True(t, hurt.Players[striker].Injured)
+	assert.Less(t, hurt.Players[striker].Attack[soccer.ChanceTypeOpenPlay], base.Players[striker].Attack[soccer.ChanceTypeOpenPlay])
+	assert.Less(t, hurt.Attack[soccer.ChanceTypeOpenPlay], base.Attack[soccer.ChanceTypeOpenPlay])
+
+	moved := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	moved.Players[striker].Attributes.Positions = []soccer.PlayerPosition{soccer.PlayerPositionDefense}
+	moved.Players[striker].Attributes.PrimaryPosition = soccer.PlayerPositionDefense
+	shifted := analyze(t, moved)
+	assert.True(t, shifted.Players[striker].OutOfPosition)
+	assert.Less(t, shifted.Players[striker].Control, base.Players[striker].Control)
+
+	taker := lineup.Players[midfielder].ID
+	withTaker := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	withTaker.Team.Tactics.SetPieceTaker = taker
+	set := analyze(t, withTaker)
+	assert.Equal(t, 1.0, set.Players[midfielder].ChanceShare[soccer.ChanceTypePenalty], "the taker takes the penalties")
+	assert.Zero(t, set.Players[midfielder].ChanceShare[soccer.ChanceTypeCorner], "and delivers the corners")
+}
+
+// A lineup the engine would refuse to play can't be analyzed either.
+func TestAnalyzeLineup_ValidatesLikeTheEngine(t *testing.T) {
+	unknown := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	unknown.Team.Formation = "4-4-2"
+	_, err := soccer.AnalyzeLineup(unknown)
+	assert.ErrorIs(t, err, soccer.ErrUnknownFormation)
+
+	short := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	short.Players = short.Players[:len(short.Players)-1]
+	_, err = soccer.AnalyzeLineup(short)
+	assert.ErrorIs(t, err, soccer.ErrLineupSize)
+}
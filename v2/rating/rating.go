@@ -0,0 +1,219 @@
+// Package rating keeps an Elo-style strength rating per team, updated from
+// match results. It is built for matchmaking, power rankings and spotting
+// teams whose results fall well short of their lineup (sandbagging).
+//
+//   - The expected score of a match is the usual logistic curve on the
+//     rating gap, with an optional home-advantage term added to the home
+//     side.
+//   - After each match the home side gains K × margin × (result − expected)
+//     and the away side loses the same, so ratings are zero-sum. The margin
+//     weights wins by goal difference: ×1 up to one goal, ×1.5 for two, and
+//     (11 + n) / 8 for n ≥ 3.
+//   - A new team starts at Rules.Initial, or from its lineup's intrinsic
+//     strength (soccer.LineupStrength) if it is seeded first.
+//
+// Everything is a pure function of the order results are recorded in; the
+// Table round-trips through JSON.
+package rating
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+)
+
+var ErrSameTeam = errors.New("rating: a team can't play itself")
+
+// Rules are the dials of the rating system.
+type Rules struct {
+	// Initial is the rating of a team with no history that wasn't seeded.
+	Initial float64 `json:"initial"`
+	// K is the most a single one-goal result can move a rating.
+	K float64 `json:"k"`
+	// Scale is the rating gap at which the stronger side's expected score
+	// is 10 to 1.
+	Scale float64 `json:"scale"`
+	// HomeAdvantage is added to the home side's rating when working out
+	// the expected score. The engine has no home edge, so it is 0 by
+	// default.
+	HomeAdvantage float64 `json:"home_advantage"`
+	// GoalDifference turns on the margin weighting.
+	GoalDifference bool `json:"goal_difference"`
+	// StrengthScale and StrengthReference convert a lineup's overall
+	// strength into a rating: Initial + StrengthScale × (overall −
+	// StrengthReference).
+	StrengthScale     float64 `json:"strength_scale"`
+	StrengthReference float64 `json:"strength_reference"`
+}
+
+// DefaultRules returns the rules used by the live game. The strength
+// conversion is calibrated on the engine: the test fixtures' strong and
+// weak sides are about 520 points apart, and the strong side takes about
+// 95% of the points between them.
+func DefaultRules() Rules {
+	return Rules{
+		Initial:           1500,
+		K:                 24,
+		Scale:             400,
+		GoalDifference:    true,
+		StrengthScale:     20,
+		StrengthReference: 25,
+	}
+}
+
+// FromStrength returns the rating a lineup of the given strength starts at.
+func (r Rules) FromStrength(s soccer.Strength) float64 {
+	return r.Initial + r.StrengthScale*(s.Overall()-r.StrengthReference)
+}
+
+// Expected returns the home side's expected score, 0 to 1, between teams
+// with the given ratings.
+func (r Rules) Expected(home, away float64) float64 {
+	return 1 / (1 + math.Pow(10, (away-home-r.HomeAdvantage)/r.Scale))
+}
+
+// margin is the goal-difference weight of a result.
+func (r Rules) margin(goalDifference int) float64 {
+	n := goalDifference
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case !r.GoalDifference || n <= 1:
+		return 1
+	case n == 2:
+		return 1.5
+	default:
+		return float64(11+n) / 8
+	}
+}
+
+// Table is every team's rating and the history of changes.
+type Table struct {
+	Rules   Rules            `json:"rules"`
+	Teams   map[string]Entry `json:"teams"`
+	History []Change         `json:"history"`
+}
+
+// Entry is one team's rating.
+type Entry struct {
+	Rating float64 `json:"rating"`
+	Played int     `json:"played"`
+}
+
+// Change is one match's effect on the two teams' ratings.
+type Change struct {
+	MatchID    string  `json:"match_id"`
+	Home       string  `json:"home"`
+	Away       string  `json:"away"`
+	HomeGoals  int     `json:"home_goals"`
+	AwayGoals  int     `json:"away_goals"`
+	HomeBefore float64 `json:"home_before"`
+	AwayBefore float64 `json:"away_before"`
+	// Expected is the home side's expected score, 0 to 1.
+	Expected float64 `json:"expected"`
+	// Delta is what the home side gained and the away side lost.
+	Delta     float64 `json:"delta"`
+	HomeAfter float64 `json:"home_after"`
+	AwayAfter float64 `json:"away_after"`
+}
+
+// New returns an empty table.
+func New(rules Rules) *Table {
+	return &Table{Rules: rules, Teams: make(map[string]Entry)}
+}
+
+// Rating returns a team's rating, Rules.Initial for a team not in the
+// table.
+func (t *Table) Rating(teamID string) float64 {
+	if e, ok := t.Teams[teamID]; ok {
+		return e.Rating
+	}
+	return t.Rules.Initial
+}
+
+// Seed starts a team that isn't in the table yet at the rating its lineup's
+// strength is worth, and reports whether it did. A team already in the
+// table keeps the rating its results have earned.
+func (t *Table) Seed(teamID string, lineup soccer.GameLineup) bool {
+	if _, ok := t.Teams[teamID]; ok {
+		return false
+	}
+	t.Teams[teamID] = Entry{Rating: t.Rules.FromStrength(soccer.LineupStrength(lineup))}
+	return true
+}
+
+// Expected returns the home side's expected score against away, 0 to 1: a
+// win counts 1 and a draw ½.
+func (t *Table) Expected(home, away string) float64 {
+	return t.Rules.Expected(t.Rating(home), t.Rating(away))
+}
+
+// Record updates both teams' ratings from a match's stats (see
+// soccer.CreateGameStats) and appends the change to the history.
+func (t *Table) Record(matchID, home, away string, stats soccer.GameStats) (Change, error) {
+	if home == away {
+		return Change{}, fmt.Errorf("%w: %q", ErrSameTeam, home)
+	}
+	c := Change{
+		MatchID:    matchID,
+		Home:       home,
+		Away:       away,
+		HomeGoals:  stats.HomeTeamStats.Goals,
+		AwayGoals:  stats.AwayTeamStats.Goals,
+		HomeBefore: t.Rating(home),
+		AwayBefore: t.Rating(away),
+	}
+	c.Expected = t.Rules.Expected(c.HomeBefore, c.AwayBefore)
+
+	var result float64
+	switch stats.Outcome(soccer.TeamTypeHome) {
+	case soccer.GameOutcomeTypeWon:
+		result = 1
+	case soccer.GameOutcomeTypeDrawn:
+		result = 0.5
+	}
+	c.Delta = t.Rules.K * t.Rules.margin(c.HomeGoals-c.AwayGoals) * (result - c.Expected)
+	c.HomeAfter, c.AwayAfter = c.HomeBefore+c.Delta, c.AwayBefore-c.Delta
+
+	t.Teams[home] = Entry{Rating: c.HomeAfter, Played: t.Teams[home].Played + 1}
+	t.Teams[away] = Entry{Rating: c.AwayAfter, Played: t.Teams[away].Played + 1}
+	t.History = append(t.History, c)
+	return c, nil
+}
+
+// Ranking is a team's place in the power rankings.
+type Ranking struct {
+	Position int     `json:"position"`
+	TeamID   string  `json:"team_id"`
+	Rating   float64 `json:"rating"`
+	Played   int     `json:"played"`
+}
+
+// Rankings returns every team in the table, highest rated first. Equal
+// ratings are ordered by team ID.
+func (t *Table) Rankings() []Ranking {
+	out := make([]Ranking, 0, len(t.Teams))
+	for id, e := range t.Teams {
+		out = append(out, Ranking{TeamID: id, Rating: e.Rating, Played: e.Played})
+	}
+	slices.SortFunc(out, func(a, b Ranking) int {
+		return cmp.Or(cmp.Compare(b.Rating, a.Rating), cmp.Compare(a.TeamID, b.TeamID))
+	})
+	for i := range out {
+		out[i].Position = i + 1
+	}
+	return out
+}
+
+// Gap returns how far a team's rating sits from what its lineup is worth
+// (Rating − Rules.FromStrength). A large negative gap after a run of
+// matches means the team is losing more than its players should: a
+// candidate for sandbagging, or a lineup that doesn't suit its tactics.
+func (t *Table) Gap(teamID string, lineup soccer.GameLineup) float64 {
+	return t.Rating(teamID) - t.Rules.FromStrength(soccer.LineupStrength(lineup))
+}
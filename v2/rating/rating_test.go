@@ -0,0 +1,140 @@
+package rating_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/rating"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func score(home, away int) soccer.GameStats {
+	return soccer.GameStats{
+		HomeTeamStats: soccer.TeamStats{TeamType: soccer.TeamTypeHome, Goals: home},
+		AwayTeamStats: soccer.TeamStats{TeamType: soccer.TeamTypeAway, Goals: away},
+	}
+}
+
+func TestRecord_IsZeroSumAndRewardsUpsets(t *testing.T) {
+	table := rating.New(rating.DefaultRules())
+	table.Teams["fav"] = rating.Entry{Rating: 1700}
+	table.Teams["dog"] = rating.Entry{Rating: 1400}
+
+	c, err := table.Record("m1", "dog", "fav", score(1, 0))
+	require.NoError(t, err)
+	assert.InDelta(t, 0.151, c.Expected, 0.001)
+	assert.InDelta(t, 24*(1-c.Expected), c.Delta, 1e-9)
+	assert.Equal(t, 1400+c.Delta, table.Rating("dog"))
+	assert.Equal(t, 1700-c.Delta, table.Rating("fav"))
+	assert.Equal(t, c.HomeAfter, table.Rating("dog"))
+
+	expected := table.Expected("fav", "dog")
+	c, err = table.Record("m2", "fav", "dog", score(1, 0))
+	require.NoError(t, err)
+	assert.InDelta(t, 24*(1-expected), c.Delta, 1e-9)
+	assert.Less(t, c.Delta, 24*(1-0.151), "the favourite's win is worth less than the upset")
+
+	c, err = table.Record("m3", "fav", "dog", score(2, 2))
+	require.NoError(t, err)
+	assert.Negative(t, c.Delta, "a draw costs the favourite")
+
+	assert.Len(t, table.History, 3)
+	assert.Equal(t, 3, table.Teams["fav"].Played)
+	assert.InDelta(t, 3100, table.Rating("fav")+table.Rating("dog"), 1e-9)
+}
+
+func TestRecord_GoalDifferenceWeighting(t *testing.T) {
+	deltas := map[int]float64{}
+	for _, goals := range []int{1, 2, 3, 5} {
+		table := rating.New(rating.DefaultRules())
+		c, err := table.Record("m", "a", "b", score(goals, 0))
+		require.NoError(t, err)
+		deltas[goals] = c.Delta
+	}
+	assert.InDelta(t, 12, deltas[1], 1e-9)
+	assert.InDelta(t, 18, deltas[2], 1e-9)
+	assert.InDelta(t, 12*14.0/8, deltas[3], 1e-9)
+	assert.InDelta(t, 12*16.0/8, deltas[5], 1e-9)
+
+	rules := rating.DefaultRules()
+	rules.GoalDifference = false
+	table := rating.New(rules)
+	c, err := table.Record("m", "a", "b", score(5, 0))
+	require.NoError(t, err)
+	assert.InDelta(t, 12, c.Delta, 1e-9)
+}
+
+func TestExpected_HomeAdvantage(t *testing.T) {
+	table := rating.New(rating.DefaultRules())
+	assert.Equal(t, 0.5, table.Expected("a", "b"))
+
+	rules := rating.DefaultRules()
+	rules.HomeAdvantage = 60
+	table = rating.New(rules)
+	assert.Greater(t, table.Expected("a", "b"), 0.5)
+	table.Teams["a"] = rating.Entry{Rating: 1440}
+	assert.InDelta(t, 0.5, table.Expected("a", "b"), 1e-9, "home advantage is worth its points in rating")
+}
+
+// Seeding from lineup strength should predict the engine: the strong
+// fixture's expected score against the weak one is close to what it
+// actually takes off it.
+func TestSeed_FromLineupStrengthMatchesTheEngine(t *testing.T) {
+	strong, weak := testdata.StrongTeam(soccer.FormationTypeDiamond), testdata.WeakTeam(soccer.FormationTypeDiamond)
+	table := rating.New(rating.DefaultRules())
+	assert.True(t, table.Seed("strong", strong))
+	assert.True(t, table.Seed("weak", weak))
+	assert.Greater(t, table.Rating("strong"), table.Rating("weak"))
+
+	var points float64
+	const matches = 500
+	for seed := range matches {
+		events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(int64(seed))), strong, weak)
+		require.NoError(t, err)
+		switch soccer.CreateGameStats(events).Outcome(soccer.TeamTypeHome) {
+		case soccer.GameOutcomeTypeWon:
+			points++
+		case soccer.GameOutcomeTypeDrawn:
+			points += 0.5
+		}
+	}
+	assert.InDelta(t, points/matches, table.Expected("strong", "weak"), 0.05)
+
+	table.Teams["strong"] = rating.Entry{Rating: 1200, Played: 10}
+	assert.False(t, table.Seed("strong", strong), "results outrank the lineup")
+	assert.Less(t, table.Gap("strong", strong), -500.0, "a strong lineup with a weak record stands out")
+}
+
+func TestRankings(t *testing.T) {
+	table := rating.New(rating.DefaultRules())
+	_, err := table.Record("m1", "a", "b", score(2, 0))
+	require.NoError(t, err)
+	_, err = table.Record("m2", "c", "d", score(0, 0))
+	require.NoError(t, err)
+
+	rankings := table.Rankings()
+	require.Len(t, rankings, 4)
+	assert.Equal(t, []string{"a", "c", "d", "b"},
+		[]string{rankings[0].TeamID, rankings[1].TeamID, rankings[2].TeamID, rankings[3].TeamID})
+	assert.Equal(t, 1, rankings[0].Position)
+	assert.Equal(t, 1, rankings[0].Played)
+}
+
+func TestTable_JSONRoundTrip(t *testing.T) {
+	table := rating.New(rating.DefaultRules())
+	_, err := table.Record("m1", "a", "b", score(3, 1))
+	require.NoError(t, err)
+
+	body, err := json.Marshal(table)
+	require.NoError(t, err)
+	var loaded rating.Table
+	require.NoError(t, json.Unmarshal(body, &loaded))
+	assert.Equal(t, *table, loaded)
+
+	_, err = loaded.Record("m2", "a", "a", score(0, 0))
+	assert.ErrorIs(t, err, rating.ErrSameTeam)
+}
@@ -13,15 +13,19 @@ import (
 //
 // Press shifts which attribute matters most: high press demands work rate
 // (close space, win the ball back), low press leans on technique (keep the
-// ball, pick passes). Neutral / no-tactic falls back to the legacy weights.
+// ball, pick passes). Passing style layers on top: possession adds
+// Technique; direct and balanced leave the press weights alone, since direct
+// play bypasses midfield rather than changing what it asks of it. Neutral /
+// no-tactic falls back to the legacy weights.
 //
 // In v1 every "raw" score folded SpeedRating in, which meant a fast attacker
 // got a "free" boost to control + defense. v2 splits the physical attribute
 // per action, so increasing WorkRate only affects control, increasing Pace
 // only affects attack, and increasing Recovery only affects defense.
 func rawControl(p PlayerAttributes, tactics Tactics) float64 {
-	w := tuning.ControlWeightsForPress(string(tactics.Press))
-	return weighted(p.ControlRating, p.EffectiveWorkRate(), w.Skill, w.Physical, w.Divisor)
+	w := tuning.ControlWeightsForPassingStyle(string(tactics.PassingStyle), tuning.ControlWeightsForPress(string(tactics.Press)))
+	num := p.ControlRating*w.Skill + p.EffectiveWorkRate()*w.Physical + p.EffectiveTechnique()*w.Technique
+	return math.Round(float64(num) / float64(w.Divisor))
 }
 
 // rawAttack returns a player's attack score from raw attributes only, using
@@ -45,21 +49,29 @@ func rawAttackForChance(p PlayerAttributes, ct ChanceType) float64 {
 	return defaultAttackScore(p)
 }
 
-// rawDefense returns a player's defense score from raw attributes only.
+// rawDefense returns a player's chance-type-agnostic defense score (the
+// open-play save for goalkeepers). See rawDefenseForChance.
+func rawDefense(p PlayerAttributes, tactics Tactics) float64 {
+	return rawDefenseForChance(p, tactics, ChanceTypeOpenPlay)
+}
+
+// rawDefenseForChance returns a player's defense score against a given
+// chance type from raw attributes only.
 //
-// Goalkeepers are scored on their GoalkeeperRating + Recovery — saves are
-// the goalkeeper's contribution to the defensive total, and tackling
-// doesn't apply. Goalkeeper scoring is tactic-agnostic.
+// Goalkeepers are scored on their save rating for the chance + Recovery —
+// saves are the goalkeeper's contribution to the defensive total, and
+// tackling doesn't apply. Goalkeeper scoring is tactic-agnostic.
 //
 // Outfield defenders combine DefenseRating (positioning / awareness),
 // Tackling (the actual dispossess / intercept work), and Recovery (the
 // physical chase). The line-height tactic shifts the weights: a high line
 // leans on Recovery (chase balls in behind), a deep line leans on
 // DefenseRating + Tackling (positioning + duels). Neutral falls back to the
-// balanced legacy weighting.
-func rawDefense(p PlayerAttributes, tactics Tactics) float64 {
+// balanced legacy weighting. Outfield defense doesn't depend on the chance
+// type.
+func rawDefenseForChance(p PlayerAttributes, tactics Tactics, ct ChanceType) float64 {
 	if isGoalkeeper(p) {
-		return weighted(p.GoalkeeperRating, p.SpeedRating,
+		return weighted(goalkeeperSaveRating(p, ct), p.SpeedRating,
 			tuning.DefenseSkillWeight, tuning.DefenseSpeedWeight, tuning.DefenseDivisor)
 	}
 	w := tuning.DefenseWeightsForLineHeight(string(tactics.LineHeight))
@@ -67,6 +79,19 @@ func rawDefense(p PlayerAttributes, tactics Tactics) float64 {
 	return math.Round(float64(num) / float64(w.Divisor))
 }
 
+// goalkeeperSaveRating is the keeper's 0-100 rating against a chance type:
+// Positioning for shots from distance (long range, free kicks), Reflexes
+// for everything closer in. Handling doesn't make the save; it decides
+// whether the keeper holds it (see shotOutcome). With no specialist
+// attributes set both accessors fall back to GoalkeeperRating, and so does
+// the result.
+func goalkeeperSaveRating(p PlayerAttributes, ct ChanceType) int {
+	if ct == ChanceTypeLongRange || ct == ChanceTypeFreeKick {
+		return p.EffectivePositioning()
+	}
+	return p.EffectiveReflexes()
+}
+
 // playerControl applies the skill curve, out-of-position penalty, and injury
 // reduction to a raw control score. This is what the engine actually consumes
 // when summing a team's contribution from each player. The team's own Tactics
@@ -83,25 +108,25 @@ func rawDefense(p PlayerAttributes, tactics Tactics) float64 {
 // differentials are amplified before team-level aggregation and chance
 // resolution — see tuning.SkillCurve for the rationale.
 func playerControl(sp SelectedPlayer, tactics Tactics) float64 {
-	return adjustForState(sp, tuning.SkillCurve(rawControl(sp.Attributes, tactics)))
+	return adjustForState(sp, tuning.SkillCurve(rawControl(injuredAttributes(sp), tactics)))
 }
 
 // playerAttack returns the chance-type-agnostic attack score (open-play
 // weights). Kept for tests and any caller that doesn't yet know the chance
 // type. Engine call sites should use playerAttackForChance instead.
 func playerAttack(sp SelectedPlayer) float64 {
-	return adjustForState(sp, tuning.SkillCurve(rawAttack(sp.Attributes)))
+	return adjustForState(sp, tuning.SkillCurve(rawAttack(injuredAttributes(sp))))
 }
 
 // playerAttackForChance applies the curve, state adjustments, and roles to
 // the chance-type-specific raw attack score. This is what makes player builds
 // matter: the same player produces different scores on a corner vs a 1-on-1.
 func playerAttackForChance(sp SelectedPlayer, ct ChanceType) float64 {
-	return adjustForState(sp, tuning.SkillCurve(rawAttackForChance(sp.Attributes, ct)))
+	return adjustForState(sp, tuning.SkillCurve(rawAttackForChance(injuredAttributes(sp), ct)))
 }
 
 // playerDefense applies the skill curve + state adjustments to the raw
-// defense score. The team's Tactics (specifically LineHeight) shifts the
+// defense score (open play; see playerDefenseForChance). The team's Tactics (specifically LineHeight) shifts the
 // underlying attribute weighting between positioning and recovery.
 //
 // The Ball Winner role is *not* a per-player multiplier here — like
@@ -110,7 +135,13 @@ func playerAttackForChance(sp SelectedPlayer, ct ChanceType) float64 {
 // within their position group. Tagging a poor defender drags the team's
 // defense down rather than giving them a free boost.
 func playerDefense(sp SelectedPlayer, tactics Tactics) float64 {
-	return adjustForState(sp, tuning.SkillCurve(rawDefense(sp.Attributes, tactics)))
+	return playerDefenseForChance(sp, tactics, ChanceTypeOpenPlay)
+}
+
+// playerDefenseForChance is playerDefense against a specific chance type —
+// only goalkeepers' scores differ between chance types.
+func playerDefenseForChance(sp SelectedPlayer, tactics Tactics, ct ChanceType) float64 {
+	return adjustForState(sp, tuning.SkillCurve(rawDefenseForChance(injuredAttributes(sp), tactics, ct)))
 }
 
 // captainBoost returns the team-wide multiplier (control + defense) driven
@@ -148,10 +179,12 @@ func captainQuality(p PlayerAttributes) int {
 }
 
 // adjustForState scales a raw score by the out-of-position penalty (if the
-// player is slotted somewhere they can't play), the injury multiplier (if
-// they're carrying an injury), and the captain self-boost (if this player
-// wears the armband — small ± based on captain quality, since a poor
-// captain feels the burden while a quality captain plays above themselves).
+// player is slotted somewhere they can't play), the flat injury multiplier
+// (if they're carrying an injury that doesn't name its attributes — see
+// injuredAttributes for the ones that do), and the captain self-boost (if
+// this player wears the armband — small ± based on captain quality, since a
+// poor captain feels the burden while a quality captain plays above
+// themselves).
 func adjustForState(sp SelectedPlayer, raw float64) float64 {
 	score := raw
 	if sp.IsOutOfPosition() {
@@ -164,20 +197,42 @@ func adjustForState(sp SelectedPlayer, raw float64) float64 {
 	return score
 }
 
-// injuryScale returns the stat-reduction multiplier for an injury event.
-// v1 only applied the multiplier for high-severity injuries (StatsReduction
-// < StatsReductionHighSeverity, which is identity-comparison nonsense). v2
-// applies whatever multiplier the injury declares — low-severity injuries
-// nick a few percent off, high-severity take a bigger chunk. If there's no
-// injury, returns 1.0.
+// injuryScale returns the flat stat-reduction multiplier for an injury
+// event. v1 only applied the multiplier for high-severity injuries
+// (StatsReduction < StatsReductionHighSeverity, which is identity-comparison
+// nonsense). v2 applies whatever multiplier the injury declares, but only
+// for injuries without an Affects list — those are applied per attribute by
+// injuredAttributes instead. If there's no injury, returns 1.0.
 func injuryScale(e *InjuryEvent) float64 {
-	if e == nil {
+	if e == nil || len(e.Injury.Affects) > 0 {
 		return 1.0
 	}
-	if e.Injury.StatsReduction <= 0 || e.Injury.StatsReduction > 1 {
-		return 1.0
+	return e.Injury.reduction()
+}
+
+// injuredAttributes returns the player's attributes with their injury's
+// StatsReduction applied to each attribute it affects. An affected
+// specialist attribute that falls back to a composite (e.g. Composure →
+// ControlRating) is first pinned to its effective value, so a concussion
+// hurts a legacy player's composure without touching their control.
+// Unaffected specialists keep falling back, so a hamstring (SpeedRating)
+// also slows a player whose WorkRate isn't set.
+func injuredAttributes(sp SelectedPlayer) PlayerAttributes {
+	p := sp.Attributes
+	if sp.Injury == nil || len(sp.Injury.Injury.Affects) == 0 {
+		return p
+	}
+	s := sp.Injury.Injury.reduction()
+	scale := func(v int) int {
+		return int(math.Round(float64(v) * s))
+	}
+	// Read from the original attributes throughout so an injury naming both
+	// a base and its specialist doesn't cut the specialist twice.
+	orig := sp.Attributes
+	for _, a := range sp.Injury.Injury.Affects {
+		p = p.WithAttribute(a, scale(orig.Effective(a)))
 	}
-	return e.Injury.StatsReduction
+	return p
 }
 
 // weighted computes (skill*skillW + physical*physW) / divisor and rounds.
@@ -211,15 +266,19 @@ func isGoalkeeper(p PlayerAttributes) bool {
 // a real choice instead of a free boost: tag your best controller and you
 // gain, tag a weak player and you lose.
 func teamControl(lineup GameLineup) float64 {
-	tactics := lineup.Team.Tactics
-	return rolePositionAverage(lineup.Players, tuning.ControlPositionWeights, func(sp SelectedPlayer) (float64, float64) {
+	return rolePositionAverage(lineup.Players, tuning.ControlPositionWeights, controlScore(lineup.Team.Tactics))
+}
+
+// controlScore is each player's (score, weight) in teamControl.
+func controlScore(tactics Tactics) func(SelectedPlayer) (float64, float64) {
+	return func(sp SelectedPlayer) (float64, float64) {
 		score := playerControl(sp, tactics)
 		weight := 1.0
 		if sp.Role == PlayerRolePlaymaker {
 			weight = tuning.PlaymakerControlWeight
 		}
 		return score, weight
-	})
+	}
 }
 
 // teamDefense mirrors teamControl's structure: position-weighted average
@@ -227,15 +286,45 @@ func teamControl(lineup GameLineup) float64 {
 // A quality Ball Winner amplifies the team's defensive shape; a weak one
 // drags it down — exactly like Playmaker on the control side.
 func teamDefense(lineup GameLineup) float64 {
-	tactics := lineup.Team.Tactics
-	return rolePositionAverage(lineup.Players, tuning.DefensePositionWeights, func(sp SelectedPlayer) (float64, float64) {
-		score := playerDefense(sp, tactics)
+	return teamDefenseForChance(lineup, ChanceTypeOpenPlay, marking{})
+}
+
+// teamDefenseForChance is teamDefense against a specific chance type (the
+// goalkeeper's save rating depends on it) with a man-marking assignment:
+// the marker is busy following one player, so their score counts at
+// tuning.MarkerDefenseScale in the team's shape.
+func teamDefenseForChance(lineup GameLineup, ct ChanceType, m marking) float64 {
+	return rolePositionAverage(lineup.Players, tuning.DefensePositionWeights, defenseScore(lineup.Team.Tactics, ct, m))
+}
+
+// defenseScore is each player's (score, weight) in teamDefenseForChance.
+func defenseScore(tactics Tactics, ct ChanceType, m marking) func(SelectedPlayer) (float64, float64) {
+	return func(sp SelectedPlayer) (float64, float64) {
+		score := playerDefenseForChance(sp, tactics, ct)
+		if m.active() && sp.ID == m.Marker.ID {
+			score *= tuning.MarkerDefenseScale
+		}
 		weight := 1.0
 		if sp.Role == PlayerRoleBallWinner {
 			weight = tuning.BallWinnerDefenseWeight
 		}
 		return score, weight
-	})
+	}
+}
+
+// teamAttack is the open-play attack score of the player a chance is
+// likely to fall to: each player's score weighted by their share of
+// pickAttacker's draw, so it leans on the forwards.
+func teamAttack(lineup GameLineup) float64 {
+	players, weights, total := attackerPool(lineup, ChanceTypeOpenPlay, "", marking{})
+	if total == 0 {
+		return 0
+	}
+	var sum float64
+	for i, p := range players {
+		sum += weights[i] * playerAttackForChance(p, ChanceTypeOpenPlay)
+	}
+	return sum / total
 }
 
 // rolePositionAverage groups players by their selected position. Each player
@@ -251,34 +340,13 @@ func teamDefense(lineup GameLineup) float64 {
 // Ball Winner (in teamDefense) use heavier weights to act as focal points
 // within their group.
 func rolePositionAverage(players []SelectedPlayer, w tuning.PositionWeights, get func(SelectedPlayer) (float64, float64)) float64 {
-	type bucket struct{ sum, totalW float64 }
-	var gk, def, mid, atk bucket
-	for _, p := range players {
-		score, weight := get(p)
-		b := &gk
-		switch p.SelectedPosition {
-		case PlayerPositionDefense:
-			b = &def
-		case PlayerPositionMidfield:
-			b = &mid
-		case PlayerPositionAttack:
-			b = &atk
-		}
-		b.sum += score * weight
-		b.totalW += weight
-	}
-	groups := []struct {
-		b bucket
-		w float64
-	}{
-		{gk, w.Goalkeeper}, {def, w.Defense}, {mid, w.Midfield}, {atk, w.Attack},
-	}
+	groups, _ := positionGroups(players, w, get)
 	var total, populatedW float64
 	for _, g := range groups {
-		if g.b.totalW == 0 {
+		if g.totalW == 0 {
 			continue
 		}
-		total += g.b.sum / g.b.totalW * g.w
+		total += g.sum / g.totalW * g.w
 		populatedW += g.w
 	}
 	if populatedW == 0 {
@@ -286,3 +354,56 @@ func rolePositionAverage(players []SelectedPlayer, w tuning.PositionWeights, get
 	}
 	return total / populatedW
 }
+
+// rolePositionShares returns each player's share of rolePositionAverage, in
+// lineup order: the part of the team score their own score accounts for.
+// The shares add up to 1 (or are all 0 for an empty lineup).
+func rolePositionShares(players []SelectedPlayer, w tuning.PositionWeights, get func(SelectedPlayer) (float64, float64)) []float64 {
+	groups, member := positionGroups(players, w, get)
+	var total, populatedW float64
+	for _, g := range groups {
+		if g.totalW == 0 {
+			continue
+		}
+		total += g.sum / g.totalW * g.w
+		populatedW += g.w
+	}
+	shares := make([]float64, len(players))
+	if total == 0 {
+		return shares
+	}
+	for i, p := range players {
+		g := groups[member[i]]
+		score, weight := get(p)
+		shares[i] = score * weight / g.totalW * g.w / total
+	}
+	return shares
+}
+
+// positionGroup is one position group's weighted scores and the position
+// weight it carries.
+type positionGroup struct{ sum, totalW, w float64 }
+
+// positionGroups buckets players by selected position (goalkeeper,
+// defense, midfield, attack) and returns the groups and each player's
+// group index.
+func positionGroups(players []SelectedPlayer, w tuning.PositionWeights, get func(SelectedPlayer) (float64, float64)) ([4]positionGroup, []int) {
+	groups := [4]positionGroup{{w: w.Goalkeeper}, {w: w.Defense}, {w: w.Midfield}, {w: w.Attack}}
+	member := make([]int, len(players))
+	for i, p := range players {
+		score, weight := get(p)
+		g := 0
+		switch p.SelectedPosition {
+		case PlayerPositionDefense:
+			g = 1
+		case PlayerPositionMidfield:
+			g = 2
+		case PlayerPositionAttack:
+			g = 3
+		}
+		member[i] = g
+		groups[g].sum += score * weight
+		groups[g].totalW += weight
+	}
+	return groups, member
+}
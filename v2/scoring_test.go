@@ -38,6 +38,21 @@ func TestEffectiveWorkRate_PrefersExplicitField(t *testing.T) {
 	assert.Equal(t, 30, p.EffectiveWorkRate())
 }
 
+// Effective reads through the fallbacks; WithAttribute pins a specialist
+// and leaves the rating it fell back to alone.
+func TestAttributes_EffectiveAndWithAttribute(t *testing.T) {
+	p := legacyAttrs()
+	assert.Equal(t, 80, p.Effective(AttributeComposure), "composure falls back to control")
+	assert.Equal(t, 90, p.Effective(AttributeWorkRate))
+	assert.Equal(t, 60, p.Effective(AttributeDefense))
+	assert.Zero(t, p.Effective("charisma"))
+
+	q := p.WithAttribute(AttributeComposure, p.Effective(AttributeComposure)+1)
+	assert.Equal(t, 81, q.Effective(AttributeComposure))
+	assert.Equal(t, 80, q.ControlRating)
+	assert.Equal(t, p, p.WithAttribute("charisma", 99))
+}
+
 // SpeedRating drives both attack and defense scoring. Bumping it must lift
 // both raw scores; ControlRating-driven control must be unaffected.
 func TestRawScores_SpeedRatingDrivesAttackAndDefense(t *testing.T) {
@@ -411,6 +426,21 @@ func TestCaptain_SelfBoostShiftsWithQuality(t *testing.T) {
 		"poor-quality captain must reduce their own score (burden of armband)")
 }
 
+// Possession passing folds Technique into control: a technician gains on a
+// possession side, a runner with poor touch loses. Other styles leave the
+// press weighting alone.
+func TestRawControl_PossessionWeightsTechnique(t *testing.T) {
+	technician := PlayerAttributes{ControlRating: 75, WorkRate: 75, Technique: 95}
+	runner := PlayerAttributes{ControlRating: 75, WorkRate: 75, Technique: 50}
+	possession := Tactics{PassingStyle: PassingStylePossession}
+
+	assert.Greater(t, rawControl(technician, possession), rawControl(technician, Tactics{}))
+	assert.Less(t, rawControl(runner, possession), rawControl(runner, Tactics{}))
+	for _, style := range []PassingStyle{PassingStyleDirect, PassingStyleBalanced} {
+		assert.Equal(t, rawControl(runner, Tactics{}), rawControl(runner, Tactics{PassingStyle: style}))
+	}
+}
+
 // Sentinel: pin the attribute math so we don't drift accidentally during
 // tuning. If these change intentionally, update the constants in tuning.go
 // in the same PR and explain why.
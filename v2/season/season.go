@@ -0,0 +1,371 @@
+// Package season runs a season of several divisions with promotion and
+// relegation between them. Each division is a league (see the league
+// package); once every league match is played, the teams below the
+// automatic promotion places can play a knockout (see the cup package) for
+// one more place, and Rollover turns the final tables into next season's
+// divisions.
+//
+// A season is a deterministic function of (divisions, block hash, rules,
+// lineups). Each random stream is derived from the season's block hash and
+// a label, so any match can be re-verified on its own:
+//
+//	<hash>/D<n>/schedule          a division's fixture list
+//	<hash>/D<n>/lot               a division's drawing of lots
+//	<hash>/D<n>-R<r>-<m>          a league match
+//	<hash>/D<n>/playoff/R<r>-<m>  a playoff tie (see cup.TieSource)
+//
+// Divisions are numbered from 1, the top. The playoff of division n is for
+// promotion to division n-1. The Season value round-trips through JSON, so
+// matchdays can be played on different days.
+package season
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/algorand"
+	"github.com/stein-f/oink-soccer-common/v2/cup"
+	"github.com/stein-f/oink-soccer-common/v2/internal/roundrobin"
+	"github.com/stein-f/oink-soccer-common/v2/league"
+)
+
+var (
+	ErrInvalidRules  = errors.New("season: invalid rules")
+	ErrTooFewTeams   = errors.New("season: not enough teams in a division")
+	ErrDuplicateTeam = errors.New("season: duplicate team")
+	ErrComplete      = errors.New("season: every round has been played")
+	ErrNotOver       = errors.New("season: the season is not over")
+	// ErrMissingLineup is league's sentinel, returned for cup matches too.
+	ErrMissingLineup = league.ErrMissingLineup
+)
+
+// Rules are the dials of a season. The same rules apply between every pair
+// of adjacent divisions.
+type Rules struct {
+	// Promoted is how many teams at the top of each division below the
+	// first go up automatically.
+	Promoted int `json:"promoted"`
+	// Relegated is how many teams at the bottom of each division above the
+	// last go down.
+	Relegated int `json:"relegated"`
+	// Playoff is how many teams below the automatic places play a knockout
+	// for one more promotion place: 0 for no playoff, otherwise at least 2.
+	// Seeds follow the table and the higher seed is at home.
+	Playoff int `json:"playoff"`
+	// Division is how each division is scheduled and ranked.
+	Division league.Rules `json:"division"`
+}
+
+// DefaultRules returns one automatic promotion place and a four-team
+// playoff for a second, two relegation places, and league.DefaultRules for
+// each division. Division sizes are unchanged from one season to the next.
+func DefaultRules() Rules {
+	return Rules{Promoted: 1, Relegated: 2, Playoff: 4, Division: league.DefaultRules()}
+}
+
+// up is how many teams go up from each division below the first.
+func (r Rules) up() int {
+	if r.Playoff > 0 {
+		return r.Promoted + 1
+	}
+	return r.Promoted
+}
+
+// Season is a season's whole state.
+type Season struct {
+	BlockHash string     `json:"block_hash"`
+	Rules     Rules      `json:"rules"`
+	Divisions []Division `json:"divisions"`
+}
+
+// Division is one division of the season.
+type Division struct {
+	Level     int               `json:"level"` // 1 is the top division
+	Teams     []string          `json:"teams"`
+	Rounds    []league.Round    `json:"rounds"`
+	Matches   []Match           `json:"matches"`
+	Standings []league.Standing `json:"standings"`
+	// Playoff is the promotion playoff, drawn once every league match has
+	// been played. It is nil for the top division or without a playoff.
+	Playoff *cup.Bracket `json:"playoff,omitempty"`
+}
+
+// Name returns the division's label in stream labels and match IDs, "D1"
+// for the top division.
+func (d Division) Name() string {
+	return fmt.Sprintf("D%d", d.Level)
+}
+
+// group is the division's league as roundrobin plays it.
+func (d Division) group() roundrobin.Group {
+	return roundrobin.Group{Name: d.Name(), Teams: d.Teams, Rounds: d.Rounds, Matches: d.Matches}
+}
+
+// Match is a played league match. Its ID is "D<level>-R<round>-<fixture>",
+// both 1-based.
+type Match = league.Match
+
+// Movement is why a team changed division.
+type Movement string
+
+const (
+	MovementPromoted  Movement = "Promoted"
+	MovementPlayoff   Movement = "Playoff"
+	MovementRelegated Movement = "Relegated"
+)
+
+// Move is one team changing division at the end of a season.
+type Move struct {
+	TeamID string   `json:"team_id"`
+	From   int      `json:"from"`
+	To     int      `json:"to"`
+	Reason Movement `json:"reason"`
+}
+
+// New schedules a season. divisions lists each division's teams, the top
+// division first. Rules are checked against the division sizes: the
+// promotion and relegation places of a division can't overlap, and every
+// division must still have at least two teams after the rollover. Nothing
+// is played.
+func New(divisions [][]string, blockHash string, rules Rules) (*Season, error) {
+	if len(divisions) == 0 || rules.Promoted < 0 || rules.Relegated < 0 || rules.Playoff < 0 || rules.Playoff == 1 {
+		return nil, fmt.Errorf("%w: %d divisions, %d promoted, %d relegated, playoff of %d",
+			ErrInvalidRules, len(divisions), rules.Promoted, rules.Relegated, rules.Playoff)
+	}
+	seen := make(map[string]bool)
+	for _, teams := range divisions {
+		for _, id := range teams {
+			if seen[id] {
+				return nil, fmt.Errorf("%w: %q", ErrDuplicateTeam, id)
+			}
+			seen[id] = true
+		}
+	}
+	last := len(divisions) - 1
+	for i, teams := range divisions {
+		// out is every place a team can end up leaving from, which the
+		// division must have room for; only up() of the playoff places
+		// actually leave.
+		var out, leaving, in int
+		if i > 0 {
+			out += rules.Promoted + rules.Playoff
+			leaving += rules.up()
+			in += rules.Relegated
+		}
+		if i < last {
+			out += rules.Relegated
+			leaving += rules.Relegated
+			in += rules.up()
+		}
+		if len(teams) < 2 || len(teams) < out {
+			return nil, fmt.Errorf("%w: division %d has %d teams and needs %d", ErrTooFewTeams, i+1, len(teams), max(2, out))
+		}
+		if next := len(teams) - leaving + in; next < 2 {
+			return nil, fmt.Errorf("%w: division %d would have %d teams next season", ErrInvalidRules, i+1, next)
+		}
+	}
+
+	s := &Season{BlockHash: blockHash, Rules: rules, Divisions: make([]Division, len(divisions))}
+	for i, teams := range divisions {
+		d := &s.Divisions[i]
+		d.Level, d.Teams = i+1, slices.Clone(teams)
+		rounds, err := league.Schedule(s.source(d.Name()+"/schedule"), d.Teams, rules.Division)
+		if err != nil {
+			return nil, fmt.Errorf("season: division %d: %w", d.Level, err)
+		}
+		d.Rounds = rounds
+		if d.Standings, err = roundrobin.Rank(d.group(), rules.Division, s.source); err != nil {
+			return nil, fmt.Errorf("season: %w", err)
+		}
+	}
+	return s, nil
+}
+
+func (s *Season) source(label string) *rand.Rand {
+	return algorand.SeedFromBlockHash(s.BlockHash + "/" + label)
+}
+
+func (s *Season) groups() []roundrobin.Group {
+	groups := make([]roundrobin.Group, len(s.Divisions))
+	for i, d := range s.Divisions {
+		groups[i] = d.group()
+	}
+	return groups
+}
+
+// Matchday returns how many league matchdays have been played.
+func (s *Season) Matchday() int {
+	return roundrobin.Matchday(s.groups())
+}
+
+// matchdays is the length of the league season: divisions of different
+// sizes have different numbers of rounds.
+func (s *Season) matchdays() int {
+	return roundrobin.Matchdays(s.groups())
+}
+
+// playoffRound returns the earliest round any playoff has left to play, or
+// -1 when there is none.
+func (s *Season) playoffRound() int {
+	next := -1
+	for _, d := range s.Divisions {
+		if d.Playoff == nil {
+			continue
+		}
+		if i := d.Playoff.Next(); i >= 0 && (next < 0 || i < next) {
+			next = i
+		}
+	}
+	return next
+}
+
+// Done reports whether every league match and playoff has been played.
+func (s *Season) Done() bool {
+	return s.Matchday() == s.matchdays() && s.playoffRound() < 0
+}
+
+// PlayRound plays the next round: a league matchday across every division,
+// or a playoff round across every division with a playoff. The playoffs
+// are drawn as soon as the last matchday is in. lineups are keyed by team
+// ID and only need to cover the teams playing this round. If a round fails
+// nothing from it is recorded.
+func (s *Season) PlayRound(lineups map[string]soccer.GameLineup) error {
+	if s.Done() {
+		return ErrComplete
+	}
+	if s.Matchday() == s.matchdays() {
+		return s.playPlayoffRound(lineups)
+	}
+
+	played, err := roundrobin.PlayMatchday(s.groups(), lineups, s.Rules.Division, s.source)
+	if err != nil {
+		return fmt.Errorf("season: %w", err)
+	}
+	for i, p := range played {
+		s.Divisions[i].Matches, s.Divisions[i].Standings = p.Matches, p.Standings
+	}
+	if s.Matchday() == s.matchdays() {
+		return s.drawPlayoffs()
+	}
+	return nil
+}
+
+// Play plays every remaining round.
+func (s *Season) Play(lineups map[string]soccer.GameLineup) error {
+	for !s.Done() {
+		if err := s.PlayRound(lineups); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawPlayoffs seeds the teams just below the automatic promotion places
+// of every division but the top into a knockout, in table order.
+func (s *Season) drawPlayoffs() error {
+	if s.Rules.Playoff == 0 {
+		return nil
+	}
+	for i := 1; i < len(s.Divisions); i++ {
+		d := &s.Divisions[i]
+		seeds := make([]string, s.Rules.Playoff)
+		for j := range seeds {
+			seeds[j] = d.Standings[s.Rules.Promoted+j].TeamID
+		}
+		bracket, err := cup.Draw(seeds)
+		if err != nil {
+			return fmt.Errorf("season: division %d playoff: %w", d.Level, err)
+		}
+		d.Playoff = bracket
+	}
+	return nil
+}
+
+// playPlayoffRound plays the next playoff round. Every playoff is the same
+// size, so they move in step; the lineups are checked up front so a missing
+// one can't leave them out of step.
+func (s *Season) playPlayoffRound(lineups map[string]soccer.GameLineup) error {
+	round := s.playoffRound()
+	for _, d := range s.Divisions {
+		if d.Playoff == nil || d.Playoff.Next() != round {
+			continue
+		}
+		for _, tie := range d.Playoff.Rounds[round].Ties {
+			for _, id := range []string{tie.Home, tie.Away} {
+				if _, ok := lineups[id]; !ok && tie.Winner == "" {
+					return fmt.Errorf("%w: %q", ErrMissingLineup, id)
+				}
+			}
+		}
+	}
+	for i := range s.Divisions {
+		d := &s.Divisions[i]
+		if d.Playoff == nil || d.Playoff.Next() != round {
+			continue
+		}
+		if err := d.Playoff.PlayRound(lineups, s.BlockHash+"/"+d.Name()+"/playoff"); err != nil {
+			return fmt.Errorf("season: division %d playoff: %w", d.Level, err)
+		}
+	}
+	return nil
+}
+
+// Rollover returns next season's divisions, top first, and every team that
+// moved. Each division lists the teams relegated into it, then the teams
+// that stayed, then the teams promoted into it, each in the order they
+// finished.
+func (s *Season) Rollover() ([][]string, []Move, error) {
+	if !s.Done() {
+		return nil, nil, ErrNotOver
+	}
+	var moves []Move
+	down := make([][]string, len(s.Divisions)) // relegated out of each division
+	up := make([][]string, len(s.Divisions))   // promoted out of each division
+	for i, d := range s.Divisions {
+		if i > 0 {
+			for _, row := range d.Standings[:s.Rules.Promoted] {
+				up[i] = append(up[i], row.TeamID)
+				moves = append(moves, Move{TeamID: row.TeamID, From: d.Level, To: d.Level - 1, Reason: MovementPromoted})
+			}
+			if d.Playoff != nil {
+				up[i] = append(up[i], d.Playoff.Winner)
+				moves = append(moves, Move{TeamID: d.Playoff.Winner, From: d.Level, To: d.Level - 1, Reason: MovementPlayoff})
+			}
+		}
+		if i < len(s.Divisions)-1 {
+			for _, row := range d.Standings[len(d.Standings)-s.Rules.Relegated:] {
+				down[i] = append(down[i], row.TeamID)
+				moves = append(moves, Move{TeamID: row.TeamID, From: d.Level, To: d.Level + 1, Reason: MovementRelegated})
+			}
+		}
+	}
+
+	next := make([][]string, len(s.Divisions))
+	for i, d := range s.Divisions {
+		if i > 0 {
+			next[i] = append(next[i], down[i-1]...)
+		}
+		for _, row := range d.Standings {
+			if !slices.Contains(up[i], row.TeamID) && !slices.Contains(down[i], row.TeamID) {
+				next[i] = append(next[i], row.TeamID)
+			}
+		}
+		if i < len(s.Divisions)-1 {
+			next[i] = append(next[i], up[i+1]...)
+		}
+	}
+	return next, moves, nil
+}
+
+// Next rolls the season over and schedules the next one with the same
+// rules, seeded from that season's block hash.
+func (s *Season) Next(blockHash string) (*Season, error) {
+	divisions, _, err := s.Rollover()
+	if err != nil {
+		return nil, err
+	}
+	return New(divisions, blockHash, s.Rules)
+}
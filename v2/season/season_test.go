@@ -0,0 +1,213 @@
+package season_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/algorand"
+	"github.com/stein-f/oink-soccer-common/v2/league"
+	"github.com/stein-f/oink-soccer-common/v2/season"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// field returns divisions of the given sizes and every team's lineup. The
+// top division is strong and the rest weak.
+func field(sizes ...int) ([][]string, map[string]soccer.GameLineup) {
+	divisions := make([][]string, len(sizes))
+	lineups := make(map[string]soccer.GameLineup)
+	for d, n := range sizes {
+		strong := 0
+		if d == 0 {
+			strong = n
+		}
+		ids, l := testdata.Field(fmt.Sprintf("d%d-", d+1), n, strong)
+		divisions[d] = ids
+		maps.Copy(lineups, l)
+	}
+	return divisions, lineups
+}
+
+func singleRoundRobin() season.Rules {
+	rules := season.DefaultRules()
+	rules.Division.Format = league.FormatSingle
+	return rules
+}
+
+func TestPlay_PromotesAndRelegates(t *testing.T) {
+	divisions, lineups := field(8, 8, 8)
+	rules := singleRoundRobin()
+	s, err := season.New(divisions, testdata.BlockHash, rules)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Play(lineups))
+	assert.True(t, s.Done())
+	assert.Nil(t, s.Divisions[0].Playoff, "nothing to be promoted into from the top")
+	for _, d := range s.Divisions[1:] {
+		require.NotNil(t, d.Playoff)
+		assert.Equal(t, d.Standings[1].TeamID, d.Playoff.Rounds[0].Ties[0].Home, "the best-placed playoff team is top seed")
+		assert.NotEmpty(t, d.Playoff.Winner)
+	}
+
+	next, moves, err := s.Rollover()
+	require.NoError(t, err)
+	require.Len(t, next, 3)
+	for _, teams := range next {
+		assert.Len(t, teams, 8, "one up, one up through the playoff, two down")
+	}
+	assert.Len(t, moves, 8)
+
+	divisionOf := map[string]int{}
+	for i, teams := range next {
+		for _, id := range teams {
+			divisionOf[id] = i + 1
+		}
+	}
+	for _, m := range moves {
+		assert.Equal(t, m.To, divisionOf[m.TeamID], "%s", m.TeamID)
+	}
+	top, second := s.Divisions[0].Standings, s.Divisions[1].Standings
+	assert.Equal(t, 2, divisionOf[top[6].TeamID])
+	assert.Equal(t, 2, divisionOf[top[7].TeamID])
+	assert.Equal(t, 1, divisionOf[top[5].TeamID])
+	assert.Equal(t, 1, divisionOf[second[0].TeamID])
+	assert.Equal(t, 1, divisionOf[s.Divisions[1].Playoff.Winner])
+	assert.Equal(t, 3, divisionOf[second[7].TeamID])
+	assert.Contains(t, moves, season.Move{TeamID: second[0].TeamID, From: 2, To: 1, Reason: season.MovementPromoted})
+	assert.Contains(t, moves, season.Move{TeamID: s.Divisions[2].Playoff.Winner, From: 3, To: 2, Reason: season.MovementPlayoff})
+
+	following, err := s.Next("ANOTHERHASH")
+	require.NoError(t, err)
+	require.NoError(t, following.Play(lineups))
+	assert.True(t, following.Done())
+}
+
+func TestRollover_UnevenCountsResizeDivisions(t *testing.T) {
+	divisions, lineups := field(6, 6)
+	rules := singleRoundRobin()
+	rules.Promoted, rules.Relegated, rules.Playoff = 1, 2, 0
+
+	s, err := season.New(divisions, testdata.BlockHash, rules)
+	require.NoError(t, err)
+	require.NoError(t, s.Play(lineups))
+	assert.Nil(t, s.Divisions[1].Playoff)
+
+	next, moves, err := s.Rollover()
+	require.NoError(t, err)
+	assert.Len(t, next[0], 5)
+	assert.Len(t, next[1], 7)
+	assert.Len(t, moves, 3)
+	assert.Equal(t, s.Divisions[1].Standings[0].TeamID, next[0][4], "promoted teams are listed last")
+	assert.Equal(t, s.Divisions[0].Standings[4].TeamID, next[1][0], "relegated teams are listed first")
+}
+
+// Only the playoff winner leaves, so a division made up of its playoff
+// keeps its size.
+func TestRollover_PlayoffOnlyDivisionKeepsItsSize(t *testing.T) {
+	divisions, lineups := field(6, 2)
+	rules := singleRoundRobin()
+	rules.Promoted, rules.Relegated, rules.Playoff = 0, 1, 2
+
+	s, err := season.New(divisions, testdata.BlockHash, rules)
+	require.NoError(t, err)
+	require.NoError(t, s.Play(lineups))
+
+	next, moves, err := s.Rollover()
+	require.NoError(t, err)
+	assert.Len(t, next[0], 6)
+	assert.Len(t, next[1], 2)
+	assert.Len(t, moves, 2)
+}
+
+func TestPlay_IsReplayableFromTheSeasonSeed(t *testing.T) {
+	divisions, lineups := field(6, 6)
+	rules := singleRoundRobin()
+	rules.Playoff = 2
+
+	straight, err := season.New(divisions, testdata.BlockHash, rules)
+	require.NoError(t, err)
+	require.NoError(t, straight.Play(lineups))
+
+	resumed, err := season.New(divisions, testdata.BlockHash, rules)
+	require.NoError(t, err)
+	for !resumed.Done() {
+		body, err := json.Marshal(resumed)
+		require.NoError(t, err)
+		var loaded season.Season
+		require.NoError(t, json.Unmarshal(body, &loaded))
+		require.NoError(t, loaded.PlayRound(lineups))
+		resumed = &loaded
+	}
+	a, _, err := straight.Rollover()
+	require.NoError(t, err)
+	b, _, err := resumed.Rollover()
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+	for i := range straight.Divisions {
+		assert.Equal(t, straight.Divisions[i].Standings, resumed.Divisions[i].Standings)
+	}
+
+	// Any league match can be checked on its own.
+	m := straight.Divisions[1].Matches[0]
+	require.Equal(t, "D2-R1-1", m.ID)
+	events, _, err := soccer.RunGameWithSeed(algorand.SeedFromBlockHash(testdata.BlockHash+"/D2-R1-1"), lineups[m.Home], lineups[m.Away])
+	require.NoError(t, err)
+	assert.Equal(t, m.Events, events)
+}
+
+func TestPlayRound_FailureLeavesTheRoundUnplayed(t *testing.T) {
+	divisions, lineups := field(4, 6)
+	rules := singleRoundRobin()
+	rules.Playoff = 2
+	s, err := season.New(divisions, testdata.BlockHash, rules)
+	require.NoError(t, err)
+	for s.Matchday() < 5 {
+		require.NoError(t, s.PlayRound(lineups))
+	}
+	require.NotNil(t, s.Divisions[1].Playoff)
+
+	missing := s.Divisions[1].Playoff.Rounds[0].Ties[0].Away
+	partial := make(map[string]soccer.GameLineup, len(lineups))
+	for id, l := range lineups {
+		if id != missing {
+			partial[id] = l
+		}
+	}
+	assert.ErrorIs(t, s.PlayRound(partial), season.ErrMissingLineup)
+	assert.False(t, s.Divisions[1].Playoff.Rounds[0].Ties[0].Played)
+
+	_, _, err = s.Rollover()
+	assert.ErrorIs(t, err, season.ErrNotOver)
+	require.NoError(t, s.PlayRound(lineups))
+	assert.True(t, s.Done())
+	assert.ErrorIs(t, s.PlayRound(lineups), season.ErrComplete)
+}
+
+func TestNew_Errors(t *testing.T) {
+	divisions, _ := field(8, 8)
+
+	_, err := season.New(nil, testdata.BlockHash, season.DefaultRules())
+	assert.ErrorIs(t, err, season.ErrInvalidRules)
+
+	rules := season.DefaultRules()
+	rules.Playoff = 1
+	_, err = season.New(divisions, testdata.BlockHash, rules)
+	assert.ErrorIs(t, err, season.ErrInvalidRules)
+
+	_, err = season.New([][]string{divisions[0], divisions[1][:4]}, testdata.BlockHash, season.DefaultRules())
+	assert.ErrorIs(t, err, season.ErrTooFewTeams, "a playoff and the automatic place need five teams")
+
+	rules = season.DefaultRules()
+	rules.Relegated, rules.Promoted, rules.Playoff = 0, 4, 0
+	_, err = season.New([][]string{divisions[0][:2], divisions[1]}, testdata.BlockHash, rules)
+	assert.NoError(t, err, "the top division grows")
+	_, err = season.New([][]string{divisions[0], divisions[1][:5]}, testdata.BlockHash, rules)
+	assert.ErrorIs(t, err, season.ErrInvalidRules, "the second division would have one team left")
+
+	_, err = season.New([][]string{divisions[0], append(divisions[1], divisions[0][0])}, testdata.BlockHash, season.DefaultRules())
+	assert.ErrorIs(t, err, season.ErrDuplicateTeam)
+}
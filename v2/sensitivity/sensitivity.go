@@ -0,0 +1,223 @@
+// Package sensitivity measures what each point of each player's ratings is
+// worth in results.
+//
+// Analyze raises one attribute of one player at a time, by Rules.Step, and
+// plays the lineup against the opponent Rules.Simulations times through
+// the engine, under the lineup's own tactics. Every variant plays the same
+// dice as the unchanged lineup (see package forecast), so the gain it
+// reports is the upgrade's rather than luck's, even at a single point. The
+// upgrades come back ranked, for training and item advice. Sweep plays one
+// attribute across a range of ratings instead, which shows how the engine's
+// skill curve turns ratings into results from the bottom of the range to
+// the top.
+package sensitivity
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/forecast"
+)
+
+var (
+	ErrInvalidRules     = errors.New("sensitivity: invalid rules")
+	ErrUnknownPlayer    = errors.New("sensitivity: unknown player")
+	ErrUnknownAttribute = errors.New("sensitivity: unknown attribute")
+	ErrInvalidRating    = errors.New("sensitivity: rating out of range")
+)
+
+// MaxRating is the top of the rating scale. An upgrade that would pass it
+// is left out.
+const MaxRating = 100
+
+// attributes are every attribute the engine reads, in the order of
+// soccer.Attribute's constants.
+var attributes = []soccer.Attribute{
+	soccer.AttributeGoalkeeper,
+	soccer.AttributeDefense,
+	soccer.AttributeSpeed,
+	soccer.AttributeControl,
+	soccer.AttributeAttack,
+	soccer.AttributeWorkRate,
+	soccer.AttributeFinishing,
+	soccer.AttributeHeading,
+	soccer.AttributeTechnique,
+	soccer.AttributeComposure,
+	soccer.AttributeTackling,
+	soccer.AttributeReflexes,
+	soccer.AttributeHandling,
+	soccer.AttributePositioning,
+	soccer.AttributeDistribution,
+}
+
+// Rules are the analysis's search space and budget.
+type Rules struct {
+	// Attributes are tried on every player.
+	Attributes []soccer.Attribute `json:"attributes"`
+	// Step is the size of each upgrade, in rating points.
+	Step        int `json:"step"`
+	Simulations int `json:"simulations"`
+}
+
+// DefaultRules returns the rules the training screen uses: +1 in every
+// attribute, over 1000 matches each.
+func DefaultRules() Rules {
+	return Rules{
+		Attributes:  slices.Clone(attributes),
+		Step:        1,
+		Simulations: 1000,
+	}
+}
+
+// Upgrade is one player's attribute raised by the step, and how the lineup
+// fares with it.
+type Upgrade struct {
+	PlayerID  string           `json:"player_id"`
+	Attribute soccer.Attribute `json:"attribute"`
+	// From is the rating the engine read before the upgrade: for a
+	// specialist left unset, the rating it falls back to. To is From plus
+	// the step, set on the specialist itself.
+	From     int               `json:"from"`
+	To       int               `json:"to"`
+	Forecast forecast.Forecast `json:"forecast"`
+	// WinDelta and PointsDelta are the gain over Report.Base in win
+	// probability and expected points.
+	WinDelta    float64 `json:"win_delta"`
+	PointsDelta float64 `json:"points_delta"`
+	// XGDelta is the gain in expected-goal difference per match. It moves
+	// with every upgrade that changes a chance, so it ranks upgrades too
+	// small to turn a result within the budget.
+	XGDelta float64 `json:"xg_delta"`
+}
+
+// Report is the lineup's upgrades, best first.
+type Report struct {
+	Base forecast.Forecast `json:"base"`
+	// Opponent is the opponent given, or the lineup itself.
+	Opponent soccer.GameLineup `json:"opponent"`
+	Upgrades []Upgrade         `json:"upgrades"`
+}
+
+// Analyze returns the marginal value of every upgrade to lineup, playing
+// at home against opponent, or against itself when opponent is nil.
+// Upgrades are ranked by win probability gained, then expected-goal
+// difference gained; ties keep lineup order, then rules order.
+func Analyze(r *rand.Rand, lineup soccer.GameLineup, opponent *soccer.GameLineup, rules Rules) (Report, error) {
+	if r == nil {
+		return Report{}, soccer.ErrNilRandSource
+	}
+	if err := rules.validate(); err != nil {
+		return Report{}, err
+	}
+	rep := Report{Opponent: lineup}
+	if opponent != nil {
+		rep.Opponent = *opponent
+	}
+
+	seed := r.Int63()
+	var err error
+	if rep.Base, err = forecast.Simulate(seed, lineup, rep.Opponent, rules.Simulations); err != nil {
+		return Report{}, err
+	}
+	for i, p := range lineup.Players {
+		for _, a := range rules.Attributes {
+			from := p.Attributes.Effective(a)
+			if from+rules.Step > MaxRating {
+				continue
+			}
+			f, err := forecast.Simulate(seed, withRating(lineup, i, a, from+rules.Step), rep.Opponent, rules.Simulations)
+			if err != nil {
+				return Report{}, err
+			}
+			rep.Upgrades = append(rep.Upgrades, Upgrade{
+				PlayerID:    p.ID,
+				Attribute:   a,
+				From:        from,
+				To:          from + rules.Step,
+				Forecast:    f,
+				WinDelta:    f.Win - rep.Base.Win,
+				PointsDelta: f.Points() - rep.Base.Points(),
+				XGDelta:     (f.XGFor - f.XGAgainst) - (rep.Base.XGFor - rep.Base.XGAgainst),
+			})
+		}
+	}
+	slices.SortStableFunc(rep.Upgrades, func(a, b Upgrade) int {
+		if c := cmp.Compare(b.WinDelta, a.WinDelta); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.XGDelta, a.XGDelta)
+	})
+	return rep, nil
+}
+
+// Point is the lineup's forecast with the swept attribute at Rating.
+type Point struct {
+	Rating   int               `json:"rating"`
+	Forecast forecast.Forecast `json:"forecast"`
+}
+
+// Sweep plays the lineup with one player's attribute set to each of
+// ratings in turn, against opponent or itself as Analyze does, all on the
+// same dice. Rules.Attributes and Rules.Step are not used.
+func Sweep(r *rand.Rand, lineup soccer.GameLineup, opponent *soccer.GameLineup, playerID string, attribute soccer.Attribute, ratings []int, rules Rules) ([]Point, error) {
+	if r == nil {
+		return nil, soccer.ErrNilRandSource
+	}
+	if rules.Simulations < 1 {
+		return nil, fmt.Errorf("%w: need a simulation", ErrInvalidRules)
+	}
+	if !slices.Contains(attributes, attribute) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAttribute, attribute)
+	}
+	i := slices.IndexFunc(lineup.Players, func(p soccer.SelectedPlayer) bool { return p.ID == playerID })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPlayer, playerID)
+	}
+	for _, v := range ratings {
+		if v < 1 || v > MaxRating {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidRating, v)
+		}
+	}
+	against := lineup
+	if opponent != nil {
+		against = *opponent
+	}
+
+	seed := r.Int63()
+	points := make([]Point, 0, len(ratings))
+	for _, v := range ratings {
+		f, err := forecast.Simulate(seed, withRating(lineup, i, attribute, v), against, rules.Simulations)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, Point{Rating: v, Forecast: f})
+	}
+	return points, nil
+}
+
+func (r Rules) validate() error {
+	if len(r.Attributes) == 0 || r.Step < 1 || r.Simulations < 1 {
+		return fmt.Errorf("%w: need an attribute, a step and a simulation", ErrInvalidRules)
+	}
+	for i, a := range r.Attributes {
+		if !slices.Contains(attributes, a) {
+			return fmt.Errorf("%w: %q", ErrUnknownAttribute, a)
+		}
+		if slices.Contains(r.Attributes[:i], a) {
+			return fmt.Errorf("%w: %q twice", ErrInvalidRules, a)
+		}
+	}
+	return nil
+}
+
+// withRating returns lineup with player i's attribute a set to v. The
+// opponent may be the same lineup, so the players are copied.
+func withRating(lineup soccer.GameLineup, i int, a soccer.Attribute, v int) soccer.GameLineup {
+	lineup.Players = slices.Clone(lineup.Players)
+	lineup.Players[i].Attributes = lineup.Players[i].Attributes.WithAttribute(a, v)
+	return lineup
+}
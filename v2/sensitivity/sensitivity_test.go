@@ -0,0 +1,137 @@
+package sensitivity_test
+
+import (
+	"math/rand"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/sensitivity"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rules() sensitivity.Rules {
+	r := sensitivity.DefaultRules()
+	r.Simulations = 300
+	return r
+}
+
+func find(t *testing.T, rep sensitivity.Report, id string, a soccer.Attribute) sensitivity.Upgrade {
+	t.Helper()
+	for _, u := range rep.Upgrades {
+		if u.PlayerID == id && u.Attribute == a {
+			return u
+		}
+	}
+	t.Fatalf("no upgrade for %s %s", id, a)
+	return sensitivity.Upgrade{}
+}
+
+func TestAnalyze_RanksEveryUpgrade(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	rep, err := sensitivity.Analyze(rand.New(rand.NewSource(1)), lineup, nil, rules())
+	require.NoError(t, err)
+
+	assert.Equal(t, lineup, rep.Opponent, "played against itself")
+	require.Len(t, rep.Upgrades, 5*15)
+	for i, u := range rep.Upgrades {
+		assert.Equal(t, u.From+1, u.To)
+		assert.InDelta(t, u.Forecast.Win-rep.Base.Win, u.WinDelta, 1e-12)
+		if i > 0 {
+			prev := rep.Upgrades[i-1]
+			assert.True(t, u.WinDelta < prev.WinDelta || u.WinDelta == prev.WinDelta && u.XGDelta <= prev.XGDelta, "upgrade %d out of order", i)
+		}
+	}
+	assert.Positive(t, rep.Upgrades[0].XGDelta)
+
+	heading := find(t, rep, "5", soccer.AttributeHeading)
+	assert.Equal(t, 93, heading.From, "an unset specialist starts from its fallback")
+	assert.Positive(t, heading.XGDelta)
+	keeping := find(t, rep, "5", soccer.AttributeGoalkeeper)
+	assert.Zero(t, keeping.WinDelta, "the striker never keeps goal")
+	assert.Zero(t, keeping.XGDelta)
+
+	again, err := sensitivity.Analyze(rand.New(rand.NewSource(1)), lineup, nil, rules())
+	require.NoError(t, err)
+	assert.Equal(t, rep, again)
+}
+
+func TestAnalyze_SkipsUpgradesPastTheTop(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	opponent := testdata.WeakTeam(soccer.FormationTypeDiamond)
+	r := rules()
+	r.Attributes = []soccer.Attribute{soccer.AttributeAttack, soccer.AttributeComposure}
+	r.Step = 10
+
+	rep, err := sensitivity.Analyze(rand.New(rand.NewSource(1)), lineup, &opponent, r)
+	require.NoError(t, err)
+
+	assert.Equal(t, opponent, rep.Opponent)
+	for _, u := range rep.Upgrades {
+		assert.LessOrEqual(t, u.To, sensitivity.MaxRating)
+		assert.False(t, u.PlayerID == "5" && u.Attribute == soccer.AttributeAttack, "93 + 10 is past the top")
+	}
+	assert.Len(t, rep.Upgrades, 5*2-3, "the midfielders' and striker's attack are too high")
+}
+
+// The skill curve is convex: each ten points in goal is worth more than the
+// ten below it.
+func TestSweep_KeeperFollowsTheSkillCurve(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	ratings := []int{40, 50, 60, 70, 80, 90, 100}
+	points, err := sensitivity.Sweep(rand.New(rand.NewSource(1)), lineup, nil, "1", soccer.AttributeGoalkeeper, ratings, rules())
+	require.NoError(t, err)
+	require.Len(t, points, len(ratings))
+
+	var lastDrop float64
+	for i := 1; i < len(points); i++ {
+		assert.Equal(t, ratings[i], points[i].Rating)
+		drop := points[i-1].Forecast.XGAgainst - points[i].Forecast.XGAgainst
+		assert.Greater(t, drop, lastDrop, "from %d to %d", ratings[i-1], ratings[i])
+		lastDrop = drop
+	}
+	assert.Greater(t, points[len(points)-1].Forecast.Win, points[0].Forecast.Win)
+}
+
+func TestSweep_StrikerAtTheTopOfTheRange(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	points, err := sensitivity.Sweep(rand.New(rand.NewSource(1)), lineup, nil, "5", soccer.AttributeAttack, []int{70, 80, 90, 100}, rules())
+	require.NoError(t, err)
+	for i := 1; i < len(points); i++ {
+		assert.Greater(t, points[i].Forecast.XGFor, points[i-1].Forecast.XGFor)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	r := rand.New(rand.NewSource(1))
+
+	_, err := sensitivity.Analyze(nil, lineup, nil, rules())
+	assert.ErrorIs(t, err, soccer.ErrNilRandSource)
+
+	bad := rules()
+	bad.Step = 0
+	_, err = sensitivity.Analyze(r, lineup, nil, bad)
+	assert.ErrorIs(t, err, sensitivity.ErrInvalidRules)
+
+	bad = rules()
+	bad.Attributes = []soccer.Attribute{soccer.AttributeHeading, soccer.AttributeHeading}
+	_, err = sensitivity.Analyze(r, lineup, nil, bad)
+	assert.ErrorIs(t, err, sensitivity.ErrInvalidRules)
+
+	bad.Attributes = []soccer.Attribute{"aggression_rating"}
+	_, err = sensitivity.Analyze(r, lineup, nil, bad)
+	assert.ErrorIs(t, err, sensitivity.ErrUnknownAttribute)
+
+	opponent := testdata.WeakTeam(soccer.FormationTypeClassic)
+	_, err = sensitivity.Analyze(r, lineup, &opponent, rules())
+	assert.ErrorIs(t, err, soccer.ErrLineupSize)
+
+	_, err = sensitivity.Sweep(r, lineup, nil, "99", soccer.AttributeAttack, []int{50}, rules())
+	assert.ErrorIs(t, err, sensitivity.ErrUnknownPlayer)
+	_, err = sensitivity.Sweep(r, lineup, nil, "5", "charisma", []int{50}, rules())
+	assert.ErrorIs(t, err, sensitivity.ErrUnknownAttribute)
+	_, err = sensitivity.Sweep(r, lineup, nil, "5", soccer.AttributeAttack, []int{50, 101}, rules())
+	assert.ErrorIs(t, err, sensitivity.ErrInvalidRating)
+}
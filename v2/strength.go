@@ -0,0 +1,37 @@
+package soccer
+
+// Strength is a lineup's intrinsic strength: the team scores the engine
+// derives from the players before a match starts, without item boosts,
+// formation profiles or the opponent's tactics. The scores are on the
+// engine's skill curve, which stretches the gap between good and great
+// players: they only mean anything compared with each other.
+//
+// Strength is the players alone, for comparing squads (it seeds ratings and
+// matchmaking). TeamProfile is the lineup as the engine plays it: its
+// Control and Defense add the formation profile and the captain on top of
+// the same player scores, and its Attack is per chance type. Use
+// AnalyzeLineup to ask how a lineup will play, LineupStrength to ask how
+// good its players are.
+type Strength struct {
+	Control float64 `json:"control"`
+	Defense float64 `json:"defense"`
+	// Attack is the open-play attack of the player a chance is likely to
+	// fall to.
+	Attack float64 `json:"attack"`
+}
+
+// Overall is the mean of the three scores.
+func (s Strength) Overall() float64 {
+	return (s.Control + s.Defense + s.Attack) / 3
+}
+
+// LineupStrength returns the lineup's intrinsic strength under its own
+// tactics, with any injuries the players carry. Unlike AnalyzeLineup it
+// doesn't need a registered formation.
+func LineupStrength(lineup GameLineup) Strength {
+	return Strength{
+		Control: teamControl(lineup),
+		Defense: teamDefense(lineup),
+		Attack:  teamAttack(lineup),
+	}
+}
@@ -0,0 +1,37 @@
+package soccer_test
+
+import (
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineupStrength_RanksStrongAboveWeak(t *testing.T) {
+	for _, f := range []soccer.FormationType{soccer.FormationTypeDiamond, soccer.FormationTypeBox, soccer.FormationTypePyramid} {
+		strong := soccer.LineupStrength(testdata.StrongTeam(f))
+		weak := soccer.LineupStrength(testdata.WeakTeam(f))
+		assert.Greater(t, strong.Control, weak.Control, "%s", f)
+		assert.Greater(t, strong.Defense, weak.Defense, "%s", f)
+		assert.Greater(t, strong.Attack, weak.Attack, "%s", f)
+		assert.Greater(t, strong.Overall(), weak.Overall(), "%s", f)
+	}
+}
+
+func TestLineupStrength_InjuriesWeaken(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	fit := soccer.LineupStrength(lineup)
+
+	var striker string
+	for _, p := range lineup.Players {
+		if p.SelectedPosition == soccer.PlayerPositionAttack {
+			striker = p.ID
+		}
+	}
+	knock := soccer.InjuryEvent{PlayerID: striker, Injury: soccer.Injury{StatsReduction: 0.5}}
+	hurt := soccer.LineupStrength(soccer.ApplyInjuries(lineup, []soccer.InjuryEvent{knock}))
+
+	assert.Less(t, hurt.Attack, fit.Attack)
+	assert.Less(t, hurt.Overall(), fit.Overall())
+}
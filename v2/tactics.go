@@ -1,6 +1,10 @@
 package soccer
 
-import "github.com/stein-f/oink-soccer-common/v2/internal/tuning"
+import (
+	"math"
+
+	"github.com/stein-f/oink-soccer-common/v2/internal/tuning"
+)
 
 // Tactics is the optional bundle of manager-controlled levers a team can
 // set before kick-off. The zero value means "neutral" — every existing v1
@@ -12,7 +16,14 @@ type Tactics struct {
 	Press         PressLevel
 	Tempo         TempoLevel
 	LineHeight    LineHeight
+	Width         Width
+	PassingStyle  PassingStyle
 	SetPieceTaker string // PlayerID — receives free kicks / corners / penalties
+	// MarkPlayerID names an *opposing* player to man-mark. MarkerID names
+	// the own player who does it; empty means the best outfield tackler.
+	// See marking.go.
+	MarkPlayerID string
+	MarkerID     string
 }
 
 // PressLevel controls how much defensive pressure a team applies. Higher
@@ -52,6 +63,34 @@ const (
 	LineHeightHigh   LineHeight = "high"
 )
 
+// Width controls how wide the team plays. A wide team gets more crosses
+// but leaves its own box thinner against the opponent's deliveries; a
+// narrow team plays through the middle and packs the box, but concedes the
+// flanks so the opponent crosses more (see tuning.ChanceMixForWidth and
+// widthAerialDefenseFactor).
+type Width string
+
+const (
+	WidthNone   Width = ""
+	WidthNarrow Width = "narrow"
+	WidthNormal Width = "normal"
+	WidthWide   Width = "wide"
+)
+
+// PassingStyle controls how the team moves the ball. Possession keeps the
+// ball longer (more control, Technique-weighted) but exposes the team to
+// counters; direct gives up control for long-range shots and runs in
+// behind (see tuning.ChanceMixForPassingStyle and
+// passingStyleControlFactor).
+type PassingStyle string
+
+const (
+	PassingStyleNone       PassingStyle = ""
+	PassingStyleDirect     PassingStyle = "direct"
+	PassingStyleBalanced   PassingStyle = "balanced"
+	PassingStylePossession PassingStyle = "possession"
+)
+
 // PlayerRole is an optional tag a manager can apply to a single player to
 // reshape their contribution. Multiple players can share the same role
 // (e.g. two playmakers) — the engine just looks at the field per-player.
@@ -87,8 +126,9 @@ func pressControlFactor(p PressLevel) float64 {
 // pressInjuryFactor returns the multiplier applied to *own* team injury
 // risk. Higher press = more own injuries.
 //
-// Note: injuries are a *next-game* cost (and managers can use recovery items
-// to mitigate them). The press tactic also imposes a within-match cost via
+// Note: an in-match injury only costs the player for the rest of the match;
+// the bigger cost is next-game (managers can use recovery items to mitigate
+// it). The press tactic also imposes a within-match cost via
 // pressFatigueFactor below — without it, high press would be a free lunch.
 func pressInjuryFactor(p PressLevel) float64 {
 	switch p {
@@ -103,6 +143,20 @@ func pressInjuryFactor(p PressLevel) float64 {
 	}
 }
 
+// pressInjuryRamp returns how steeply a team's in-match injury risk climbs
+// through the match (see tuning.InjuryExposure). Pressing teams tire, so
+// more of their knocks come late.
+func pressInjuryRamp(p PressLevel) float64 {
+	switch p {
+	case PressLevelLow:
+		return tuning.InjuryRampLowPress
+	case PressLevelHigh:
+		return tuning.InjuryRampHighPress
+	default: // none, medium
+		return tuning.InjuryRampDefault
+	}
+}
+
 // pressFatigueFactor returns the multiplier applied to a pressing team's
 // *own attack quality* in the final third of the match. Pressing high is
 // physically taxing — the team that's been chasing all game fades after
@@ -198,6 +252,61 @@ func lineHeightDefenseFactor(l LineHeight) float64 {
 	}
 }
 
+// widthAerialDefenseFactor returns the multiplier on a team's defense
+// against crosses and corners. A narrow shape keeps bodies in the box; a
+// wide one pulls defenders out to the touchline.
+func widthAerialDefenseFactor(w Width) float64 {
+	switch w {
+	case WidthNarrow:
+		return 1.06
+	case WidthWide:
+		return 0.95
+	default: // none, normal
+		return 1.0
+	}
+}
+
+// widthAerialAttackFactor returns the multiplier on a team's *own* cross
+// and corner attack. Width gives the crossers time and angles; a narrow
+// side crosses from cramped, central positions.
+func widthAerialAttackFactor(w Width) float64 {
+	switch w {
+	case WidthNarrow:
+		return 0.94
+	case WidthWide:
+		return 1.06
+	default: // none, normal
+		return 1.0
+	}
+}
+
+// passingStyleControlFactor returns the multiplier on a team's *own*
+// control. Possession sides keep the ball; direct sides give it back.
+func passingStyleControlFactor(s PassingStyle) float64 {
+	switch s {
+	case PassingStylePossession:
+		return 1.02
+	case PassingStyleDirect:
+		return 0.98
+	default: // none, balanced
+		return 1.0
+	}
+}
+
+// passingStyleQualityFactor returns the multiplier on a team's own chance
+// quality. Direct play arrives before the defense is set; possession play
+// meets a set, compact block.
+func passingStyleQualityFactor(s PassingStyle) float64 {
+	switch s {
+	case PassingStylePossession:
+		return 0.95
+	case PassingStyleDirect:
+		return 1.06
+	default: // none, balanced
+		return 1.0
+	}
+}
+
 // cornerDeliveryFactor returns the multiplier applied to a corner's
 // effective attack score based on the named SetPieceTaker's Technique.
 // Corner finisher selection is independent (still Heading-driven via
@@ -216,3 +325,169 @@ func cornerDeliveryFactor(lineup GameLineup, ct ChanceType, tactics Tactics) flo
 	}
 	return 1.0
 }
+
+// TacticLever names one of the levers in Tactics.
+type TacticLever string
+
+const (
+	TacticLeverPress        TacticLever = "press"
+	TacticLeverTempo        TacticLever = "tempo"
+	TacticLeverLineHeight   TacticLever = "line_height"
+	TacticLeverWidth        TacticLever = "width"
+	TacticLeverPassingStyle TacticLever = "passing_style"
+)
+
+// TacticTarget is what a TacticEffect scales.
+type TacticTarget string
+
+const (
+	TacticTargetControl         TacticTarget = "control"          // own control
+	TacticTargetOpponentControl TacticTarget = "opponent_control" // the opponent's control
+	TacticTargetDefense         TacticTarget = "defense"          // own defense on every chance
+	TacticTargetAerialDefense   TacticTarget = "aerial_defense"   // own defense on crosses + corners
+	TacticTargetAerialAttack    TacticTarget = "aerial_attack"    // own attack on crosses + corners
+	TacticTargetChanceQuality   TacticTarget = "chance_quality"   // own attack on every chance
+	TacticTargetLateAttack      TacticTarget = "late_attack"      // own attack from FromMinute
+	// The match's chance volume is scaled by the mean of both teams'
+	// factors.
+	TacticTargetChanceVolume TacticTarget = "chance_volume"
+	TacticTargetInjuryRisk   TacticTarget = "injury_risk" // own injury risk
+	// Late injury risk is own injury risk from FromMinute. The match total
+	// is unchanged (that is TacticTargetInjuryRisk): the rest of the match
+	// carries less.
+	TacticTargetLateInjuryRisk TacticTarget = "late_injury_risk"
+	// Weight effects shift which attributes a score is built from: Weights
+	// holds the shifts and Factor is unset.
+	TacticTargetControlWeights TacticTarget = "control_weights" // own control
+	TacticTargetDefenseWeights TacticTarget = "defense_weights" // own outfield defense
+	// Chance mix effects shift the share of each chance type: Mix holds
+	// the factors and Factor is unset.
+	TacticTargetChanceMix         TacticTarget = "chance_mix"          // own chances
+	TacticTargetOpponentChanceMix TacticTarget = "opponent_chance_mix" // the opponent's chances
+)
+
+// TacticEffect is one multiplier or reweighting a tactic setting puts on
+// the match, read from the same functions the engine applies.
+type TacticEffect struct {
+	Lever   TacticLever  `json:"lever"`
+	Setting string       `json:"setting"`
+	Target  TacticTarget `json:"target"`
+	Factor  float64      `json:"factor,omitempty"`
+	Mix     ChanceMix    `json:"mix,omitempty"`
+	// Weights is each attribute's share of the score less its share in
+	// the neutral formula, for the attributes whose share moved: high
+	// press moves a fifth of control from ControlRating to WorkRate.
+	Weights map[Attribute]float64 `json:"weights,omitempty"`
+	// FromMinute is when a late-game effect starts.
+	FromMinute int `json:"from_minute,omitempty"`
+}
+
+// TacticEffects returns every effect of the tactics that isn't neutral,
+// lever by lever in the order of the Tactics fields. The set-piece taker
+// and man-marking depend on the players, so they aren't included.
+func TacticEffects(t Tactics) []TacticEffect {
+	var out []TacticEffect
+	add := func(lever TacticLever, setting string, target TacticTarget, factor float64) {
+		if factor != 1.0 {
+			out = append(out, TacticEffect{Lever: lever, Setting: setting, Target: target, Factor: factor})
+		}
+	}
+	addMix := func(lever TacticLever, setting string, target TacticTarget, mix ChanceMix) {
+		if len(mix) > 0 {
+			out = append(out, TacticEffect{Lever: lever, Setting: setting, Target: target, Mix: mix})
+		}
+	}
+
+	addWeights := func(lever TacticLever, setting string, target TacticTarget, shift map[Attribute]float64) {
+		if len(shift) > 0 {
+			out = append(out, TacticEffect{Lever: lever, Setting: setting, Target: target, Weights: shift})
+		}
+	}
+
+	press := string(t.Press)
+	pressWeights := tuning.ControlWeightsForPress(press)
+	add(TacticLeverPress, press, TacticTargetOpponentControl, pressControlFactor(t.Press))
+	addWeights(TacticLeverPress, press, TacticTargetControlWeights, weightShift(controlShares(tuning.ControlWeightsForPress("")), controlShares(pressWeights)))
+	add(TacticLeverPress, press, TacticTargetInjuryRisk, pressInjuryFactor(t.Press))
+	if f := lateInjuryFactor(t.Press); f != 1.0 {
+		out = append(out, TacticEffect{Lever: TacticLeverPress, Setting: press, Target: TacticTargetLateInjuryRisk, Factor: f, FromMinute: lateGameMinute})
+	}
+	for _, minute := range []int{60, 75} {
+		if f := pressFatigueFactor(t.Press, minute); f != 1.0 {
+			out = append(out, TacticEffect{Lever: TacticLeverPress, Setting: press, Target: TacticTargetLateAttack, Factor: f, FromMinute: minute})
+		}
+	}
+
+	tempo := string(t.Tempo)
+	add(TacticLeverTempo, tempo, TacticTargetChanceVolume, tempoChanceFactor(t.Tempo))
+	add(TacticLeverTempo, tempo, TacticTargetChanceQuality, tempoQualityFactor(t.Tempo))
+	addMix(TacticLeverTempo, tempo, TacticTargetChanceMix, tuning.ChanceMixForTempo(tempo))
+
+	line := string(t.LineHeight)
+	add(TacticLeverLineHeight, line, TacticTargetOpponentControl, lineHeightControlFactor(t.LineHeight))
+	add(TacticLeverLineHeight, line, TacticTargetDefense, lineHeightDefenseFactor(t.LineHeight))
+	addWeights(TacticLeverLineHeight, line, TacticTargetDefenseWeights, weightShift(defenseShares(tuning.DefenseWeightsForLineHeight("")), defenseShares(tuning.DefenseWeightsForLineHeight(line))))
+
+	width := string(t.Width)
+	add(TacticLeverWidth, width, TacticTargetAerialAttack, widthAerialAttackFactor(t.Width))
+	add(TacticLeverWidth, width, TacticTargetAerialDefense, widthAerialDefenseFactor(t.Width))
+	addMix(TacticLeverWidth, width, TacticTargetChanceMix, tuning.ChanceMixForWidth(width))
+	addMix(TacticLeverWidth, width, TacticTargetOpponentChanceMix, tuning.OpponentChanceMixForWidth(width))
+
+	style := string(t.PassingStyle)
+	add(TacticLeverPassingStyle, style, TacticTargetControl, passingStyleControlFactor(t.PassingStyle))
+	addWeights(TacticLeverPassingStyle, style, TacticTargetControlWeights, weightShift(controlShares(pressWeights), controlShares(tuning.ControlWeightsForPassingStyle(style, pressWeights))))
+	add(TacticLeverPassingStyle, style, TacticTargetChanceQuality, passingStyleQualityFactor(t.PassingStyle))
+	addMix(TacticLeverPassingStyle, style, TacticTargetChanceMix, tuning.ChanceMixForPassingStyle(style))
+	addMix(TacticLeverPassingStyle, style, TacticTargetOpponentChanceMix, tuning.OpponentChanceMixForPassingStyle(style))
+	return out
+}
+
+// lateGameMinute is where the late game starts for the injury ramp's
+// effect, as it does for pressFatigueFactor.
+const lateGameMinute = 60
+
+// lateInjuryFactor is how much of a team's injury risk lands in the late
+// game under its press, against the neutral ramp.
+func lateInjuryFactor(p PressLevel) float64 {
+	late := func(ramp float64) float64 {
+		return tuning.InjuryExposure(lateGameMinute, tuning.InjuryMatchMinutes, ramp)
+	}
+	return math.Round(late(pressInjuryRamp(p))/late(tuning.InjuryRampDefault)*100) / 100
+}
+
+// controlShares and defenseShares are each attribute's share of a control
+// or outfield defense score under the weights (see rawControl and
+// rawDefenseForChance).
+func controlShares(w tuning.ControlWeights) map[Attribute]float64 {
+	d := float64(w.Divisor)
+	return map[Attribute]float64{
+		AttributeControl:   float64(w.Skill) / d,
+		AttributeWorkRate:  float64(w.Physical) / d,
+		AttributeTechnique: float64(w.Technique) / d,
+	}
+}
+
+func defenseShares(w tuning.DefenseWeights) map[Attribute]float64 {
+	d := float64(w.Divisor)
+	return map[Attribute]float64{
+		AttributeDefense:  float64(w.Skill) / d,
+		AttributeTackling: float64(w.Tackling) / d,
+		AttributeSpeed:    float64(w.Recovery) / d,
+	}
+}
+
+// weightShift is the change from one set of shares to another, rounded to
+// a thousandth, for the attributes that moved.
+func weightShift(from, to map[Attribute]float64) map[Attribute]float64 {
+	var out map[Attribute]float64
+	for a, share := range to {
+		if d := math.Round((share-from[a])*1000) / 1000; d != 0 {
+			if out == nil {
+				out = make(map[Attribute]float64)
+			}
+			out[a] = d
+		}
+	}
+	return out
+}
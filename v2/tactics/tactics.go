@@ -0,0 +1,368 @@
+// Package tactics recommends tactics against a known opponent.
+//
+// Recommend plays every combination of the chosen levers' settings against
+// the opponent, as they have set up, through the engine: each combination
+// Rules.Simulations times on the same dice (see package forecast), so the
+// ranking reflects the tactics rather than luck. Each option comes with
+// the reasons behind it, read from the multipliers and reweightings the
+// engine applies (soccer.TacticEffects) for both sides, and from the
+// players on each side those effects act on.
+package tactics
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/forecast"
+)
+
+var (
+	ErrInvalidRules = errors.New("tactics: invalid rules")
+	ErrUnknownLever = errors.New("tactics: unknown lever")
+)
+
+// lever is one lever's settings, neutral first, and how to read and set
+// it.
+type lever struct {
+	settings []string
+	get      func(t soccer.Tactics) string
+	set      func(t *soccer.Tactics, setting string)
+}
+
+// levers are the levers Recommend can search. A new lever in
+// soccer.Tactics is added here, and to soccer.TacticEffects for its
+// reasons.
+var levers = map[soccer.TacticLever]lever{
+	soccer.TacticLeverPress: {
+		settings: []string{string(soccer.PressLevelMedium), string(soccer.PressLevelLow), string(soccer.PressLevelHigh)},
+		get:      func(t soccer.Tactics) string { return string(t.Press) },
+		set:      func(t *soccer.Tactics, s string) { t.Press = soccer.PressLevel(s) },
+	},
+	soccer.TacticLeverTempo: {
+		settings: []string{string(soccer.TempoLevelNormal), string(soccer.TempoLevelSlow), string(soccer.TempoLevelFast)},
+		get:      func(t soccer.Tactics) string { return string(t.Tempo) },
+		set:      func(t *soccer.Tactics, s string) { t.Tempo = soccer.TempoLevel(s) },
+	},
+	soccer.TacticLeverLineHeight: {
+		settings: []string{string(soccer.LineHeightNormal), string(soccer.LineHeightDeep), string(soccer.LineHeightHigh)},
+		get:      func(t soccer.Tactics) string { return string(t.LineHeight) },
+		set:      func(t *soccer.Tactics, s string) { t.LineHeight = soccer.LineHeight(s) },
+	},
+	soccer.TacticLeverWidth: {
+		settings: []string{string(soccer.WidthNormal), string(soccer.WidthNarrow), string(soccer.WidthWide)},
+		get:      func(t soccer.Tactics) string { return string(t.Width) },
+		set:      func(t *soccer.Tactics, s string) { t.Width = soccer.Width(s) },
+	},
+	soccer.TacticLeverPassingStyle: {
+		settings: []string{string(soccer.PassingStyleBalanced), string(soccer.PassingStyleDirect), string(soccer.PassingStylePossession)},
+		get:      func(t soccer.Tactics) string { return string(t.PassingStyle) },
+		set:      func(t *soccer.Tactics, s string) { t.PassingStyle = soccer.PassingStyle(s) },
+	},
+}
+
+// Rules are the recommender's search space and budget.
+type Rules struct {
+	// Levers are searched over every setting; the lineup's other tactics
+	// (and its set-piece taker and marking) are kept.
+	Levers      []soccer.TacticLever `json:"levers"`
+	Simulations int                  `json:"simulations"`
+}
+
+// DefaultRules returns the rules the match-prep screen uses: press, tempo
+// and line height, 27 options over 200 matches each.
+func DefaultRules() Rules {
+	return Rules{
+		Levers:      []soccer.TacticLever{soccer.TacticLeverPress, soccer.TacticLeverTempo, soccer.TacticLeverLineHeight},
+		Simulations: 200,
+	}
+}
+
+// Option is one combination of settings and how it fares.
+type Option struct {
+	Tactics  soccer.Tactics    `json:"tactics"`
+	Forecast forecast.Forecast `json:"forecast"`
+	// Current marks the lineup's own settings. A lever left unset counts
+	// as its neutral setting.
+	Current bool `json:"current"`
+	// Reasons explain the option's non-neutral settings, one line per
+	// effect, with the players it acts on and the opponent's settings that
+	// work with or against it.
+	Reasons []string `json:"reasons"`
+}
+
+// Recommend returns every option for lineup against opponent, best first:
+// by win probability, then expected points, then goal difference.
+func Recommend(r *rand.Rand, lineup, opponent soccer.GameLineup, rules Rules) ([]Option, error) {
+	if r == nil {
+		return nil, soccer.ErrNilRandSource
+	}
+	if len(rules.Levers) == 0 || rules.Simulations < 1 {
+		return nil, fmt.Errorf("%w: need a lever and a simulation", ErrInvalidRules)
+	}
+	for i, l := range rules.Levers {
+		if _, ok := levers[l]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownLever, l)
+		}
+		if slices.Contains(rules.Levers[:i], l) {
+			return nil, fmt.Errorf("%w: %q twice", ErrInvalidRules, l)
+		}
+	}
+
+	current := neutralised(lineup.Team.Tactics)
+	seed := r.Int63()
+	var options []Option
+	for _, t := range grid(lineup.Team.Tactics, rules.Levers) {
+		variant := lineup
+		variant.Team.Tactics = t
+		f, err := forecast.Simulate(seed, variant, opponent, rules.Simulations)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, Option{
+			Tactics:  t,
+			Forecast: f,
+			Current:  neutralised(t) == current,
+			Reasons:  reasons(t, lineup, opponent, rules.Levers),
+		})
+	}
+	slices.SortStableFunc(options, func(a, b Option) int {
+		if c := cmp.Compare(b.Forecast.Win, a.Forecast.Win); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Forecast.Points(), a.Forecast.Points()); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Forecast.GoalsFor-b.Forecast.GoalsAgainst, a.Forecast.GoalsFor-a.Forecast.GoalsAgainst)
+	})
+	return options, nil
+}
+
+// grid returns base with every combination of the levers' settings, the
+// first lever varying slowest.
+func grid(base soccer.Tactics, ls []soccer.TacticLever) []soccer.Tactics {
+	out := []soccer.Tactics{base}
+	for _, l := range ls {
+		var next []soccer.Tactics
+		for _, t := range out {
+			for _, s := range levers[l].settings {
+				levers[l].set(&t, s)
+				next = append(next, t)
+			}
+		}
+		out = next
+	}
+	return out
+}
+
+// neutralised returns t with every unset lever at its neutral setting.
+func neutralised(t soccer.Tactics) soccer.Tactics {
+	for _, l := range levers {
+		if l.get(t) == "" {
+			l.set(&t, l.settings[0])
+		}
+	}
+	return t
+}
+
+// related are the opponent's effects that work with or against each of
+// ours.
+var related = map[soccer.TacticTarget][]soccer.TacticTarget{
+	soccer.TacticTargetOpponentControl: {soccer.TacticTargetControl, soccer.TacticTargetControlWeights},
+	soccer.TacticTargetControl:         {soccer.TacticTargetOpponentControl},
+	soccer.TacticTargetDefense:         {soccer.TacticTargetChanceQuality},
+	soccer.TacticTargetChanceQuality:   {soccer.TacticTargetDefense, soccer.TacticTargetDefenseWeights},
+	soccer.TacticTargetAerialAttack:    {soccer.TacticTargetAerialDefense},
+	soccer.TacticTargetAerialDefense:   {soccer.TacticTargetAerialAttack},
+	soccer.TacticTargetChanceVolume:    {soccer.TacticTargetChanceVolume},
+}
+
+// reasons explains t's settings on the searched levers for lineup against
+// the opponent's players and tactics.
+func reasons(t soccer.Tactics, lineup, opponent soccer.GameLineup, ls []soccer.TacticLever) []string {
+	opposing := soccer.TacticEffects(opponent.Team.Tactics)
+	out := []string{}
+	for _, e := range soccer.TacticEffects(t) {
+		if !slices.Contains(ls, e.Lever) {
+			continue
+		}
+		line := describe(e, yours)
+		var context []string
+		if s := squad(e, "your", lineup); s != "" {
+			context = append(context, s)
+		}
+		switch {
+		case e.Target == soccer.TacticTargetOpponentControl:
+			if v, group, ok := average(opponent, soccer.PlayerPositionMidfield, soccer.AttributeControl); ok {
+				context = append(context, fmt.Sprintf("their %s average control %.0f", group, v))
+			}
+		case e.Target == soccer.TacticTargetDefenseWeights && e.Weights[soccer.AttributeSpeed] != 0:
+			if s := paceRace(lineup, opponent); s != "" {
+				context = append(context, s)
+			}
+		}
+		for _, o := range opposing {
+			if slices.Contains(related[e.Target], o.Target) {
+				c := "their " + describe(o, theirs)
+				if s := squad(o, "their", opponent); s != "" {
+					c += ", " + s
+				}
+				context = append(context, c)
+			}
+		}
+		if len(context) > 0 {
+			line += " (" + strings.Join(context, "; ") + ")"
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// yours and theirs name what an effect scales, from the manager's side,
+// for the manager's settings and the opponent's.
+var (
+	yours = map[soccer.TacticTarget]string{
+		soccer.TacticTargetControl:           "your control",
+		soccer.TacticTargetOpponentControl:   "their control",
+		soccer.TacticTargetDefense:           "your defense",
+		soccer.TacticTargetAerialDefense:     "your defense against crosses and corners",
+		soccer.TacticTargetAerialAttack:      "your attack on crosses and corners",
+		soccer.TacticTargetChanceQuality:     "your chance quality",
+		soccer.TacticTargetLateAttack:        "your attack",
+		soccer.TacticTargetChanceVolume:      "chance volume",
+		soccer.TacticTargetInjuryRisk:        "your injury risk",
+		soccer.TacticTargetLateInjuryRisk:    "your injury risk",
+		soccer.TacticTargetControlWeights:    "your control formula",
+		soccer.TacticTargetDefenseWeights:    "your defense formula",
+		soccer.TacticTargetChanceMix:         "your chances",
+		soccer.TacticTargetOpponentChanceMix: "their chances",
+	}
+	theirs = map[soccer.TacticTarget]string{
+		soccer.TacticTargetControl:           "their control",
+		soccer.TacticTargetOpponentControl:   "your control",
+		soccer.TacticTargetDefense:           "their defense",
+		soccer.TacticTargetAerialDefense:     "their defense against crosses and corners",
+		soccer.TacticTargetAerialAttack:      "their attack on crosses and corners",
+		soccer.TacticTargetChanceQuality:     "their chance quality",
+		soccer.TacticTargetLateAttack:        "their attack",
+		soccer.TacticTargetChanceVolume:      "chance volume",
+		soccer.TacticTargetInjuryRisk:        "their injury risk",
+		soccer.TacticTargetLateInjuryRisk:    "their injury risk",
+		soccer.TacticTargetControlWeights:    "their control formula",
+		soccer.TacticTargetDefenseWeights:    "their defense formula",
+		soccer.TacticTargetChanceMix:         "their chances",
+		soccer.TacticTargetOpponentChanceMix: "your chances",
+	}
+)
+
+// describe renders an effect, e.g. "press high: their control ×0.94".
+func describe(e soccer.TacticEffect, names map[soccer.TacticTarget]string) string {
+	head := fmt.Sprintf("%s %s: %s", strings.ReplaceAll(string(e.Lever), "_", " "), e.Setting, names[e.Target])
+	switch {
+	case len(e.Mix) > 0:
+		var parts []string
+		for _, ct := range slices.Sorted(maps.Keys(e.Mix)) {
+			parts = append(parts, fmt.Sprintf("%s ×%.2f", ct, e.Mix[ct]))
+		}
+		return head + " " + strings.Join(parts, ", ")
+	case len(e.Weights) > 0:
+		var parts []string
+		for _, a := range slices.Sorted(maps.Keys(e.Weights)) {
+			parts = append(parts, fmt.Sprintf("%s %+.0f%%", attributeName(a), e.Weights[a]*100))
+		}
+		return head + " " + strings.Join(parts, ", ")
+	case e.FromMinute > 0:
+		return fmt.Sprintf("%s ×%.2f from minute %d", head, e.Factor, e.FromMinute)
+	default:
+		return fmt.Sprintf("%s ×%.2f", head, e.Factor)
+	}
+}
+
+// weightGroups are the players a reweighted score is mostly built from.
+var weightGroups = map[soccer.TacticTarget]soccer.PlayerPosition{
+	soccer.TacticTargetControlWeights: soccer.PlayerPositionMidfield,
+	soccer.TacticTargetDefenseWeights: soccer.PlayerPositionDefense,
+}
+
+// squad names the ratings a reweighting moves for the players it acts on,
+// e.g. "your midfielders average control 85, work rate 80", or "" for an
+// effect that isn't a reweighting.
+func squad(e soccer.TacticEffect, side string, l soccer.GameLineup) string {
+	pos, ok := weightGroups[e.Target]
+	if !ok {
+		return ""
+	}
+	var group string
+	var parts []string
+	for _, a := range slices.Sorted(maps.Keys(e.Weights)) {
+		v, g, ok := average(l, pos, a)
+		if !ok {
+			return ""
+		}
+		group = g
+		parts = append(parts, fmt.Sprintf("%s %.0f", attributeName(a), v))
+	}
+	return fmt.Sprintf("%s %s average %s", side, group, strings.Join(parts, ", "))
+}
+
+// paceRace compares the lineup's defenders' speed with the opponent's
+// attackers', the race a line height moves the defense toward or away
+// from.
+func paceRace(lineup, opponent soccer.GameLineup) string {
+	ours, _, ok := average(lineup, soccer.PlayerPositionDefense, soccer.AttributeSpeed)
+	if !ok {
+		return ""
+	}
+	theirs, group, ok := average(opponent, soccer.PlayerPositionAttack, soccer.AttributeSpeed)
+	if !ok {
+		return ""
+	}
+	verdict := "as quick as your defenders"
+	switch {
+	case math.Round(theirs) > math.Round(ours):
+		verdict = "quicker than your defenders"
+	case math.Round(theirs) < math.Round(ours):
+		verdict = "slower than your defenders"
+	}
+	return fmt.Sprintf("their %s average speed %.0f, %s", group, theirs, verdict)
+}
+
+// positionGroups name the players at each outfield position.
+var positionGroups = map[soccer.PlayerPosition]string{
+	soccer.PlayerPositionDefense:  "defenders",
+	soccer.PlayerPositionMidfield: "midfielders",
+	soccer.PlayerPositionAttack:   "attackers",
+}
+
+// average is the mean rating in attribute a of the lineup's players at
+// pos, and what to call them. A formation with nobody at pos falls back to
+// every outfield player; ok is false when there are none.
+func average(l soccer.GameLineup, pos soccer.PlayerPosition, a soccer.Attribute) (float64, string, bool) {
+	mean := func(match func(p soccer.SelectedPlayer) bool) (float64, bool) {
+		var sum, n int
+		for _, p := range l.Players {
+			if match(p) {
+				sum += p.Attributes.Effective(a)
+				n++
+			}
+		}
+		return float64(sum) / float64(max(n, 1)), n > 0
+	}
+	if v, ok := mean(func(p soccer.SelectedPlayer) bool { return p.SelectedPosition == pos }); ok {
+		return v, positionGroups[pos], true
+	}
+	v, ok := mean(func(p soccer.SelectedPlayer) bool { return p.SelectedPosition != soccer.PlayerPositionGoalkeeper })
+	return v, "outfield players", ok
+}
+
+// attributeName is how a reason names an attribute: "control" for
+// control_rating, "work rate" for work_rate.
+func attributeName(a soccer.Attribute) string {
+	return strings.ReplaceAll(strings.TrimSuffix(string(a), "_rating"), "_", " ")
+}
@@ -0,0 +1,115 @@
+package tactics_test
+
+import (
+	"math/rand"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/tactics"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rules() tactics.Rules {
+	r := tactics.DefaultRules()
+	r.Simulations = 40
+	return r
+}
+
+func TestRecommend_RanksTheGrid(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	lineup.Team.Tactics.SetPieceTaker = "3"
+	opponent := testdata.StrongTeam(soccer.FormationTypeBox)
+
+	options, err := tactics.Recommend(rand.New(rand.NewSource(1)), lineup, opponent, rules())
+	require.NoError(t, err)
+	require.Len(t, options, 27)
+
+	var current int
+	seen := map[soccer.Tactics]bool{}
+	for i, o := range options {
+		assert.Equal(t, "3", o.Tactics.SetPieceTaker, "levers outside the grid are kept")
+		assert.False(t, seen[o.Tactics])
+		seen[o.Tactics] = true
+		if i > 0 {
+			assert.LessOrEqual(t, o.Forecast.Win, options[i-1].Forecast.Win)
+		}
+		if o.Current {
+			current++
+			assert.Equal(t, soccer.TempoLevelNormal, o.Tactics.Tempo, "unset levers are neutral")
+			assert.Empty(t, o.Reasons)
+		}
+	}
+	assert.Equal(t, 1, current)
+
+	again, err := tactics.Recommend(rand.New(rand.NewSource(1)), lineup, opponent, rules())
+	require.NoError(t, err)
+	assert.Equal(t, options, again)
+}
+
+func TestRecommend_ExplainsAgainstTheOpponent(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	opponent := testdata.StrongTeam(soccer.FormationTypeBox)
+	opponent.Team.Tactics = soccer.Tactics{PassingStyle: soccer.PassingStylePossession, Width: soccer.WidthNarrow}
+
+	r := rules()
+	r.Levers = []soccer.TacticLever{soccer.TacticLeverPress, soccer.TacticLeverWidth}
+	options, err := tactics.Recommend(rand.New(rand.NewSource(1)), lineup, opponent, r)
+	require.NoError(t, err)
+	require.Len(t, options, 9)
+
+	for _, o := range options {
+		if o.Tactics.Press == soccer.PressLevelHigh && o.Tactics.Width == soccer.WidthWide {
+			assert.Contains(t, o.Reasons, "press high: their control ×0.94 (their outfield players average control 83; "+
+				"their passing style possession: their control ×1.02; "+
+				"their passing style possession: their control formula control -23%, technique +29%, work rate -6%, their outfield players average control 83, technique 83, work rate 80)")
+			assert.Contains(t, o.Reasons, "press high: your control formula control -20%, work rate +20% (your midfielders average control 85, work rate 80)")
+			assert.Contains(t, o.Reasons, "press high: your injury risk ×1.15 from minute 60")
+			assert.Contains(t, o.Reasons, "press high: your attack ×0.82 from minute 75")
+			assert.Contains(t, o.Reasons, "width wide: your attack on crosses and corners ×1.06 (their width narrow: their defense against crosses and corners ×1.06)")
+			return
+		}
+	}
+	t.Fatal("no high-press, wide option")
+}
+
+// A high line leans the defense on pace; against slow attackers the
+// reasons say so.
+func TestRecommend_ExplainsAgainstTheOpponentsPlayers(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	opponent := testdata.WeakTeam(soccer.FormationTypeDiamond)
+
+	r := rules()
+	r.Levers = []soccer.TacticLever{soccer.TacticLeverLineHeight}
+	options, err := tactics.Recommend(rand.New(rand.NewSource(1)), lineup, opponent, r)
+	require.NoError(t, err)
+
+	for _, o := range options {
+		if o.Tactics.LineHeight == soccer.LineHeightHigh {
+			assert.Contains(t, o.Reasons, "line height high: their control ×0.97 (their midfielders average control 76)")
+			assert.Contains(t, o.Reasons, "line height high: your defense formula defense -25%, speed +25% "+
+				"(your defenders average defense 90, speed 80; their attackers average speed 68, slower than your defenders)")
+			return
+		}
+	}
+	t.Fatal("no high-line option")
+}
+
+func TestRecommend_Errors(t *testing.T) {
+	lineup := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	_, err := tactics.Recommend(nil, lineup, lineup, rules())
+	assert.ErrorIs(t, err, soccer.ErrNilRandSource)
+
+	r := rules()
+	r.Levers = append(r.Levers, "formation")
+	_, err = tactics.Recommend(rand.New(rand.NewSource(1)), lineup, lineup, r)
+	assert.ErrorIs(t, err, tactics.ErrUnknownLever)
+
+	r.Levers = []soccer.TacticLever{soccer.TacticLeverPress, soccer.TacticLeverPress}
+	_, err = tactics.Recommend(rand.New(rand.NewSource(1)), lineup, lineup, r)
+	assert.ErrorIs(t, err, tactics.ErrInvalidRules)
+
+	_, err = tactics.Recommend(rand.New(rand.NewSource(1)), lineup, testdata.StrongTeam(soccer.FormationTypeClassic), rules())
+	assert.ErrorIs(t, err, soccer.ErrLineupSize)
+}
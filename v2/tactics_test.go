@@ -84,6 +84,137 @@ func TestTactics_FastTempoProducesMoreChances(t *testing.T) {
 		"fast tempo should produce more chances; base=%.2f fast=%.2f", chancesBase, chancesFast)
 }
 
+// Width: a wide side must see a larger share of crosses than a narrow one,
+// and the narrow side must pay for it by conceding more crosses, which it
+// then defends better than a wide side would.
+func TestTactics_WidthTradesCrossesForAerialDefense(t *testing.T) {
+	if testing.Short() {
+		t.Skip("tactics impact test runs many trials; skip under -short")
+	}
+
+	const trials = 2000
+	away := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	withWidth := func(w soccer.Width) chanceTally {
+		home := testdata.StrongTeam(soccer.FormationTypeDiamond)
+		home.Team.Tactics = soccer.Tactics{Width: w}
+		return tallyChances(t, trials, home, away)
+	}
+	narrow, normal, wide := withWidth(soccer.WidthNarrow), withWidth(soccer.WidthNone), withWidth(soccer.WidthWide)
+
+	homeCross := func(c chanceTally) float64 {
+		return c.share(soccer.TeamTypeHome, soccer.ChanceTypeCross)
+	}
+	assert.Greater(t, homeCross(wide), homeCross(normal), "wide should create more crosses")
+	assert.Greater(t, homeCross(normal), homeCross(narrow), "narrow should create fewer crosses")
+
+	assert.Greater(t, narrow.shots[soccer.TeamTypeAway][soccer.ChanceTypeCross], normal.shots[soccer.TeamTypeAway][soccer.ChanceTypeCross],
+		"a narrow side concedes the flanks — the opponent should cross more")
+	assert.Less(t, narrow.conversion(soccer.TeamTypeAway, soccer.ChanceTypeCross, soccer.ChanceTypeCorner),
+		wide.conversion(soccer.TeamTypeAway, soccer.ChanceTypeCross, soccer.ChanceTypeCorner),
+		"a narrow side packs the box — opponent deliveries should convert less often than against a wide side")
+}
+
+// PassingStyle: direct play gives up possession for long-range efforts and
+// runs in behind; possession keeps the ball but is caught on the counter.
+func TestTactics_PassingStyleTradesControlForDirectChances(t *testing.T) {
+	if testing.Short() {
+		t.Skip("tactics impact test runs many trials; skip under -short")
+	}
+
+	const trials = 2000
+	away := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	withStyle := func(s soccer.PassingStyle) chanceTally {
+		home := testdata.StrongTeam(soccer.FormationTypeDiamond)
+		home.Team.Tactics = soccer.Tactics{PassingStyle: s}
+		return tallyChances(t, trials, home, away)
+	}
+	direct, balanced, possession := withStyle(soccer.PassingStyleDirect), withStyle(soccer.PassingStyleNone), withStyle(soccer.PassingStylePossession)
+
+	assert.Less(t, direct.total(soccer.TeamTypeHome), balanced.total(soccer.TeamTypeHome), "direct should see less of the ball")
+	assert.Greater(t, possession.total(soccer.TeamTypeHome), balanced.total(soccer.TeamTypeHome), "possession should see more of the ball")
+
+	directShare := func(c chanceTally) float64 {
+		return c.share(soccer.TeamTypeHome, soccer.ChanceTypeLongRange, soccer.ChanceTypeGoalKeeperShot)
+	}
+	assert.Greater(t, directShare(direct), directShare(balanced), "direct should create more long-range and breakaway chances")
+	assert.Less(t, directShare(possession), directShare(balanced), "possession should create fewer long-range and breakaway chances")
+
+	assert.Greater(t, possession.share(soccer.TeamTypeAway, soccer.ChanceTypeGoalKeeperShot), balanced.share(soccer.TeamTypeAway, soccer.ChanceTypeGoalKeeperShot),
+		"possession should leave the team exposed to breakaways")
+}
+
+// MarkPlayerID: shadowing the opponent's star must cut both how often they
+// shoot and how often they score.
+func TestTactics_MarkPlayerIDShadowsTheStar(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping tactics balance test in short mode")
+	}
+	const trials = 2000
+	home := testdata.WeakTeam(soccer.FormationTypeDiamond)
+	away := testdata.WeakTeam(soccer.FormationTypeDiamond)
+	var starID string
+	for i, p := range away.Players {
+		if p.SelectedPosition == soccer.PlayerPositionAttack {
+			away.Players[i].Attributes.AttackRating = 99
+			starID = p.ID
+		}
+	}
+	require.NotEmpty(t, starID)
+
+	starTally := func(home soccer.GameLineup) (shots, goals int) {
+		for i := 0; i < trials; i++ {
+			events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(int64(i))), home, away)
+			require.NoError(t, err)
+			for _, e := range events {
+				if !e.IsChance() {
+					continue
+				}
+				if e.IsGoal() {
+					if e.GetGoalEvent().PlayerID == starID {
+						shots++
+						goals++
+					}
+				} else if e.GetMissEvent().PlayerID == starID {
+					shots++
+				}
+			}
+		}
+		return shots, goals
+	}
+
+	unmarkedShots, unmarkedGoals := starTally(home)
+	marked := cloneLineup(home)
+	marked.Team.Tactics = soccer.Tactics{MarkPlayerID: starID}
+	markedShots, markedGoals := starTally(marked)
+
+	assert.Less(t, markedShots, unmarkedShots, "a marked player should see less of the ball")
+	assert.Less(t, float64(markedGoals)/float64(markedShots), float64(unmarkedGoals)/float64(unmarkedShots),
+		"a marked player should convert less often")
+}
+
+// MarkPlayerID: marking a player who isn't a threat must cost more than it
+// saves. The marker's lost defense (tuning.MarkerDefenseScale) is the price
+// of the job; spent on a centre-back who rarely shoots, it buys nothing.
+func TestTactics_MarkingLowThreatPlayerHurtsWinRate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping tactics balance test in short mode")
+	}
+	const trials = 1500
+	home := testdata.StrongTeam(soccer.FormationTypeDiamond)
+	away := testdata.StrongTeam(soccer.FormationTypeDiamond)
+
+	// Player "2" is the away centre-back: AttackRating 37, no threat.
+	marking := cloneLineup(home)
+	marking.Team.Tactics = soccer.Tactics{MarkPlayerID: "2"}
+
+	winBase := homeWinRate(t, trials, home, away)
+	winMarking := homeWinRate(t, trials, marking, away)
+
+	t.Logf("home win rate: unmarked=%.1f%% | marking the centre-back=%.1f%%", winBase*100, winMarking*100)
+	assert.Less(t, winMarking, winBase,
+		"marking a low-threat player must lose more than it saves")
+}
+
 // SetPieceTaker: every *direct* set-piece chance (free kick / penalty) for a
 // team must go to the named player when the field is populated. Corners are
 // deliberately excluded — for a corner the named taker delivers the ball but
@@ -408,9 +539,18 @@ func TestRole_WeakBallWinnerHurtsWinRate(t *testing.T) {
 
 	winControl := homeWinRate(t, trials, control, away)
 	winWeakBW := homeWinRate(t, trials, weakened, away)
+	marginControl := homeGoalMarginAvg(t, trials, control, away)
+	marginWeakBW := homeGoalMarginAvg(t, trials, weakened, away)
 
 	t.Logf("home win rate: weak-mid no role=%.1f%% | weak-mid Ball Winner=%.1f%%",
 		winControl*100, winWeakBW*100)
+	t.Logf("goal margin/game: weak-mid no role=%.3f | weak-mid Ball Winner=%.3f",
+		marginControl, marginWeakBW)
+
+	// Two costs: the focal-point drag on team defense, and the breakaways a
+	// poor tackler doesn't win (tuning.BallWinnerBreakawayFactor).
+	assert.Less(t, marginWeakBW, marginControl,
+		"tagging the weakest player in their position group as Ball Winner must cost goals, not gain them")
 	assert.Less(t, winWeakBW, winControl,
 		"tagging the weakest player in their position group as Ball Winner must hurt win rate, not help it")
 }
@@ -526,13 +666,126 @@ func awayGoalAvg(t *testing.T, trials int, home, away soccer.GameLineup) float64
 	return float64(total) / float64(trials)
 }
 
+// homeGoalMarginAvg returns the home side's average goals scored minus
+// goals conceded.
+func homeGoalMarginAvg(t *testing.T, trials int, home, away soccer.GameLineup) float64 {
+	t.Helper()
+	var total int
+	for i := 0; i < trials; i++ {
+		events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(int64(i))), home, away)
+		require.NoError(t, err)
+		stats := soccer.CreateGameStats(events)
+		total += stats.HomeTeamStats.Goals - stats.AwayTeamStats.Goals
+	}
+	return float64(total) / float64(trials)
+}
+
 func totalChancesAvg(t *testing.T, trials int, home, away soccer.GameLineup) float64 {
 	t.Helper()
 	var total int
 	for i := 0; i < trials; i++ {
 		events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(int64(i))), home, away)
 		require.NoError(t, err)
-		total += len(events)
+		for _, e := range events {
+			if e.IsChance() {
+				total++
+			}
+		}
 	}
 	return float64(total) / float64(trials)
 }
+
+// chanceTally counts shots and goals per team and chance type.
+type chanceTally struct {
+	shots map[soccer.TeamType]map[soccer.ChanceType]int
+	goals map[soccer.TeamType]map[soccer.ChanceType]int
+}
+
+func tallyChances(t *testing.T, trials int, home, away soccer.GameLineup) chanceTally {
+	t.Helper()
+	c := chanceTally{
+		shots: map[soccer.TeamType]map[soccer.ChanceType]int{soccer.TeamTypeHome: {}, soccer.TeamTypeAway: {}},
+		goals: map[soccer.TeamType]map[soccer.ChanceType]int{soccer.TeamTypeHome: {}, soccer.TeamTypeAway: {}},
+	}
+	for i := 0; i < trials; i++ {
+		events, _, err := soccer.RunGameWithSeed(rand.New(rand.NewSource(int64(i))), home, away)
+		require.NoError(t, err)
+		for _, e := range events {
+			if !e.IsChance() {
+				continue
+			}
+			if e.IsGoal() {
+				team := e.GetGoalEvent().TeamType
+				c.shots[team][e.ChanceType]++
+				c.goals[team][e.ChanceType]++
+			} else {
+				c.shots[e.GetMissEvent().TeamType][e.ChanceType]++
+			}
+		}
+	}
+	return c
+}
+
+func (c chanceTally) total(team soccer.TeamType) int {
+	var n int
+	for _, v := range c.shots[team] {
+		n += v
+	}
+	return n
+}
+
+func (c chanceTally) share(team soccer.TeamType, cts ...soccer.ChanceType) float64 {
+	var n int
+	for _, ct := range cts {
+		n += c.shots[team][ct]
+	}
+	return float64(n) / float64(c.total(team))
+}
+
+func (c chanceTally) conversion(team soccer.TeamType, cts ...soccer.ChanceType) float64 {
+	var shots, goals int
+	for _, ct := range cts {
+		shots += c.shots[team][ct]
+		goals += c.goals[team][ct]
+	}
+	return float64(goals) / float64(shots)
+}
+
+func TestTacticEffects(t *testing.T) {
+	assert.Empty(t, soccer.TacticEffects(soccer.Tactics{}))
+	assert.Empty(t, soccer.TacticEffects(soccer.Tactics{Press: soccer.PressLevelMedium, Tempo: soccer.TempoLevelNormal, LineHeight: soccer.LineHeightNormal}),
+		"the explicit neutrals change nothing")
+
+	effects := soccer.TacticEffects(soccer.Tactics{Press: soccer.PressLevelHigh, Width: soccer.WidthWide})
+	byTarget := map[soccer.TacticTarget][]soccer.TacticEffect{}
+	for _, e := range effects {
+		byTarget[e.Target] = append(byTarget[e.Target], e)
+	}
+	assert.Equal(t, 0.94, byTarget[soccer.TacticTargetOpponentControl][0].Factor)
+	assert.Equal(t, 1.10, byTarget[soccer.TacticTargetInjuryRisk][0].Factor)
+	require.Len(t, byTarget[soccer.TacticTargetLateAttack], 2)
+	assert.Equal(t, 60, byTarget[soccer.TacticTargetLateAttack][0].FromMinute)
+	assert.Equal(t, 0.82, byTarget[soccer.TacticTargetLateAttack][1].Factor)
+	assert.Equal(t, soccer.TacticLeverWidth, byTarget[soccer.TacticTargetAerialAttack][0].Lever)
+	assert.Greater(t, byTarget[soccer.TacticTargetChanceMix][0].Mix["Cross"], 1.0)
+	assert.Less(t, byTarget[soccer.TacticTargetOpponentChanceMix][0].Mix["Cross"], 1.0)
+
+	// The reweightings are shifts in each attribute's share of the score.
+	assert.Equal(t, map[soccer.Attribute]float64{soccer.AttributeControl: -0.2, soccer.AttributeWorkRate: 0.2},
+		byTarget[soccer.TacticTargetControlWeights][0].Weights, "high press moves a fifth of control onto work rate")
+	require.Len(t, byTarget[soccer.TacticTargetLateInjuryRisk], 1)
+	assert.Greater(t, byTarget[soccer.TacticTargetLateInjuryRisk][0].Factor, 1.0, "pressing teams pick up more of their knocks late")
+
+	for _, e := range soccer.TacticEffects(soccer.Tactics{LineHeight: soccer.LineHeightHigh, PassingStyle: soccer.PassingStylePossession}) {
+		switch e.Target {
+		case soccer.TacticTargetDefenseWeights:
+			assert.Positive(t, e.Weights[soccer.AttributeSpeed], "a high line leans on recovery pace")
+			assert.Negative(t, e.Weights[soccer.AttributeDefense])
+		case soccer.TacticTargetControlWeights:
+			assert.Positive(t, e.Weights[soccer.AttributeTechnique], "possession brings Technique into control")
+		}
+	}
+	for _, e := range soccer.TacticEffects(soccer.Tactics{PassingStyle: soccer.PassingStyleDirect}) {
+		assert.NotEqual(t, soccer.TacticTargetControlWeights, e.Target, "direct play leaves the control formula alone")
+	}
+}
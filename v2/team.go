@@ -13,4 +13,7 @@ type GameLineup struct {
 	Team       Team             `json:"team"`
 	Players    []SelectedPlayer `json:"players"`
 	ItemBoosts []Boost          `json:"item_boosts"`
+	// Shootout is optional — only RunShootoutWithSeed reads it. The zero
+	// value takes kicks in the default order with the lineup's goalkeeper.
+	Shootout ShootoutPlan `json:"shootout,omitzero"`
 }
@@ -4,6 +4,7 @@
 package testdata
 
 import (
+	"fmt"
 	"strconv"
 
 	soccer "github.com/stein-f/oink-soccer-common/v2"
@@ -11,9 +12,10 @@ import (
 
 // Lineups are built from the formation's actual slot layout — the same way
 // lost-pigs builds production lineups — so a Box fixture really fields
-// GK/DEF/DEF/ATK/ATK. Each slot is filled with the archetype stat line for
-// its position. Player IDs are "1".."5" by slot (StrongTeam) and "6".."10"
-// (WeakTeam) to keep golden snapshots readable.
+// GK/DEF/DEF/ATK/ATK and an eleven-a-side Classic fields 1-4-4-2. Each slot
+// is filled with the archetype stat line for its position. Player IDs are
+// "1".."n" by slot (StrongTeam) and "n+1".."2n" (WeakTeam) to keep golden
+// snapshots readable.
 
 // stat lines: gk, def, ctrl, atk, speed
 type statLine struct{ gk, def, ctrl, atk, speed int }
@@ -32,8 +34,8 @@ var weakStats = map[soccer.PlayerPosition]statLine{
 	soccer.PlayerPositionAttack:     {14, 22, 67, 74, 68},
 }
 
-// StrongTeam returns a 5-player squad with high overall ratings, shaped to
-// the given formation's slots.
+// StrongTeam returns a squad with high overall ratings, one player per slot
+// of the given formation.
 func StrongTeam(formation soccer.FormationType) soccer.GameLineup {
 	return teamForFormation("strong", formation, strongStats, 0)
 }
@@ -41,13 +43,36 @@ func StrongTeam(formation soccer.FormationType) soccer.GameLineup {
 // WeakTeam returns a deliberately mediocre squad — used as a foil for
 // "stronger team should win more often" smoke tests.
 func WeakTeam(formation soccer.FormationType) soccer.GameLineup {
-	return teamForFormation("weak", formation, weakStats, 5)
+	return teamForFormation("weak", formation, weakStats, formationConfig(formation).TeamSize())
+}
+
+// BlockHash is a real Algorand block hash, for seeding competitions.
+const BlockHash = "VVMG2PTT6YGDPSF3YKC4AEZUYH5UNLDLZNMSBR24MQ6ZD7Y7IWNA"
+
+// Field returns n team IDs, prefix followed by "01".."n", and a lineup for
+// each: the first strong of them StrongTeam, the rest WeakTeam. Formations
+// cycle through The Diamond, The Box, The Y and The Pyramid so a
+// competition sees every style.
+func Field(prefix string, n, strong int) ([]string, map[string]soccer.GameLineup) {
+	formations := []soccer.FormationType{soccer.FormationTypeDiamond, soccer.FormationTypeBox, soccer.FormationTypeY, soccer.FormationTypePyramid}
+	ids := make([]string, n)
+	lineups := make(map[string]soccer.GameLineup, n)
+	for i := range ids {
+		id := fmt.Sprintf("%s%02d", prefix, i+1)
+		lineup := StrongTeam(formations[i%len(formations)])
+		if i >= strong {
+			lineup = WeakTeam(formations[i%len(formations)])
+		}
+		lineup.Team.ID = id
+		ids[i], lineups[id] = id, lineup
+	}
+	return ids, lineups
 }
 
 func teamForFormation(teamID string, formation soccer.FormationType, stats map[soccer.PlayerPosition]statLine, idOffset int) soccer.GameLineup {
 	config := formationConfig(formation)
-	players := make([]soccer.SelectedPlayer, 0, 5)
-	for slot := uint64(1); slot <= 5; slot++ {
+	players := make([]soccer.SelectedPlayer, 0, config.TeamSize())
+	for slot := uint64(1); slot <= uint64(config.TeamSize()); slot++ {
 		pos := config.Slots[slot]
 		players = append(players, player(strconv.Itoa(int(slot)+idOffset), pos, stats[pos]))
 	}
@@ -57,17 +82,14 @@ func teamForFormation(teamID string, formation soccer.FormationType, stats map[s
 	}
 }
 
+// formationConfig returns the registered formation, falling back to The
+// Diamond for unknown names.
 func formationConfig(formation soccer.FormationType) soccer.FormationConfig {
-	switch formation {
-	case soccer.FormationTypePyramid:
-		return soccer.ThePyramidFormation
-	case soccer.FormationTypeY:
-		return soccer.TheYFormation
-	case soccer.FormationTypeBox:
-		return soccer.TheBoxFormation
-	default:
+	config, err := soccer.LookupFormation(formation)
+	if err != nil {
 		return soccer.TheDiamondFormation
 	}
+	return config
 }
 
 func player(id string, pos soccer.PlayerPosition, s statLine) soccer.SelectedPlayer {
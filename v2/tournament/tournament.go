@@ -0,0 +1,267 @@
+// Package tournament runs World-Cup-style events: a group stage of
+// round-robin groups, then the top finishers of each group in a seeded
+// knockout bracket. It is built from the league package (group fixtures and
+// tables) and the cup package (the knockout), and plays every match through
+// the engine.
+//
+// A tournament is a deterministic function of (teams, block hash, rules,
+// lineups). Each random stream is derived from the one master block hash
+// and a label, so every step can be re-verified on its own:
+//
+//	<hash>/draw                the group draw
+//	<hash>/<group>/schedule    a group's fixture list
+//	<hash>/<group>/lot         a group's drawing of lots
+//	<hash>/<group>-R<n>-<m>    a group match
+//	<hash>/R<n>-<m>            a knockout tie (see cup.TieSource)
+//
+// The Tournament value holds everything played so far and round-trips
+// through JSON, so rounds can be played on different days: load it, call
+// PlayRound with that day's lineups, save it.
+package tournament
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/algorand"
+	"github.com/stein-f/oink-soccer-common/v2/cup"
+	"github.com/stein-f/oink-soccer-common/v2/internal/roundrobin"
+	"github.com/stein-f/oink-soccer-common/v2/league"
+)
+
+var (
+	ErrInvalidRules  = errors.New("tournament: invalid rules")
+	ErrTooFewTeams   = errors.New("tournament: not enough teams for the groups")
+	ErrDuplicateTeam = errors.New("tournament: duplicate team")
+	ErrComplete      = errors.New("tournament: every round has been played")
+	// ErrMissingLineup is league's sentinel, returned for cup matches too.
+	ErrMissingLineup = league.ErrMissingLineup
+)
+
+// Rules are the dials of a tournament.
+type Rules struct {
+	// Groups is the number of groups, at most 26 (named A to Z).
+	Groups int `json:"groups"`
+	// Advance is how many teams from each group go through.
+	Advance int `json:"advance"`
+	// Group is how each group is scheduled and ranked.
+	Group league.Rules `json:"group"`
+}
+
+// DefaultRules returns the classic format: four groups, each a single
+// round-robin, with the top two of each going through to the
+// quarter-finals.
+func DefaultRules() Rules {
+	group := league.DefaultRules()
+	group.Format = league.FormatSingle
+	return Rules{Groups: 4, Advance: 2, Group: group}
+}
+
+// Tournament is a tournament's whole state.
+type Tournament struct {
+	BlockHash string  `json:"block_hash"`
+	Rules     Rules   `json:"rules"`
+	Groups    []Group `json:"groups"`
+	// Knockout is drawn once every group match has been played.
+	Knockout *cup.Bracket `json:"knockout,omitempty"`
+}
+
+// Group is one group of the group stage.
+type Group struct {
+	Name      string            `json:"name"`
+	Teams     []string          `json:"teams"`
+	Rounds    []league.Round    `json:"rounds"`
+	Matches   []Match           `json:"matches"`
+	Standings []league.Standing `json:"standings"`
+}
+
+// group is the group as roundrobin plays it.
+func (g Group) group() roundrobin.Group {
+	return roundrobin.Group{Name: g.Name, Teams: g.Teams, Rounds: g.Rounds, Matches: g.Matches}
+}
+
+// Match is a played group match. Its ID is "<group>-R<round>-<fixture>",
+// both 1-based.
+type Match = league.Match
+
+// New draws a tournament for teams listed in seed order, the top seed
+// first. Teams are drawn from pots of Groups teams each, one per group, so
+// the top seeds are kept apart; the seed decides which group each team of a
+// pot lands in. Nothing is played.
+func New(teams []string, blockHash string, rules Rules) (*Tournament, error) {
+	if rules.Groups < 1 || rules.Groups > 26 || rules.Advance < 1 {
+		return nil, fmt.Errorf("%w: %d groups, %d advancing", ErrInvalidRules, rules.Groups, rules.Advance)
+	}
+	smallest := len(teams) / rules.Groups
+	if smallest < 2 || smallest < rules.Advance || rules.Groups*rules.Advance < 2 {
+		return nil, fmt.Errorf("%w: %d teams in %d groups, %d advancing", ErrTooFewTeams, len(teams), rules.Groups, rules.Advance)
+	}
+	seen := make(map[string]bool, len(teams))
+	for _, id := range teams {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateTeam, id)
+		}
+		seen[id] = true
+	}
+
+	t := &Tournament{BlockHash: blockHash, Rules: rules, Groups: make([]Group, rules.Groups)}
+	draw := t.source("draw")
+	for pot := 0; pot < len(teams); pot += rules.Groups {
+		members := slices.Clone(teams[pot:min(pot+rules.Groups, len(teams))])
+		draw.Shuffle(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] })
+		for g, id := range members {
+			t.Groups[g].Teams = append(t.Groups[g].Teams, id)
+		}
+	}
+	for g := range t.Groups {
+		group := &t.Groups[g]
+		group.Name = string(rune('A' + g))
+		rounds, err := league.Schedule(t.source(group.Name+"/schedule"), group.Teams, rules.Group)
+		if err != nil {
+			return nil, fmt.Errorf("tournament: group %s: %w", group.Name, err)
+		}
+		group.Rounds = rounds
+		if group.Standings, err = roundrobin.Rank(group.group(), rules.Group, t.source); err != nil {
+			return nil, fmt.Errorf("tournament: group %w", err)
+		}
+	}
+	return t, nil
+}
+
+func (t *Tournament) source(label string) *rand.Rand {
+	return algorand.SeedFromBlockHash(t.BlockHash + "/" + label)
+}
+
+func (t *Tournament) groups() []roundrobin.Group {
+	groups := make([]roundrobin.Group, len(t.Groups))
+	for i, g := range t.Groups {
+		groups[i] = g.group()
+	}
+	return groups
+}
+
+// Matchday returns how many group-stage matchdays have been played.
+func (t *Tournament) Matchday() int {
+	return roundrobin.Matchday(t.groups())
+}
+
+// matchdays is the length of the group stage: groups of different sizes
+// have different numbers of rounds.
+func (t *Tournament) matchdays() int {
+	return roundrobin.Matchdays(t.groups())
+}
+
+// Done reports whether the tournament is over.
+func (t *Tournament) Done() bool {
+	return t.Knockout != nil && t.Knockout.Next() < 0
+}
+
+// Winner returns the champion, "" until the final is played.
+func (t *Tournament) Winner() string {
+	if t.Knockout == nil {
+		return ""
+	}
+	return t.Knockout.Winner
+}
+
+// PlayRound plays the next round: a group matchday across every group, or
+// a knockout round. The knockout is drawn as soon as the last group
+// matchday is in. lineups are keyed by team ID and only need to cover the
+// teams playing this round.
+func (t *Tournament) PlayRound(lineups map[string]soccer.GameLineup) error {
+	if t.Done() {
+		return ErrComplete
+	}
+	if t.Knockout != nil {
+		return t.Knockout.PlayRound(lineups, t.BlockHash)
+	}
+
+	played, err := roundrobin.PlayMatchday(t.groups(), lineups, t.Rules.Group, t.source)
+	if err != nil {
+		return fmt.Errorf("tournament: %w", err)
+	}
+	for g, p := range played {
+		t.Groups[g].Matches, t.Groups[g].Standings = p.Matches, p.Standings
+	}
+	if t.Matchday() == t.matchdays() {
+		return t.drawKnockout()
+	}
+	return nil
+}
+
+// Play plays every remaining round.
+func (t *Tournament) Play(lineups map[string]soccer.GameLineup) error {
+	for !t.Done() {
+		if err := t.PlayRound(lineups); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawKnockout seeds the qualifiers into a cup bracket. Group winners are
+// seeded above runners-up, and so on down; within each finishing position
+// the better record (points, goal difference, goals) is seeded higher.
+// Where the standard pairings would put two teams from the same group
+// together in the first round, the lower-seeded team swaps places with the
+// first team of the same finishing position that clears the clash without
+// creating another. With a single group a rematch can't be avoided.
+func (t *Tournament) drawKnockout() error {
+	type qualifier struct {
+		id       string
+		group    int
+		position int
+		row      league.Standing
+	}
+	var qs []qualifier
+	for g, group := range t.Groups {
+		for pos := 0; pos < t.Rules.Advance; pos++ {
+			qs = append(qs, qualifier{id: group.Standings[pos].TeamID, group: g, position: pos, row: group.Standings[pos]})
+		}
+	}
+	slices.SortStableFunc(qs, func(a, b qualifier) int {
+		return cmp.Or(
+			cmp.Compare(a.position, b.position),
+			cmp.Compare(b.row.Points, a.row.Points),
+			cmp.Compare(b.row.GoalDifference(), a.row.GoalDifference()),
+			cmp.Compare(b.row.GoalsFor, a.row.GoalsFor),
+		)
+	})
+
+	size := 1
+	for size < len(qs) {
+		size *= 2
+	}
+	partner := func(i int) int { return size - 1 - i } // 0-based seeds
+	for s := range qs {
+		p := partner(s)
+		if p <= s || p >= len(qs) || qs[s].group != qs[p].group {
+			continue
+		}
+		for u := range qs {
+			v := partner(u)
+			if u == p || u == s || qs[u].position != qs[p].position || v >= len(qs) || v == s {
+				continue
+			}
+			if qs[u].group != qs[s].group && qs[p].group != qs[v].group {
+				qs[p], qs[u] = qs[u], qs[p]
+				break
+			}
+		}
+	}
+
+	seeds := make([]string, len(qs))
+	for i, q := range qs {
+		seeds[i] = q.id
+	}
+	bracket, err := cup.Draw(seeds)
+	if err != nil {
+		return fmt.Errorf("tournament: knockout: %w", err)
+	}
+	t.Knockout = bracket
+	return nil
+}
@@ -0,0 +1,182 @@
+package tournament_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stein-f/oink-soccer-common/v2/tournament"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func groupOf(tm *tournament.Tournament) map[string]string {
+	out := make(map[string]string)
+	for _, g := range tm.Groups {
+		for _, id := range g.Teams {
+			out[id] = g.Name
+		}
+	}
+	return out
+}
+
+func TestNew_DrawsFromPots(t *testing.T) {
+	teams, _ := testdata.Field("seed-", 16, 8)
+
+	tm, err := tournament.New(teams, testdata.BlockHash, tournament.DefaultRules())
+	require.NoError(t, err)
+
+	require.Len(t, tm.Groups, 4)
+	groups := groupOf(tm)
+	for pot := 0; pot < 16; pot += 4 {
+		seen := map[string]bool{}
+		for _, id := range teams[pot : pot+4] {
+			assert.False(t, seen[groups[id]], "two teams from pot %d in group %s", pot/4+1, groups[id])
+			seen[groups[id]] = true
+		}
+	}
+	for _, g := range tm.Groups {
+		assert.Len(t, g.Teams, 4)
+		assert.Len(t, g.Rounds, 3)
+		assert.Len(t, g.Standings, 4)
+	}
+
+	again, err := tournament.New(teams, testdata.BlockHash, tournament.DefaultRules())
+	require.NoError(t, err)
+	assert.Equal(t, tm, again)
+}
+
+func TestPlay_GroupsThenKnockout(t *testing.T) {
+	teams, lineups := testdata.Field("seed-", 16, 8)
+	tm, err := tournament.New(teams, testdata.BlockHash, tournament.DefaultRules())
+	require.NoError(t, err)
+
+	for day := 1; day <= 3; day++ {
+		require.NoError(t, tm.PlayRound(lineups))
+		assert.Equal(t, day, tm.Matchday())
+	}
+	require.NotNil(t, tm.Knockout, "the knockout is drawn after the last matchday")
+	assert.Equal(t, "Quarter-finals", tm.Knockout.Rounds[0].Name)
+
+	qualified := map[string]bool{}
+	for _, g := range tm.Groups {
+		assert.Len(t, g.Matches, 6)
+		for _, s := range g.Standings {
+			assert.Equal(t, 3, s.Played)
+		}
+		qualified[g.Standings[0].TeamID] = true
+		qualified[g.Standings[1].TeamID] = true
+	}
+	groups := groupOf(tm)
+	for _, tie := range tm.Knockout.Rounds[0].Ties {
+		assert.True(t, qualified[tie.Home])
+		assert.True(t, qualified[tie.Away])
+		assert.NotEqual(t, groups[tie.Home], groups[tie.Away], "%s is a group rematch", tie.ID)
+	}
+
+	require.NoError(t, tm.Play(lineups))
+	assert.True(t, tm.Done())
+	assert.True(t, qualified[tm.Winner()])
+	assert.ErrorIs(t, tm.PlayRound(lineups), tournament.ErrComplete)
+}
+
+func TestPlay_NoFirstRoundRematches(t *testing.T) {
+	for _, tc := range []struct{ teams, groups, advance int }{
+		{8, 2, 2},
+		{10, 3, 2},
+		{12, 3, 2},
+		{12, 3, 3},
+		{15, 5, 1},
+		{24, 6, 2},
+	} {
+		t.Run(fmt.Sprintf("%d teams, %d groups, top %d", tc.teams, tc.groups, tc.advance), func(t *testing.T) {
+			teams, lineups := testdata.Field("seed-", tc.teams, tc.teams/2)
+			rules := tournament.DefaultRules()
+			rules.Groups, rules.Advance = tc.groups, tc.advance
+			tm, err := tournament.New(teams, testdata.BlockHash, rules)
+			require.NoError(t, err)
+
+			for tm.Knockout == nil {
+				require.NoError(t, tm.PlayRound(lineups))
+			}
+
+			groups := groupOf(tm)
+			var places int
+			for _, tie := range tm.Knockout.Rounds[0].Ties {
+				if tie.Bye {
+					places++
+					continue
+				}
+				places += 2
+				assert.NotEqual(t, groups[tie.Home], groups[tie.Away], "%s is a group rematch", tie.ID)
+			}
+			assert.Equal(t, tc.groups*tc.advance, places)
+		})
+	}
+}
+
+func TestPlay_ResumesFromJSON(t *testing.T) {
+	teams, lineups := testdata.Field("seed-", 8, 4)
+	rules := tournament.DefaultRules()
+	rules.Groups = 2
+
+	straight, err := tournament.New(teams, testdata.BlockHash, rules)
+	require.NoError(t, err)
+	require.NoError(t, straight.Play(lineups))
+
+	resumed, err := tournament.New(teams, testdata.BlockHash, rules)
+	require.NoError(t, err)
+	for !resumed.Done() {
+		body, err := json.Marshal(resumed)
+		require.NoError(t, err)
+		var loaded tournament.Tournament
+		require.NoError(t, json.Unmarshal(body, &loaded))
+		require.NoError(t, loaded.PlayRound(lineups))
+		resumed = &loaded
+	}
+
+	assert.Equal(t, straight.Winner(), resumed.Winner())
+	for i := range straight.Groups {
+		assert.Equal(t, straight.Groups[i].Standings, resumed.Groups[i].Standings)
+	}
+	assert.Equal(t, straight.Knockout.Rounds[len(straight.Knockout.Rounds)-1].Ties[0].Home,
+		resumed.Knockout.Rounds[len(resumed.Knockout.Rounds)-1].Ties[0].Home)
+}
+
+func TestPlayRound_FailureLeavesTheMatchdayUnplayed(t *testing.T) {
+	teams, lineups := testdata.Field("seed-", 8, 4)
+	rules := tournament.DefaultRules()
+	rules.Groups = 2
+	tm, err := tournament.New(teams, testdata.BlockHash, rules)
+	require.NoError(t, err)
+
+	missing := tm.Groups[1].Rounds[0].Fixtures[0].Home
+	partial := make(map[string]soccer.GameLineup, len(lineups))
+	for id, l := range lineups {
+		if id != missing {
+			partial[id] = l
+		}
+	}
+
+	assert.ErrorIs(t, tm.PlayRound(partial), tournament.ErrMissingLineup)
+	assert.Equal(t, 0, tm.Matchday())
+	assert.Empty(t, tm.Groups[0].Matches)
+
+	require.NoError(t, tm.PlayRound(lineups))
+	assert.Equal(t, 1, tm.Matchday())
+}
+
+func TestNew_Errors(t *testing.T) {
+	teams, _ := testdata.Field("seed-", 8, 4)
+
+	_, err := tournament.New(teams, testdata.BlockHash, tournament.Rules{Groups: 0, Advance: 2})
+	assert.ErrorIs(t, err, tournament.ErrInvalidRules)
+
+	_, err = tournament.New(teams[:6], testdata.BlockHash, tournament.DefaultRules())
+	assert.ErrorIs(t, err, tournament.ErrTooFewTeams)
+
+	_, err = tournament.New(append(teams, teams[0]), testdata.BlockHash, tournament.DefaultRules())
+	assert.ErrorIs(t, err, tournament.ErrDuplicateTeam)
+}
@@ -0,0 +1,257 @@
+// Package whatif answers "would we have won with X instead of Y?" for a
+// match that has been played.
+//
+// Analyze makes one change to one side's lineup: a player swapped, the
+// tactics changed, or a new formation. It then answers in two ways. The
+// replay plays the match again under its own seed. The engine takes its
+// draws in a fixed order (see soccer.RunGameWithSeed), so the replay sees
+// the original's dice as long as the change doesn't add or remove a draw.
+// Report.InStep says whether it did; when it is false the replay is a
+// different match from the point the draws shifted, and only the forecast
+// means much. The forecast plays both versions over many seeds on the same
+// dice (see package forecast) and reports the difference in win
+// probability, which is the answer that doesn't depend on one match's luck.
+package whatif
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/forecast"
+)
+
+var (
+	ErrInvalidChange = errors.New("whatif: invalid change")
+	ErrUnknownPlayer = errors.New("whatif: unknown player")
+)
+
+// Change is one modification to one side. Exactly one of the swap (Out
+// and In), Tactics or Formation is set.
+type Change struct {
+	Team soccer.TeamType `json:"team"`
+	// In replaces the player Out, taking their position, role and any
+	// set-piece or marking duty.
+	Out string                 `json:"out,omitempty"`
+	In  *soccer.SelectedPlayer `json:"in,omitempty"`
+	// Tactics replaces the side's tactics.
+	Tactics *soccer.Tactics `json:"tactics,omitempty"`
+	// Formation replaces the side's formation; Positions moves players to
+	// fit it, by player ID. Players it doesn't name keep their position.
+	Formation soccer.FormationType             `json:"formation,omitempty"`
+	Positions map[string]soccer.PlayerPosition `json:"positions,omitempty"`
+}
+
+// Apply returns the two lineups with the change made. The inputs are not
+// modified.
+func (c Change) Apply(home, away soccer.GameLineup) (soccer.GameLineup, soccer.GameLineup, error) {
+	var set int
+	for _, ok := range []bool{c.Out != "" || c.In != nil, c.Tactics != nil, c.Formation != ""} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return home, away, fmt.Errorf("%w: need exactly one of a swap, tactics or a formation", ErrInvalidChange)
+	}
+	if c.Positions != nil && c.Formation == "" {
+		return home, away, fmt.Errorf("%w: positions without a formation", ErrInvalidChange)
+	}
+
+	lineup := &home
+	switch c.Team {
+	case soccer.TeamTypeHome:
+	case soccer.TeamTypeAway:
+		lineup = &away
+	default:
+		return home, away, fmt.Errorf("%w: team %q", ErrInvalidChange, c.Team)
+	}
+	lineup.Players = slices.Clone(lineup.Players)
+
+	switch {
+	case c.Tactics != nil:
+		lineup.Team.Tactics = *c.Tactics
+	case c.Formation != "":
+		lineup.Team.Formation = c.Formation
+		for id, pos := range c.Positions {
+			i := slices.IndexFunc(lineup.Players, func(p soccer.SelectedPlayer) bool { return p.ID == id })
+			if i < 0 {
+				return home, away, fmt.Errorf("%w: %q", ErrUnknownPlayer, id)
+			}
+			lineup.Players[i].SelectedPosition = pos
+		}
+	default:
+		if c.Out == "" || c.In == nil {
+			return home, away, fmt.Errorf("%w: a swap needs a player out and a player in", ErrInvalidChange)
+		}
+		i := slices.IndexFunc(lineup.Players, func(p soccer.SelectedPlayer) bool { return p.ID == c.Out })
+		if i < 0 {
+			return home, away, fmt.Errorf("%w: %q", ErrUnknownPlayer, c.Out)
+		}
+		if slices.ContainsFunc(lineup.Players, func(p soccer.SelectedPlayer) bool { return p.ID == c.In.ID }) {
+			return home, away, fmt.Errorf("%w: %q is already playing", ErrInvalidChange, c.In.ID)
+		}
+		in := *c.In
+		in.SelectedPosition, in.Role = lineup.Players[i].SelectedPosition, lineup.Players[i].Role
+		lineup.Players[i] = in
+		t := &lineup.Team.Tactics
+		if t.SetPieceTaker == c.Out {
+			t.SetPieceTaker = in.ID
+		}
+		if t.MarkerID == c.Out {
+			t.MarkerID = in.ID
+		}
+	}
+	return home, away, nil
+}
+
+// Rules are the analysis's budget.
+type Rules struct {
+	// Simulations is how many seeds each version plays for the forecast.
+	Simulations int `json:"simulations"`
+}
+
+// DefaultRules returns the rules the post-match screen uses: 1000 seeds.
+func DefaultRules() Rules {
+	return Rules{Simulations: 1000}
+}
+
+// Replay is one match played under the match's seed.
+type Replay struct {
+	Events   []soccer.GameEvent `json:"events"`
+	Injuries soccer.Injuries    `json:"injuries"`
+	Stats    soccer.GameStats   `json:"stats"`
+}
+
+// Report is the what-if analysis, from the changed side's view.
+type Report struct {
+	Change Change `json:"change"`
+	// Original and Changed are the match and its replay under the same
+	// seed.
+	Original Replay `json:"original"`
+	Changed  Replay `json:"changed"`
+	// InStep reports whether the replay made the same draws in the same
+	// order as the original, so that every difference between them is the
+	// change's doing. A change of tempo or formation style that changes
+	// the number of chances, a set-piece taker leaving or joining the
+	// pitch, or an injury in one match and not the other puts the replay
+	// out of step. A replay in step can still hand a chance to a different
+	// player: the engine picks attackers in ID order, so a swapped-in
+	// player whose ID sorts elsewhere in the lineup moves the picks.
+	InStep bool `json:"in_step"`
+	// Before and After are the forecasts without and with the change.
+	Before forecast.Forecast `json:"before"`
+	After  forecast.Forecast `json:"after"`
+	// WinDelta is After.Win − Before.Win.
+	WinDelta float64 `json:"win_delta"`
+}
+
+// Analyze replays the match with the change and forecasts both versions.
+// source returns a fresh stream for the match's seed each time it's
+// called, e.g. func() *rand.Rand { return algorand.SeedFromBlockHash(h) }.
+// The forecasts are seeded from the same stream, so the report is
+// reproducible.
+func Analyze(source func() *rand.Rand, home, away soccer.GameLineup, change Change, rules Rules) (Report, error) {
+	if source == nil {
+		return Report{}, soccer.ErrNilRandSource
+	}
+	if rules.Simulations < 1 {
+		return Report{}, fmt.Errorf("%w: %d", forecast.ErrNoMatches, rules.Simulations)
+	}
+	newHome, newAway, err := change.Apply(home, away)
+	if err != nil {
+		return Report{}, err
+	}
+
+	rep := Report{Change: change}
+	if rep.Original, err = replay(source(), home, away); err != nil {
+		return Report{}, err
+	}
+	if rep.Changed, err = replay(source(), newHome, newAway); err != nil {
+		return Report{}, err
+	}
+	rep.InStep = sameDrawPlan(home, away, newHome, newAway) &&
+		sameInjuries(home, newHome, rep.Original.Injuries.HomeTeamInjuries, rep.Changed.Injuries.HomeTeamInjuries) &&
+		sameInjuries(away, newAway, rep.Original.Injuries.AwayTeamInjuries, rep.Changed.Injuries.AwayTeamInjuries)
+
+	seed := source().Int63()
+	if rep.Before, err = forecast.Simulate(seed, home, away, rules.Simulations); err != nil {
+		return Report{}, err
+	}
+	if rep.After, err = forecast.Simulate(seed, newHome, newAway, rules.Simulations); err != nil {
+		return Report{}, err
+	}
+	if change.Team == soccer.TeamTypeAway {
+		rep.Before, rep.After = rep.Before.Reversed(), rep.After.Reversed()
+	}
+	rep.WinDelta = rep.After.Win - rep.Before.Win
+	return rep, nil
+}
+
+func replay(r *rand.Rand, home, away soccer.GameLineup) (Replay, error) {
+	events, injuries, err := soccer.RunGameWithSeed(r, home, away)
+	if err != nil {
+		return Replay{}, err
+	}
+	return Replay{Events: events, Injuries: injuries, Stats: soccer.CreateGameStats(events)}, nil
+}
+
+// sameDrawPlan reports whether the two versions of the match draw the same
+// chance count the same way and take the same attacker draws on set
+// pieces: the draws that depend on the lineups rather than on how the match
+// goes.
+func sameDrawPlan(home, away, newHome, newAway soccer.GameLineup) bool {
+	for _, pair := range [][2]soccer.GameLineup{{home, newHome}, {away, newAway}} {
+		before, after := pair[0], pair[1]
+		if style(before) != style(after) || chanceVolume(before) != chanceVolume(after) {
+			return false
+		}
+		if takerOnPitch(before) != takerOnPitch(after) {
+			return false
+		}
+	}
+	return true
+}
+
+func style(l soccer.GameLineup) soccer.FormationStyle {
+	f, err := soccer.LookupFormation(l.Team.Formation)
+	if err != nil {
+		return ""
+	}
+	return f.Style
+}
+
+// chanceVolume is the side's tempo factor on the match's chance count.
+func chanceVolume(l soccer.GameLineup) float64 {
+	for _, e := range soccer.TacticEffects(l.Team.Tactics) {
+		if e.Target == soccer.TacticTargetChanceVolume {
+			return e.Factor
+		}
+	}
+	return 1
+}
+
+func takerOnPitch(l soccer.GameLineup) bool {
+	id := l.Team.Tactics.SetPieceTaker
+	return id != "" && slices.ContainsFunc(l.Players, func(p soccer.SelectedPlayer) bool { return p.ID == id })
+}
+
+// sameInjuries reports whether a side picked up the same injuries in both
+// matches: the same injury, in the same lineup slot, at the same minute.
+// Their draws then line up too.
+func sameInjuries(before, after soccer.GameLineup, a, b []soccer.InjuryEvent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	slot := func(l soccer.GameLineup, id string) int {
+		return slices.IndexFunc(l.Players, func(p soccer.SelectedPlayer) bool { return p.ID == id })
+	}
+	for i := range a {
+		if a[i].Minute != b[i].Minute || a[i].Injury.Name != b[i].Injury.Name || slot(before, a[i].PlayerID) != slot(after, b[i].PlayerID) {
+			return false
+		}
+	}
+	return true
+}
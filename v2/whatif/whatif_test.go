@@ -0,0 +1,137 @@
+package whatif_test
+
+import (
+	"math/rand"
+	"testing"
+
+	soccer "github.com/stein-f/oink-soccer-common/v2"
+	"github.com/stein-f/oink-soccer-common/v2/testdata"
+	"github.com/stein-f/oink-soccer-common/v2/whatif"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func source(seed int64) func() *rand.Rand {
+	return func() *rand.Rand { return rand.New(rand.NewSource(seed)) }
+}
+
+func rules() whatif.Rules {
+	return whatif.Rules{Simulations: 200}
+}
+
+func TestAnalyze_LikeForLikeSwapReplaysTheMatch(t *testing.T) {
+	home, away := testdata.StrongTeam(soccer.FormationTypeDiamond), testdata.WeakTeam(soccer.FormationTypeDiamond)
+	home.Team.Tactics.SetPieceTaker = "4"
+	twin := home.Players[3]
+	// "4b" sorts where "4" did, so the attacker draws pick the same slots.
+	twin.ID, twin.Name = "4b", "4b"
+
+	for seed := int64(1); seed <= 20; seed++ {
+		rep, err := whatif.Analyze(source(seed), home, away, whatif.Change{Team: soccer.TeamTypeHome, Out: "4", In: &twin}, rules())
+		require.NoError(t, err)
+
+		assert.True(t, rep.InStep, "seed %d", seed)
+		require.Len(t, rep.Changed.Events, len(rep.Original.Events), "seed %d", seed)
+		for i, e := range rep.Original.Events {
+			c := rep.Changed.Events[i]
+			assert.Equal(t, []any{e.Type, e.Minute, e.ChanceType, e.XG}, []any{c.Type, c.Minute, c.ChanceType, c.XG}, "seed %d event %d", seed, i)
+		}
+		assert.Equal(t, rep.Original.Stats.HomeTeamStats.Goals, rep.Changed.Stats.HomeTeamStats.Goals)
+		assert.Equal(t, rep.Before, rep.After, "the same dice over every seed")
+		assert.Zero(t, rep.WinDelta)
+	}
+}
+
+func TestAnalyze_BetterPlayerRaisesTheWinProbability(t *testing.T) {
+	home, away := testdata.StrongTeam(soccer.FormationTypeDiamond), testdata.WeakTeam(soccer.FormationTypeDiamond)
+	star := testdata.StrongTeam(soccer.FormationTypeDiamond).Players[4]
+	star.ID = "star"
+
+	rep, err := whatif.Analyze(source(1), home, away, whatif.Change{Team: soccer.TeamTypeAway, Out: "10", In: &star}, rules())
+	require.NoError(t, err)
+
+	assert.Equal(t, "star", rep.Change.In.ID)
+	assert.Less(t, rep.Before.Win, 0.5, "from the away side's view")
+	assert.Positive(t, rep.WinDelta)
+	assert.InDelta(t, rep.After.Win-rep.Before.Win, rep.WinDelta, 1e-12)
+	assert.Less(t, rep.After.GoalsAgainst, rep.Before.GoalsAgainst+rep.Before.GoalsFor)
+
+	again, err := whatif.Analyze(source(1), home, away, whatif.Change{Team: soccer.TeamTypeAway, Out: "10", In: &star}, rules())
+	require.NoError(t, err)
+	assert.Equal(t, rep, again)
+}
+
+func TestAnalyze_OutOfStep(t *testing.T) {
+	home, away := testdata.StrongTeam(soccer.FormationTypeDiamond), testdata.WeakTeam(soccer.FormationTypeDiamond)
+
+	// The Y is an attacking formation: a different chance count.
+	rep, err := whatif.Analyze(source(1), home, away, whatif.Change{
+		Team:      soccer.TeamTypeHome,
+		Formation: soccer.FormationTypeY,
+		Positions: map[string]soccer.PlayerPosition{"3": soccer.PlayerPositionAttack},
+	}, rules())
+	require.NoError(t, err)
+	assert.False(t, rep.InStep)
+
+	fast := soccer.Tactics{Tempo: soccer.TempoLevelFast}
+	rep, err = whatif.Analyze(source(1), home, away, whatif.Change{Team: soccer.TeamTypeAway, Tactics: &fast}, rules())
+	require.NoError(t, err)
+	assert.False(t, rep.InStep)
+
+	// The Box is balanced like the Diamond; nothing else moves the draws
+	// but injuries.
+	rep, err = whatif.Analyze(source(1), home, away, whatif.Change{
+		Team:      soccer.TeamTypeHome,
+		Formation: soccer.FormationTypeBox,
+		Positions: map[string]soccer.PlayerPosition{"3": soccer.PlayerPositionDefense, "4": soccer.PlayerPositionAttack},
+	}, rules())
+	require.NoError(t, err)
+	assert.Equal(t, len(rep.Original.Injuries.HomeTeamInjuries) == len(rep.Changed.Injuries.HomeTeamInjuries) &&
+		len(rep.Original.Injuries.AwayTeamInjuries) == len(rep.Changed.Injuries.AwayTeamInjuries), rep.InStep)
+}
+
+func TestChange_Apply(t *testing.T) {
+	home, away := testdata.StrongTeam(soccer.FormationTypeDiamond), testdata.WeakTeam(soccer.FormationTypeDiamond)
+	home.Players[2].Role = soccer.PlayerRoleCaptain
+	home.Team.Tactics = soccer.Tactics{SetPieceTaker: "3", MarkerID: "3"}
+	in := away.Players[2]
+	in.ID = "new"
+
+	h, a, err := whatif.Change{Team: soccer.TeamTypeHome, Out: "3", In: &in}.Apply(home, away)
+	require.NoError(t, err)
+	assert.Equal(t, away, a)
+	assert.Equal(t, "new", h.Players[2].ID)
+	assert.Equal(t, home.Players[2].SelectedPosition, h.Players[2].SelectedPosition)
+	assert.Equal(t, soccer.PlayerRoleCaptain, h.Players[2].Role)
+	assert.Equal(t, "new", h.Team.Tactics.SetPieceTaker)
+	assert.Equal(t, "new", h.Team.Tactics.MarkerID)
+	assert.Equal(t, "3", home.Players[2].ID, "the input is untouched")
+}
+
+func TestAnalyze_Errors(t *testing.T) {
+	home, away := testdata.StrongTeam(soccer.FormationTypeDiamond), testdata.WeakTeam(soccer.FormationTypeDiamond)
+	in := away.Players[0]
+	in.ID = "new"
+	slow := soccer.Tactics{Tempo: soccer.TempoLevelSlow}
+
+	_, err := whatif.Analyze(nil, home, away, whatif.Change{Team: soccer.TeamTypeHome, Tactics: &slow}, rules())
+	assert.ErrorIs(t, err, soccer.ErrNilRandSource)
+
+	for name, change := range map[string]whatif.Change{
+		"nothing":     {Team: soccer.TeamTypeHome},
+		"two changes": {Team: soccer.TeamTypeHome, Tactics: &slow, Formation: soccer.FormationTypeBox},
+		"half a swap": {Team: soccer.TeamTypeHome, Out: "1"},
+		"no team":     {Tactics: &slow},
+		"positions":   {Team: soccer.TeamTypeHome, Tactics: &slow, Positions: map[string]soccer.PlayerPosition{}},
+		"already in":  {Team: soccer.TeamTypeHome, Out: "1", In: &home.Players[1]},
+	} {
+		_, err = whatif.Analyze(source(1), home, away, change, rules())
+		assert.ErrorIs(t, err, whatif.ErrInvalidChange, name)
+	}
+
+	_, err = whatif.Analyze(source(1), home, away, whatif.Change{Team: soccer.TeamTypeAway, Out: "1", In: &in}, rules())
+	assert.ErrorIs(t, err, whatif.ErrUnknownPlayer)
+
+	_, err = whatif.Analyze(source(1), home, away, whatif.Change{Team: soccer.TeamTypeHome, Formation: "Christmas Tree"}, rules())
+	assert.ErrorIs(t, err, soccer.ErrUnknownFormation)
+}